@@ -0,0 +1,70 @@
+//go:build !race
+
+// The race detector allocates on its own, so allocations are only measured
+// without it.
+
+package solaceotlpreceiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// allocBudgets bound the allocations decodePayload may add on top of
+// unmarshaling the raw OTLP protobuf payload. A garbage collection during the
+// measurement may empty the buffer pool, which costs one buffer.
+var allocBudgets = map[string]float64{
+	formatProto:       0,
+	formatBase64Proto: 1,
+	"gzip_proto":      4,
+}
+
+// TestDecodePayload_AllocationBudget keeps the decoding overhead of every wire
+// format within allocBudgets, measured against a plain protobuf unmarshal.
+func TestDecodePayload_AllocationBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("allocation measurement skipped in short mode")
+	}
+	for _, size := range benchSizes[:3] {
+		raw := sizedLogs(t, size.size)
+		baseline := testing.AllocsPerRun(20, func() {
+			_, _ = (&plog.ProtoUnmarshaler{}).UnmarshalLogs(raw)
+		})
+		for _, encoding := range benchEncodings {
+			payload := encoding.encode(t, raw)
+			t.Run(encoding.name+"/"+size.name, func(t *testing.T) {
+				want := baseline + allocBudgets[encoding.name]
+				if bytes.HasPrefix(payload, gzipMagic) {
+					// flate allocates Huffman tables per block; that is not ours to save
+					want += inflateAllocs(t, payload)
+				}
+				allocs := testing.AllocsPerRun(20, func() {
+					if _, err := decodePayload(payload); err != nil {
+						t.Fatal(err)
+					}
+				})
+				assert.LessOrEqual(t, allocs, want,
+					"decoding allocates %v times, unmarshaling %v times", allocs, baseline)
+			})
+		}
+	}
+}
+
+// inflateAllocs returns the allocations of inflating payload with a reused gzip reader
+func inflateAllocs(t *testing.T, payload []byte) float64 {
+	zr, err := gzip.NewReader(bytes.NewReader(payload))
+	require.NoError(t, err)
+	r := bytes.NewReader(payload)
+	return testing.AllocsPerRun(20, func() {
+		r.Reset(payload)
+		require.NoError(t, zr.Reset(r))
+		_, err := io.Copy(io.Discard, zr)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,74 @@
+package solaceotlpreceiver
+
+import (
+	"maps"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pipeline"
+	"go.opentelemetry.io/collector/pipeline/xpipeline"
+	"go.uber.org/zap"
+	"solace.dev/go/messaging/pkg/solace/message"
+
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/topic"
+)
+
+// resourceAttributes returns the configured resource attributes of a message:
+// the static attributes of its queue and topic, overridden by the levels of
+// the topic template
+func (r *Receiver) resourceAttributes(q *queueBinding, msg message.InboundMessage) map[string]string {
+	cfg := r.config.ResourceAttributes
+	if len(cfg.Static) == 0 && r.topicTemplate == nil {
+		return nil
+	}
+	destination := msg.GetDestinationName()
+	attributes := map[string]string{}
+	for _, static := range cfg.Static {
+		if (static.Queue != "" && static.Queue == q.name) ||
+			(static.Subscription != "" && topic.Matches(static.Subscription, destination)) {
+			maps.Copy(attributes, static.Attributes)
+		}
+	}
+	if r.topicTemplate != nil {
+		if levels, ok := r.topicTemplate.Extract(destination); ok {
+			maps.Copy(attributes, levels)
+		} else {
+			r.logger.Debug("Topic does not match the topic template", zap.String("topic", destination))
+		}
+	}
+	return attributes
+}
+
+// putResourceAttributes adds attributes to every resource of data. Attributes
+// of the payload are kept unless overwrite is set.
+func putResourceAttributes(data decoded, attributes map[string]string, overwrite bool) {
+	if len(attributes) == 0 {
+		return
+	}
+	put := func(resource pcommon.Resource) {
+		dst := resource.Attributes()
+		for key, value := range attributes {
+			if _, exists := dst.Get(key); exists && !overwrite {
+				continue
+			}
+			dst.PutStr(key, value)
+		}
+	}
+	switch data.signal {
+	case pipeline.SignalLogs:
+		for i := 0; i < data.logs.ResourceLogs().Len(); i++ {
+			put(data.logs.ResourceLogs().At(i).Resource())
+		}
+	case pipeline.SignalTraces:
+		for i := 0; i < data.traces.ResourceSpans().Len(); i++ {
+			put(data.traces.ResourceSpans().At(i).Resource())
+		}
+	case pipeline.SignalMetrics:
+		for i := 0; i < data.metrics.ResourceMetrics().Len(); i++ {
+			put(data.metrics.ResourceMetrics().At(i).Resource())
+		}
+	case xpipeline.SignalProfiles:
+		for i := 0; i < data.profiles.ResourceProfiles().Len(); i++ {
+			put(data.profiles.ResourceProfiles().At(i).Resource())
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package solaceotlpreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+)
+
+func TestHandleMessage_ResourceAttributes(t *testing.T) {
+	const payload = `{"resource": {"attributes": {"service.name": "from-payload"}}, "body": "hello"}`
+	for precedence, wantService := range map[string]string{
+		"":                             "from-payload",
+		solaceconfig.PrecedencePayload: "from-payload",
+		solaceconfig.PrecedenceTopic:   "checkout",
+	} {
+		t.Run(precedence, func(t *testing.T) {
+			broker := newTestBroker()
+			cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+			cfg.ResourceAttributes = solaceconfig.ResourceAttributesConfig{
+				TopicTemplate: "otel/{deployment.environment}/{team}/{service.name}/*",
+				Precedence:    precedence,
+				Static: []solaceconfig.StaticAttributesConfig{
+					{Queue: testQueue, Attributes: map[string]string{"messaging.queue": testQueue, "team": "static"}},
+					{Subscription: "otel/prod/>", Attributes: map[string]string{"cloud.region": "eu-central-1"}},
+					{Subscription: "otel/dev/>", Attributes: map[string]string{"cloud.region": "local"}},
+				},
+			}
+			sink := new(consumertest.LogsSink)
+			r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, sink, nil, nil, broker.NewMessagingService())
+			require.NoError(t, err)
+			startReceiver(t, r)
+
+			broker.Publish("otel/prod/payments/checkout/logs", []byte(payload))
+			require.Eventually(t, func() bool { return broker.Acked(testQueue) == 1 }, time.Second, time.Millisecond)
+			got := sink.AllLogs()[0].ResourceLogs().At(0).Resource().Attributes().AsRaw()
+			assert.Equal(t, map[string]any{
+				"service.name":           wantService,
+				"deployment.environment": "prod",
+				"team":                   "payments",
+				"messaging.queue":        testQueue,
+				"cloud.region":           "eu-central-1",
+			}, got)
+		})
+	}
+}
+
+func TestHandleMessage_TopicNotMatchingTemplate(t *testing.T) {
+	broker := newTestBroker()
+	cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+	cfg.ResourceAttributes.TopicTemplate = "otel/{env}/{team}/{service.name}/*"
+	sink := new(consumertest.LogsSink)
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, sink, nil, nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	startReceiver(t, r)
+
+	broker.Publish("otel/logs", []byte(`{"body": "hello"}`))
+	require.Eventually(t, func() bool { return broker.Acked(testQueue) == 1 }, time.Second, time.Millisecond)
+	assert.Zero(t, sink.AllLogs()[0].ResourceLogs().At(0).Resource().Attributes().Len())
+}
+
+func TestConfigValidate_ResourceAttributes(t *testing.T) {
+	for name, attributes := range map[string]solaceconfig.ResourceAttributesConfig{
+		"template":               {TopicTemplate: "otel/{a}/{a}"},
+		"precedence":             {Precedence: "static"},
+		"queue and subscription": {Static: []solaceconfig.StaticAttributesConfig{{Queue: "q", Subscription: "s", Attributes: map[string]string{"a": "b"}}}},
+		"no target":              {Static: []solaceconfig.StaticAttributesConfig{{Attributes: map[string]string{"a": "b"}}}},
+		"no attributes":          {Static: []solaceconfig.StaticAttributesConfig{{Queue: "q"}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+			cfg.ResourceAttributes = attributes
+			assert.ErrorContains(t, cfg.Validate(), "resource_attributes: ")
+		})
+	}
+	_, err := NewReceiver(receivertest.NewNopSettings(typeStr), &solaceconfig.Config{
+		ResourceAttributes: solaceconfig.ResourceAttributesConfig{TopicTemplate: "otel/{"},
+	}, nil, nil, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,180 @@
+package solaceotlpreceiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/testdata"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/solacetest"
+)
+
+// benchSizes are the payload sizes of the benchmarks
+var benchSizes = []struct {
+	name string
+	size int
+}{
+	{"1KB", 1 << 10},
+	{"16KB", 16 << 10},
+	{"256KB", 256 << 10},
+	{"1MB", 1 << 20},
+	{"4MB", 4 << 20},
+}
+
+// benchEncodings turn an OTLP protobuf payload into each supported wire format
+var benchEncodings = []struct {
+	name   string
+	encode func(testing.TB, []byte) []byte
+}{
+	{formatProto, func(_ testing.TB, raw []byte) []byte { return raw }},
+	{formatBase64Proto, func(_ testing.TB, raw []byte) []byte { return encodeBase64(raw) }},
+	{"gzip_proto", gzipPayload},
+}
+
+// sizedLogs returns OTLP protobuf logs of at least size bytes
+func sizedLogs(tb testing.TB, size int) []byte {
+	rnd := rand.New(rand.NewSource(int64(size)))
+	logs := plog.NewLogs()
+	records := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	marshaler := &plog.ProtoMarshaler{}
+	for marshaler.LogsSize(logs) < size {
+		for i := 0; i < 16; i++ {
+			record := records.AppendEmpty()
+			record.SetTimestamp(pcommon.Timestamp(rnd.Int63()))
+			record.SetSeverityNumber(plog.SeverityNumberInfo)
+			record.Body().SetStr(fmt.Sprintf("request %d served in %dms", rnd.Int63(), rnd.Intn(1000)))
+			record.Attributes().PutStr("http.route", "/api/v1/items")
+			record.Attributes().PutInt("http.status_code", 200)
+			record.SetTraceID(testdata.RandomTraceID(rnd))
+			record.SetSpanID(testdata.RandomSpanID(rnd))
+		}
+	}
+	raw, err := marshaler.MarshalLogs(logs)
+	require.NoError(tb, err)
+	return raw
+}
+
+func gzipPayload(tb testing.TB, raw []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(raw)
+	require.NoError(tb, err)
+	require.NoError(tb, zw.Close())
+	return buf.Bytes()
+}
+
+func BenchmarkDecodePayload(b *testing.B) {
+	for _, size := range benchSizes {
+		raw := sizedLogs(b, size.size)
+		for _, encoding := range benchEncodings {
+			payload := encoding.encode(b, raw)
+			b.Run(encoding.name+"/"+size.name, func(b *testing.B) {
+				b.SetBytes(int64(len(raw)))
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := decodePayload(payload); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkHandleMessage(b *testing.B) {
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), newTestConfig(""), consumertest.NewNop(), consumertest.NewNop(), nil)
+	require.NoError(b, err)
+	for _, size := range benchSizes {
+		msg := solacetest.NewMessage("otel/logs", sizedLogs(b, size.size))
+		b.Run(size.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				r.HandleMessage(msg)
+			}
+		})
+	}
+}
+
+// TestDecodeProto_SniffsSignal checks that protobuf payloads are attributed to
+// the signal they carry by the fields of their first record
+func TestDecodeProto_SniffsSignal(t *testing.T) {
+	sniffed := func(t *testing.T, payload []byte, want string) {
+		require.NotZero(t, sniffProtoSignals(payload))
+		d, ok := decodeProto(payload)
+		require.True(t, ok)
+		assert.Equal(t, want, d.signal.String())
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		logs, err := (&plog.ProtoMarshaler{}).MarshalLogs(testdata.RandomLogs(rnd))
+		require.NoError(t, err)
+		assert.Equal(t, sniffLogs, sniffProtoSignals(logs))
+		sniffed(t, logs, "logs")
+
+		traces, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(testdata.RandomTraces(rnd))
+		require.NoError(t, err)
+		assert.Equal(t, sniffTraces, sniffProtoSignals(traces))
+		sniffed(t, traces, "traces")
+
+		metrics, err := (&pmetric.ProtoMarshaler{}).MarshalMetrics(testdata.RandomMetrics(rnd))
+		require.NoError(t, err)
+		sniffed(t, metrics, "metrics")
+	}
+	profiles, err := (&pprofile.ProtoMarshaler{}).MarshalProfiles(newTestProfiles())
+	require.NoError(t, err)
+	assert.Equal(t, sniffProfiles, sniffProtoSignals(profiles))
+	sniffed(t, profiles, "profiles")
+	assert.Zero(t, sniffProtoSignals(nil))
+}
+
+func TestMessagePayload_UnwrapsSDTString(t *testing.T) {
+	for name, tc := range map[string]struct {
+		payload []byte
+		want    []byte
+	}{
+		"string":        {[]byte{0x1f, 0, 0, 0, 9, 'a', 'b', 'c', 0}, []byte("abc")},
+		"empty string":  {[]byte{0x1f, 0, 0, 0, 6, 0}, []byte{}},
+		"short length":  {[]byte{0x1c, 5, 'a', 'b', 0}, []byte("ab")},
+		"wrong length":  {[]byte{0x1f, 0, 0, 0, 8, 'a', 0}, []byte{0x1f, 0, 0, 0, 8, 'a', 0}},
+		"gzip":          {[]byte{0x1f, 0x8b, 8, 0}, []byte{0x1f, 0x8b, 8, 0}},
+		"protobuf":      {[]byte{0x0a, 0x02, 0x0a, 0x00}, []byte{0x0a, 0x02, 0x0a, 0x00}},
+		"base64 string": {[]byte("CgIKAA=="), []byte("CgIKAA==")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, ok := messagePayload(solacetest.NewMessage("otel/logs", tc.payload))
+			require.True(t, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDecodePayload_Base64WithLineBreaks(t *testing.T) {
+	raw := sizedLogs(t, 1<<10)
+	encoded := base64.StdEncoding.EncodeToString(raw)
+	var wrapped bytes.Buffer
+	for len(encoded) > 76 {
+		wrapped.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	wrapped.WriteString(encoded)
+
+	got, err := decodePayload(wrapped.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, formatBase64Proto, got.format)
+	want, err := (&plog.ProtoUnmarshaler{}).UnmarshalLogs(raw)
+	require.NoError(t, err)
+	assert.Equal(t, want, got.logs)
+}
@@ -0,0 +1,219 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pipeline"
+	"go.opentelemetry.io/collector/pipeline/xpipeline"
+	"go.uber.org/zap"
+
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/wal"
+)
+
+// bufferStorageName names the storage client of the write-ahead buffer
+const bufferStorageName = "buffer"
+
+// Signal tags of buffered records: one byte followed by the OTLP protobuf
+const (
+	recordLogs     byte = 'l'
+	recordTraces   byte = 't'
+	recordMetrics  byte = 'm'
+	recordProfiles byte = 'p'
+)
+
+// openBuffer opens the write-ahead buffer in the configured storage extension
+// and starts feeding it to the pipeline
+func (r *Receiver) openBuffer(ctx context.Context, host component.Host) error {
+	cfg := r.config.Buffer
+	if !cfg.Enabled {
+		return nil
+	}
+	client, err := r.storageClient(ctx, host, *cfg.Storage, bufferStorageName)
+	if err != nil {
+		return err
+	}
+	buffer, err := wal.Open(ctx, client, cfg.MaxBatches)
+	if err != nil {
+		return errors.Join(err, client.Close(ctx))
+	}
+	r.buffer, r.bufferClient = buffer, client
+	r.logger.Info("Opened write-ahead buffer", zap.Int("messages", buffer.Len()))
+	r.telemetry.RecordBuffered(ctx, buffer.Len())
+
+	feedCtx, cancel := context.WithCancel(context.Background())
+	r.cancelFeed = cancel
+	r.feedWg.Add(1)
+	go func() {
+		defer r.feedWg.Done()
+		r.feedBuffer(feedCtx)
+	}()
+	return nil
+}
+
+// closeBuffer stops feeding the buffer and closes its storage client.
+// Messages not yet fed stay in the buffer for the next start.
+func (r *Receiver) closeBuffer(ctx context.Context) error {
+	if r.buffer == nil {
+		return nil
+	}
+	r.cancelFeed()
+	r.feedWg.Wait()
+	err := r.bufferClient.Close(ctx)
+	r.buffer, r.bufferClient = nil, nil
+	return err
+}
+
+// bufferData appends decoded data to the write-ahead buffer. A full buffer
+// returns a transient error, so that the broker keeps the message.
+func (r *Receiver) bufferData(data decoded) error {
+	record, err := marshalRecord(data)
+	if err != nil {
+		return consumererror.NewPermanent(err)
+	}
+	if err := r.buffer.Append(context.Background(), record); err != nil {
+		return err
+	}
+	r.telemetry.RecordBuffered(context.Background(), r.buffer.Len())
+	return nil
+}
+
+// feedBuffer sends buffered messages to the pipeline in order until ctx is
+// done. A message the pipeline fails to consume is retried with backoff
+// unless the error is permanent.
+func (r *Receiver) feedBuffer(ctx context.Context) {
+	retry := r.config.Buffer.Retry
+	interval := retry.InitialInterval
+	for {
+		err := r.feedFirst(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case errors.Is(err, errBufferEmpty):
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.buffer.Ready():
+			}
+			continue
+		case err != nil:
+			r.logger.Warn("Failed to feed buffered message, retrying",
+				zap.Duration("retry_in", interval),
+				zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+			interval = nextInterval(interval, retry)
+			continue
+		}
+		interval = retry.InitialInterval
+	}
+}
+
+// errBufferEmpty is returned by feedFirst if the buffer holds no message
+var errBufferEmpty = errors.New("buffer is empty")
+
+// feedFirst sends the first buffered message to the pipeline and removes it
+// once it is consumed or rejected permanently
+func (r *Receiver) feedFirst(ctx context.Context) error {
+	record, ok, err := r.buffer.First(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errBufferEmpty
+	}
+	data, err := unmarshalRecord(record)
+	if err == nil && !r.serves(data.signal) {
+		err = consumererror.NewPermanent(fmt.Errorf("no %s pipeline", data.signal))
+	}
+	if err == nil {
+		err = r.consume(ctx, data)
+	}
+	if err != nil && !consumererror.IsPermanent(err) && !errors.Is(err, errInvalidRecord) {
+		return err
+	}
+	if err != nil {
+		r.logger.Error("Dropping buffered message", zap.String("signal", data.signal.String()), zap.Error(err))
+		r.telemetry.RecordBufferDropped(ctx, data.signal.String())
+	}
+	if err := r.buffer.RemoveFirst(ctx); err != nil {
+		return err
+	}
+	r.telemetry.RecordBuffered(ctx, r.buffer.Len())
+	return nil
+}
+
+// errInvalidRecord is returned for buffered records that cannot be decoded
+var errInvalidRecord = errors.New("invalid buffered record")
+
+// marshalRecord encodes decoded data as buffered record
+func marshalRecord(data decoded) ([]byte, error) {
+	var (
+		tag     byte
+		payload []byte
+		err     error
+	)
+	switch data.signal {
+	case pipeline.SignalLogs:
+		tag = recordLogs
+		payload, err = (&plog.ProtoMarshaler{}).MarshalLogs(data.logs)
+	case pipeline.SignalTraces:
+		tag = recordTraces
+		payload, err = (&ptrace.ProtoMarshaler{}).MarshalTraces(data.traces)
+	case pipeline.SignalMetrics:
+		tag = recordMetrics
+		payload, err = (&pmetric.ProtoMarshaler{}).MarshalMetrics(data.metrics)
+	case xpipeline.SignalProfiles:
+		tag = recordProfiles
+		payload, err = (&pprofile.ProtoMarshaler{}).MarshalProfiles(data.profiles)
+	default:
+		return nil, fmt.Errorf("cannot buffer signal %s", data.signal)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s for the buffer: %w", data.signal, err)
+	}
+	return append([]byte{tag}, payload...), nil
+}
+
+// unmarshalRecord decodes a buffered record
+func unmarshalRecord(record []byte) (decoded, error) {
+	if len(record) == 0 {
+		return decoded{}, fmt.Errorf("%w: record is missing", errInvalidRecord)
+	}
+	var (
+		data decoded
+		err  error
+	)
+	payload := record[1:]
+	switch record[0] {
+	case recordLogs:
+		data.signal = pipeline.SignalLogs
+		data.logs, err = (&plog.ProtoUnmarshaler{}).UnmarshalLogs(payload)
+	case recordTraces:
+		data.signal = pipeline.SignalTraces
+		data.traces, err = (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(payload)
+	case recordMetrics:
+		data.signal = pipeline.SignalMetrics
+		data.metrics, err = (&pmetric.ProtoUnmarshaler{}).UnmarshalMetrics(payload)
+	case recordProfiles:
+		data.signal = xpipeline.SignalProfiles
+		data.profiles, err = (&pprofile.ProtoUnmarshaler{}).UnmarshalProfiles(payload)
+	default:
+		return decoded{}, fmt.Errorf("%w: unknown signal tag %q", errInvalidRecord, record[0])
+	}
+	if err != nil {
+		return decoded{}, fmt.Errorf("%w: %w", errInvalidRecord, err)
+	}
+	return data, nil
+}
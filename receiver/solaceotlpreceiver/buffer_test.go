@@ -0,0 +1,216 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/storagetest"
+)
+
+// flakyLogs is a logs consumer that fails with err while down and otherwise
+// forwards to a sink
+type flakyLogs struct {
+	consumer.Logs
+	sink *consumertest.LogsSink
+	down atomic.Bool
+}
+
+func newFlakyLogs(t *testing.T, err error) *flakyLogs {
+	f := &flakyLogs{sink: new(consumertest.LogsSink)}
+	f.down.Store(true)
+	next, nextErr := consumer.NewLogs(func(ctx context.Context, ld plog.Logs) error {
+		if f.down.Load() {
+			return err
+		}
+		return f.sink.ConsumeLogs(ctx, ld)
+	})
+	require.NoError(t, nextErr)
+	f.Logs = next
+	return f
+}
+
+// newBufferTest returns a test configuration with the write-ahead buffer in
+// storage and a host with that storage extension
+func newBufferTest(storage *storagetest.Storage) (*solaceconfig.Config, extensionHost) {
+	cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+	cfg.Buffer.Enabled = true
+	cfg.Buffer.Storage = &fileStorageID
+	cfg.Buffer.Retry = solaceconfig.ConnectRetryConfig{InitialInterval: time.Millisecond, MaxInterval: 5 * time.Millisecond}
+	return cfg, extensionHost{extensions: map[component.ID]component.Component{fileStorageID: storage}}
+}
+
+func TestBuffer_AbsorbsOutage(t *testing.T) {
+	broker := newTestBroker()
+	cfg, host := newBufferTest(storagetest.New())
+	logs := newFlakyLogs(t, assert.AnError)
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, logs, nil, nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), host))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+
+	for _, body := range []string{"first", "second", "third"} {
+		broker.Publish("otel/logs", []byte(`{"body": "`+body+`"}`))
+	}
+	require.Eventually(t, func() bool { return broker.Acked(testQueue) == 3 }, time.Second, time.Millisecond,
+		"messages are acknowledged while the pipeline is down")
+	assert.Zero(t, logs.sink.LogRecordCount())
+
+	logs.down.Store(false)
+	require.Eventually(t, func() bool { return logs.sink.LogRecordCount() == 3 }, time.Second, time.Millisecond)
+	var bodies []string
+	for _, ld := range logs.sink.AllLogs() {
+		bodies = append(bodies, ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+	}
+	assert.Equal(t, []string{"first", "second", "third"}, bodies, "buffered messages keep their order")
+	assert.Zero(t, r.buffer.Len())
+}
+
+func TestBuffer_SurvivesRestart(t *testing.T) {
+	broker := newTestBroker()
+	storage := storagetest.New()
+	cfg, host := newBufferTest(storage)
+	down := newFlakyLogs(t, assert.AnError)
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, down, consumertest.NewErr(assert.AnError), nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), host))
+	broker.Publish("otel/logs", []byte(testLogsPayload))
+	broker.Publish("otel/traces", []byte(testTracesPayload))
+	require.Eventually(t, func() bool { return broker.Acked(testQueue) == 2 }, time.Second, time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	logsSink := new(consumertest.LogsSink)
+	tracesSink := new(consumertest.TracesSink)
+	restarted, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, logsSink, tracesSink, nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	require.NoError(t, restarted.Start(context.Background(), host))
+	t.Cleanup(func() { require.NoError(t, restarted.Shutdown(context.Background())) })
+	require.Eventually(t, func() bool {
+		return logsSink.LogRecordCount() == 1 && tracesSink.SpanCount() == 1
+	}, time.Second, time.Millisecond)
+	assert.Zero(t, broker.Pending(testQueue)+broker.Unacked(testQueue))
+}
+
+func TestBuffer_FullReleasesToBroker(t *testing.T) {
+	broker := newTestBroker()
+	cfg, host := newBufferTest(storagetest.New())
+	cfg.Buffer.MaxBatches = 1
+	logs := newFlakyLogs(t, assert.AnError)
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, logs, nil, nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), host))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+
+	broker.Publish("otel/logs", []byte(testLogsPayload))
+	broker.Publish("otel/logs", []byte(testLogsPayload))
+	require.Eventually(t, func() bool { return broker.Acked(testQueue) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, 1, broker.Pending(testQueue)+broker.Unacked(testQueue), "the broker keeps messages the buffer has no room for")
+
+	logs.down.Store(false)
+	require.Eventually(t, func() bool { return broker.Acked(testQueue) == 2 }, time.Second, time.Millisecond)
+	require.Eventually(t, func() bool { return logs.sink.LogRecordCount() == 2 }, time.Second, time.Millisecond)
+}
+
+func TestBuffer_DropsPermanentFailures(t *testing.T) {
+	broker := newTestBroker()
+	cfg, host := newBufferTest(storagetest.New())
+	logs := newFlakyLogs(t, consumererror.NewPermanent(assert.AnError))
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, logs, nil, nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), host))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+
+	broker.Publish("otel/logs", []byte(testLogsPayload))
+	require.Eventually(t, func() bool { return broker.Acked(testQueue) == 1 }, time.Second, time.Millisecond)
+	require.Eventually(t, func() bool { return r.buffer.Len() == 0 }, time.Second, time.Millisecond)
+	assert.Zero(t, logs.sink.LogRecordCount())
+}
+
+func TestBuffer_DropsSignalsWithoutPipeline(t *testing.T) {
+	broker := newTestBroker()
+	storage := storagetest.New()
+	cfg, host := newBufferTest(storage)
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, newFlakyLogs(t, assert.AnError),
+		consumertest.NewErr(assert.AnError), nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), host))
+	broker.Publish("otel/traces", []byte(testTracesPayload))
+	broker.Publish("otel/logs", []byte(testLogsPayload))
+	require.Eventually(t, func() bool { return broker.Acked(testQueue) == 2 }, time.Second, time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	tel := componenttest.NewTelemetry()
+	t.Cleanup(func() { require.NoError(t, tel.Shutdown(context.Background())) })
+	settings := receivertest.NewNopSettings(typeStr)
+	settings.TelemetrySettings = tel.NewTelemetrySettings()
+	logsSink := new(consumertest.LogsSink)
+	restarted, err := NewReceiver(settings, cfg, logsSink, nil, nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	require.NoError(t, restarted.Start(context.Background(), host))
+	t.Cleanup(func() { require.NoError(t, restarted.Shutdown(context.Background())) })
+	require.Eventually(t, func() bool { return logsSink.LogRecordCount() == 1 }, time.Second, time.Millisecond,
+		"the buffer keeps draining behind a batch without a pipeline")
+	assert.Zero(t, restarted.buffer.Len())
+
+	got, err := tel.GetMetric("otelcol_receiver_solaceotlp_buffer_dropped_messages")
+	require.NoError(t, err)
+	sum := got.Data.(metricdata.Sum[int64])
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+	assert.Equal(t, attribute.NewSet(attribute.String("signal", "traces")), sum.DataPoints[0].Attributes)
+}
+
+func TestBufferRecord_RoundTrip(t *testing.T) {
+	data, err := decodePayload([]byte(testTracesPayload))
+	require.NoError(t, err)
+	record, err := marshalRecord(data)
+	require.NoError(t, err)
+	got, err := unmarshalRecord(record)
+	require.NoError(t, err)
+	assert.Equal(t, data.signal, got.signal)
+	assert.Equal(t, data.traces, got.traces)
+
+	_, err = unmarshalRecord(nil)
+	assert.ErrorIs(t, err, errInvalidRecord)
+	_, err = unmarshalRecord([]byte("x"))
+	assert.ErrorIs(t, err, errInvalidRecord)
+}
+
+func TestConfigValidate_Buffer(t *testing.T) {
+	for name, tc := range map[string]struct {
+		buffer  solaceconfig.BufferConfig
+		wantErr bool
+	}{
+		"enabled":           {buffer: solaceconfig.BufferConfig{Enabled: true, Storage: &fileStorageID, MaxBatches: 1}},
+		"disabled":          {buffer: solaceconfig.BufferConfig{}},
+		"no storage":        {buffer: solaceconfig.BufferConfig{Enabled: true, MaxBatches: 1}, wantErr: true},
+		"no capacity":       {buffer: solaceconfig.BufferConfig{Enabled: true, Storage: &fileStorageID}, wantErr: true},
+		"negative interval": {buffer: solaceconfig.BufferConfig{Enabled: true, Storage: &fileStorageID, MaxBatches: 1, Retry: solaceconfig.ConnectRetryConfig{MaxInterval: -1}}, wantErr: true},
+		"shrinking backoff": {buffer: solaceconfig.BufferConfig{Enabled: true, Storage: &fileStorageID, MaxBatches: 1, Retry: solaceconfig.ConnectRetryConfig{Multiplier: 0.5}}, wantErr: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+			cfg.Buffer = tc.buffer
+			err := cfg.Validate()
+			if tc.wantErr {
+				assert.ErrorContains(t, err, "buffer: ")
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
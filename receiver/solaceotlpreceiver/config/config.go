@@ -1,10 +1,443 @@
 package config
 
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/replay"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/selector"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/topic"
+)
+
+const (
+	// InitialConnectBlock makes Start wait until the broker is connected
+	InitialConnectBlock = "block"
+	// InitialConnectBackground makes Start return at once and connect with retries
+	InitialConnectBackground = "background"
+
+	// AckModeClient acknowledges a message after the next consumer accepted it
+	AckModeClient = "client"
+	// AckModeAuto lets the broker acknowledge a message on delivery
+	AckModeAuto = "auto"
+
+	// PrecedencePayload keeps resource attributes of the payload
+	PrecedencePayload = "payload"
+	// PrecedenceTopic overwrites resource attributes of the payload
+	PrecedenceTopic = "topic"
+
+	// FillSender fills missing timestamps with the sender timestamp of the message
+	FillSender = "sender"
+	// FillReceive fills missing timestamps with the receive time
+	FillReceive = "receive"
+
+	// OutOfRangeFlag marks records with timestamps out of range
+	OutOfRangeFlag = "flag"
+	// OutOfRangeClamp moves timestamps out of range to the nearest bound
+	OutOfRangeClamp = "clamp"
+
+	// DedupKeyReplicationGroupMessageID identifies messages by the ID the broker assigned when spooling
+	DedupKeyReplicationGroupMessageID = "replication_group_message_id"
+	// DedupKeyApplicationMessageID identifies messages by the ID the publisher set
+	DedupKeyApplicationMessageID = "application_message_id"
+
+	// DedupEligibleRedelivered checks only messages the broker flagged as redelivered
+	DedupEligibleRedelivered = "redelivered"
+	// DedupEligibleAll checks every message
+	DedupEligibleAll = "all"
+
+	// SelectorEvaluateBroker passes the selector to the broker, which only delivers matching messages
+	SelectorEvaluateBroker = "broker"
+	// SelectorEvaluateClient evaluates the selector in the receiver and drops messages that do not match
+	SelectorEvaluateClient = "client"
+
+	// SheddingKeyPriority ranks messages by their Solace message priority
+	SheddingKeyPriority = "priority"
+	// SheddingKeyClassOfService ranks messages by their class of service
+	SheddingKeyClassOfService = "class_of_service"
+
+	// SheddingActionDrop acknowledges shed messages without consuming them
+	SheddingActionDrop = "drop"
+	// SheddingActionReject settles shed messages as failed, so that the broker redelivers them
+	SheddingActionReject = "reject"
+
+	// DefaultReplayEndpoint is the address of the replay endpoint unless configured
+	DefaultReplayEndpoint = "localhost:13135"
+)
+
+// Formats of the built-in decoders
+const (
+	FormatProto       = "proto"
+	FormatBase64Proto = "base64_proto"
+	FormatOTLPJSON    = "otlp_json"
+	FormatJSON        = "json"
+)
+
 // Config defines configuration for the Solace OTLP receiver
 type Config struct {
-	Host     string `mapstructure:"host"`     // Solace host/endpoint
-	VPN      string `mapstructure:"vpn"`      // Solace VPN name
-	Username string `mapstructure:"username"` // Solace username
-	Password string `mapstructure:"password"` // Solace password
-	Queue    string `mapstructure:"queue"`    // Queue name for receiving messages
+	Host           string             `mapstructure:"host"`            // Solace host/endpoint
+	VPN            string             `mapstructure:"vpn"`             // Solace VPN name
+	Username       string             `mapstructure:"username"`        // Solace username
+	Password       string             `mapstructure:"password"`        // Solace password
+	Queue          string             `mapstructure:"queue"`           // Shared queue of the signals without own queues
+	BrokerSpans    BrokerSpansConfig  `mapstructure:"broker_spans"`    // Synthesized broker-hop spans
+	InitialConnect string             `mapstructure:"initial_connect"` // Startup connect policy: block or background
+	ConnectRetry   ConnectRetryConfig `mapstructure:"connect_retry"`   // Backoff between background connect attempts
+	AckMode        string             `mapstructure:"ack_mode"`        // Message acknowledgement: client or auto
+	Logs           SignalConfig       `mapstructure:"logs"`            // Settings of the logs signal
+	Traces         SignalConfig       `mapstructure:"traces"`          // Settings of the traces signal
+	Metrics        SignalConfig       `mapstructure:"metrics"`         // Settings of the metrics signal
+
+	ResourceAttributes ResourceAttributesConfig `mapstructure:"resource_attributes"` // Resource attributes from topics and static values
+	Timestamps         TimestampsConfig         `mapstructure:"timestamps"`          // Timestamp policy applied at ingestion
+	Dedup              DedupConfig              `mapstructure:"dedup"`               // Deduplication of redelivered messages
+	Buffer             BufferConfig             `mapstructure:"buffer"`              // Write-ahead buffer between the broker and the pipeline
+	Replay             ReplayConfig             `mapstructure:"replay"`              // Message replay on start and at runtime
+	Selectors          []SelectorConfig         `mapstructure:"selectors"`           // Message selectors of queues
+	LoadShedding       LoadSheddingConfig       `mapstructure:"load_shedding"`       // Shedding of low-priority messages under back-pressure
+}
+
+// SignalConfig defines the settings of one signal
+type SignalConfig struct {
+	Queues   []string      `mapstructure:"queues"`   // Queues of the signal; unset uses the shared queue
+	Encoding *component.ID `mapstructure:"encoding"` // Encoding extension that decodes payloads; unset uses the built-in OTLP decoders
+	Format   string        `mapstructure:"format"`   // Expected built-in format; unset accepts all
+	Strict   bool          `mapstructure:"strict"`   // Reject messages of other signals on the queues and payloads of other formats
+}
+
+// ResourceAttributesConfig defines resource attributes added to the received telemetry
+type ResourceAttributesConfig struct {
+	TopicTemplate string                   `mapstructure:"topic_template"` // Template whose named levels become attributes
+	Static        []StaticAttributesConfig `mapstructure:"static"`         // Attributes of queues or subscriptions
+	Precedence    string                   `mapstructure:"precedence"`     // Which value wins for attributes in the payload: payload or topic
+}
+
+// StaticAttributesConfig defines attributes of the messages of a queue or of
+// the topics matching a subscription
+type StaticAttributesConfig struct {
+	Queue        string            `mapstructure:"queue"`        // Queue the messages were received from
+	Subscription string            `mapstructure:"subscription"` // Topic subscription the message topic matches
+	Attributes   map[string]string `mapstructure:"attributes"`   // Attributes to add
+}
+
+// TimestampsConfig defines how timestamps are corrected at ingestion
+type TimestampsConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`      // Apply the policy
+	Fill       string        `mapstructure:"fill"`         // Source of missing timestamps: sender or receive
+	MaxFuture  time.Duration `mapstructure:"max_future"`   // Allowed time after the receive time; 0 disables the check
+	MaxPast    time.Duration `mapstructure:"max_past"`     // Allowed time before the receive time; 0 disables the check
+	OutOfRange string        `mapstructure:"out_of_range"` // Handling of timestamps out of range: flag or clamp
+}
+
+// DedupConfig defines how redelivered messages that were already consumed are recognized
+type DedupConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`     // Drop messages whose key is in the window
+	Key        string        `mapstructure:"key"`         // Message ID to deduplicate on: replication_group_message_id or application_message_id
+	Eligible   string        `mapstructure:"eligible"`    // Messages checked against the window: redelivered or all
+	WindowSize int           `mapstructure:"window_size"` // Number of consumed message keys remembered
+	Storage    *component.ID `mapstructure:"storage"`     // Storage extension that keeps the window across restarts; unset keeps it in memory
+}
+
+// BufferConfig defines the write-ahead buffer. Decoded messages are persisted
+// before they are acknowledged and fed to the pipeline from the buffer.
+type BufferConfig struct {
+	Enabled    bool               `mapstructure:"enabled"`     // Persist messages before acknowledging them
+	Storage    *component.ID      `mapstructure:"storage"`     // Storage extension that holds the buffer
+	MaxBatches int                `mapstructure:"max_batches"` // Messages the buffer holds before new ones are released to the broker
+	Retry      ConnectRetryConfig `mapstructure:"retry"`       // Backoff between attempts to feed a buffered message to the pipeline
+}
+
+// ReplayConfig defines the replay of messages from the replay log of the broker
+type ReplayConfig struct {
+	From   string                   `mapstructure:"from"`   // Replay on start from: all, an RFC 3339 time or a replication group message ID; unset disables it
+	Queues []string                 `mapstructure:"queues"` // Queues replayed on start; unset replays all consumed queues
+	HTTP   *confighttp.ServerConfig `mapstructure:"http"`   // HTTP server that triggers replays at runtime; unset disables it
+}
+
+// Unmarshal defaults the HTTP server of the replay endpoint once it is configured
+func (r *ReplayConfig) Unmarshal(conf *confmap.Conf) error {
+	if conf.IsSet("http") && r.HTTP == nil {
+		server := confighttp.NewDefaultServerConfig()
+		server.Endpoint = DefaultReplayEndpoint
+		server.TLSSetting = nil
+		r.HTTP = &server
+	}
+	return conf.Unmarshal(r)
+}
+
+// SelectorConfig filters the messages of a queue by their user properties
+type SelectorConfig struct {
+	Queue      string `mapstructure:"queue"`      // Queue whose messages are filtered
+	Expression string `mapstructure:"expression"` // Solace selector, e.g. "env = 'prod' AND severity >= 13"
+	Evaluate   string `mapstructure:"evaluate"`   // Where the selector is evaluated: broker or client
+}
+
+// LoadSheddingConfig sheds messages of low priority while the pipeline refuses messages
+type LoadSheddingConfig struct {
+	Enabled   bool                 `mapstructure:"enabled"`   // Shed messages under back-pressure
+	Key       string               `mapstructure:"key"`       // Rank of a message: priority or class_of_service
+	Default   int                  `mapstructure:"default"`   // Rank of messages without a priority
+	Threshold int                  `mapstructure:"threshold"` // Messages ranked at or above are never shed
+	Bands     []SheddingBandConfig `mapstructure:"bands"`     // Bands below the threshold, shed from the lowest
+	Action    string               `mapstructure:"action"`    // Handling of shed messages: drop or reject
+	Cooldown  time.Duration        `mapstructure:"cooldown"`  // Time until one more band is shed under back-pressure, or one less without it
+}
+
+// SheddingBandConfig is a range of ranks shed together
+type SheddingBandConfig struct {
+	Name string `mapstructure:"name"` // Name reported in the shed metric
+	Min  int    `mapstructure:"min"`  // Lowest rank of the band; it ends below the next band
+}
+
+// BrokerSpansConfig defines the synthesized broker-hop spans. They are only
+// created for log and trace messages, and for log messages only with a
+// traces pipeline; metrics and profiles messages get none.
+type BrokerSpansConfig struct {
+	Enabled bool `mapstructure:"enabled"` // Emit one CONSUMER span per log or trace message
+}
+
+// ConnectRetryConfig defines the exponential backoff of background connect attempts
+type ConnectRetryConfig struct {
+	InitialInterval time.Duration `mapstructure:"initial_interval"` // Wait after the first failed attempt
+	MaxInterval     time.Duration `mapstructure:"max_interval"`     // Upper bound for the wait between attempts
+	Multiplier      float64       `mapstructure:"multiplier"`       // Growth factor of the wait
+}
+
+// Validate checks the receiver configuration
+func (c *Config) Validate() error {
+	switch c.InitialConnect {
+	case "", InitialConnectBlock, InitialConnectBackground:
+	default:
+		return fmt.Errorf("initial_connect must be %q or %q, got %q",
+			InitialConnectBlock, InitialConnectBackground, c.InitialConnect)
+	}
+	switch c.AckMode {
+	case "", AckModeClient, AckModeAuto:
+	default:
+		return fmt.Errorf("ack_mode must be %q or %q, got %q", AckModeClient, AckModeAuto, c.AckMode)
+	}
+	if c.ConnectRetry.InitialInterval < 0 || c.ConnectRetry.MaxInterval < 0 {
+		return fmt.Errorf("connect_retry intervals must not be negative")
+	}
+	if c.ConnectRetry.Multiplier != 0 && c.ConnectRetry.Multiplier < 1 {
+		return fmt.Errorf("connect_retry.multiplier must be at least 1, got %v", c.ConnectRetry.Multiplier)
+	}
+	if err := c.ResourceAttributes.validate(); err != nil {
+		return fmt.Errorf("resource_attributes: %w", err)
+	}
+	if err := c.Timestamps.validate(); err != nil {
+		return fmt.Errorf("timestamps: %w", err)
+	}
+	if err := c.Dedup.validate(); err != nil {
+		return fmt.Errorf("dedup: %w", err)
+	}
+	if err := c.Buffer.validate(); err != nil {
+		return fmt.Errorf("buffer: %w", err)
+	}
+	if err := c.Replay.validate(); err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+	if err := validateSelectors(c.Selectors); err != nil {
+		return err
+	}
+	if err := c.LoadShedding.validate(); err != nil {
+		return fmt.Errorf("load_shedding: %w", err)
+	}
+	if c.LoadShedding.Enabled && c.LoadShedding.Action == SheddingActionReject && c.AckMode == AckModeAuto {
+		return fmt.Errorf("load_shedding: action %q requires ack_mode %q", SheddingActionReject, AckModeClient)
+	}
+	for name, signal := range map[string]SignalConfig{"logs": c.Logs, "traces": c.Traces, "metrics": c.Metrics} {
+		if err := signal.validate(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validate checks the settings of one signal
+func (s SignalConfig) validate() error {
+	seen := map[string]bool{}
+	for _, queue := range s.Queues {
+		if queue == "" {
+			return fmt.Errorf("queues must not contain an empty name")
+		}
+		if seen[queue] {
+			return fmt.Errorf("queue %q is listed twice", queue)
+		}
+		seen[queue] = true
+	}
+	switch s.Format {
+	case "", FormatProto, FormatBase64Proto, FormatOTLPJSON, FormatJSON:
+	default:
+		return fmt.Errorf("format must be one of %q, %q, %q or %q, got %q",
+			FormatProto, FormatBase64Proto, FormatOTLPJSON, FormatJSON, s.Format)
+	}
+	if s.Format != "" && s.Encoding != nil {
+		return fmt.Errorf("format and encoding are mutually exclusive")
+	}
+	return nil
+}
+
+// validate checks the resource attributes settings
+func (r ResourceAttributesConfig) validate() error {
+	if r.TopicTemplate != "" {
+		if _, err := topic.ParseTemplate(r.TopicTemplate); err != nil {
+			return err
+		}
+	}
+	switch r.Precedence {
+	case "", PrecedencePayload, PrecedenceTopic:
+	default:
+		return fmt.Errorf("precedence must be %q or %q, got %q", PrecedencePayload, PrecedenceTopic, r.Precedence)
+	}
+	for i, static := range r.Static {
+		if (static.Queue == "") == (static.Subscription == "") {
+			return fmt.Errorf("static[%d]: exactly one of queue and subscription must be set", i)
+		}
+		if len(static.Attributes) == 0 {
+			return fmt.Errorf("static[%d]: attributes must not be empty", i)
+		}
+	}
+	return nil
+}
+
+// validate checks the timestamp policy
+func (t TimestampsConfig) validate() error {
+	switch t.Fill {
+	case "", FillSender, FillReceive:
+	default:
+		return fmt.Errorf("fill must be %q or %q, got %q", FillSender, FillReceive, t.Fill)
+	}
+	switch t.OutOfRange {
+	case "", OutOfRangeFlag, OutOfRangeClamp:
+	default:
+		return fmt.Errorf("out_of_range must be %q or %q, got %q", OutOfRangeFlag, OutOfRangeClamp, t.OutOfRange)
+	}
+	if t.MaxFuture < 0 || t.MaxPast < 0 {
+		return fmt.Errorf("max_future and max_past must not be negative")
+	}
+	return nil
+}
+
+// validate checks the deduplication settings
+func (d DedupConfig) validate() error {
+	switch d.Key {
+	case "", DedupKeyReplicationGroupMessageID, DedupKeyApplicationMessageID:
+	default:
+		return fmt.Errorf("key must be %q or %q, got %q",
+			DedupKeyReplicationGroupMessageID, DedupKeyApplicationMessageID, d.Key)
+	}
+	switch d.Eligible {
+	case "", DedupEligibleRedelivered, DedupEligibleAll:
+	default:
+		return fmt.Errorf("eligible must be %q or %q, got %q", DedupEligibleRedelivered, DedupEligibleAll, d.Eligible)
+	}
+	if d.Enabled && d.WindowSize <= 0 {
+		return fmt.Errorf("window_size must be positive, got %d", d.WindowSize)
+	}
+	return nil
+}
+
+// validate checks the write-ahead buffer settings
+func (b BufferConfig) validate() error {
+	if !b.Enabled {
+		return nil
+	}
+	if b.Storage == nil {
+		return fmt.Errorf("storage must be set")
+	}
+	if b.MaxBatches <= 0 {
+		return fmt.Errorf("max_batches must be positive, got %d", b.MaxBatches)
+	}
+	if b.Retry.InitialInterval < 0 || b.Retry.MaxInterval < 0 {
+		return fmt.Errorf("retry intervals must not be negative")
+	}
+	if b.Retry.Multiplier != 0 && b.Retry.Multiplier < 1 {
+		return fmt.Errorf("retry.multiplier must be at least 1, got %v", b.Retry.Multiplier)
+	}
+	return nil
+}
+
+// validate checks the replay settings
+func (r ReplayConfig) validate() error {
+	if r.From != "" {
+		if _, err := replay.ParseStrategy(r.From); err != nil {
+			return err
+		}
+	} else if len(r.Queues) > 0 {
+		return fmt.Errorf("queues require from")
+	}
+	for _, queue := range r.Queues {
+		if queue == "" {
+			return fmt.Errorf("queues must not contain an empty name")
+		}
+	}
+	return nil
+}
+
+// validateSelectors checks the message selectors of queues
+func validateSelectors(selectors []SelectorConfig) error {
+	seen := map[string]bool{}
+	for i, s := range selectors {
+		if s.Queue == "" {
+			return fmt.Errorf("selectors[%d]: queue must be set", i)
+		}
+		if seen[s.Queue] {
+			return fmt.Errorf("selectors[%d]: queue %q has more than one selector", i, s.Queue)
+		}
+		seen[s.Queue] = true
+		if _, err := selector.Parse(s.Expression); err != nil {
+			return fmt.Errorf("selectors[%d]: invalid expression: %w", i, err)
+		}
+		switch s.Evaluate {
+		case "", SelectorEvaluateBroker, SelectorEvaluateClient:
+		default:
+			return fmt.Errorf("selectors[%d]: evaluate must be %q or %q, got %q",
+				i, SelectorEvaluateBroker, SelectorEvaluateClient, s.Evaluate)
+		}
+	}
+	return nil
+}
+
+// validate checks the load shedding settings
+func (l LoadSheddingConfig) validate() error {
+	switch l.Key {
+	case "", SheddingKeyPriority, SheddingKeyClassOfService:
+	default:
+		return fmt.Errorf("key must be %q or %q, got %q", SheddingKeyPriority, SheddingKeyClassOfService, l.Key)
+	}
+	switch l.Action {
+	case "", SheddingActionDrop, SheddingActionReject:
+	default:
+		return fmt.Errorf("action must be %q or %q, got %q", SheddingActionDrop, SheddingActionReject, l.Action)
+	}
+	if !l.Enabled {
+		return nil
+	}
+	if l.Cooldown <= 0 {
+		return fmt.Errorf("cooldown must be positive, got %v", l.Cooldown)
+	}
+	if len(l.Bands) == 0 {
+		return fmt.Errorf("bands must not be empty")
+	}
+	names := map[string]bool{}
+	for i, band := range l.Bands {
+		if band.Name == "" {
+			return fmt.Errorf("bands[%d]: name must be set", i)
+		}
+		if names[band.Name] {
+			return fmt.Errorf("bands[%d]: name %q is used twice", i, band.Name)
+		}
+		names[band.Name] = true
+		if i > 0 && band.Min <= l.Bands[i-1].Min {
+			return fmt.Errorf("bands[%d]: min must be greater than the min of the previous band", i)
+		}
+	}
+	if last := l.Bands[len(l.Bands)-1]; last.Min >= l.Threshold {
+		return fmt.Errorf("threshold must be greater than the min of band %q, got %d", last.Name, l.Threshold)
+	}
+	return nil
 }
@@ -0,0 +1,181 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pipeline"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/solacetest"
+)
+
+// brokerGenerator implements receivertest.Generator by publishing base64
+// OTLP protobuf messages to a fake broker
+type brokerGenerator struct {
+	broker *solacetest.Broker
+	signal pipeline.Signal
+	nextID atomic.Int64
+}
+
+func (g *brokerGenerator) Start() {}
+
+func (g *brokerGenerator) Stop() {}
+
+func (g *brokerGenerator) Generate() []receivertest.UniqueIDAttrVal {
+	id := receivertest.UniqueIDAttrVal(fmt.Sprint(g.nextID.Add(1)))
+	var payload []byte
+	var err error
+	switch g.signal {
+	case pipeline.SignalLogs:
+		payload, err = (&plog.ProtoMarshaler{}).MarshalLogs(receivertest.CreateOneLogWithID(id))
+	default:
+		payload, err = (&ptrace.ProtoMarshaler{}).MarshalTraces(receivertest.CreateOneSpanWithID(id))
+	}
+	if err != nil {
+		panic(err)
+	}
+	g.broker.Publish("otel/"+g.signal.String(), []byte(base64.StdEncoding.EncodeToString(payload)))
+	return []receivertest.UniqueIDAttrVal{id}
+}
+
+// newBrokerFactory creates a receiver factory whose receivers connect to broker
+func newBrokerFactory(broker *solacetest.Broker) receiver.Factory {
+	return receiver.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		receiver.WithTraces(func(_ context.Context, settings receiver.Settings, cfg component.Config, next consumer.Traces) (receiver.Traces, error) {
+			return NewReceiver(settings, cfg.(*solaceconfig.Config), nil, next, nil, broker.NewMessagingService())
+		}, component.StabilityLevelStable),
+		receiver.WithLogs(func(_ context.Context, settings receiver.Settings, cfg component.Config, next consumer.Logs) (receiver.Logs, error) {
+			return NewReceiver(settings, cfg.(*solaceconfig.Config), next, nil, nil, broker.NewMessagingService())
+		}, component.StabilityLevelAlpha),
+	)
+}
+
+// TestConsumeContract_ClientAck checks at-least-once delivery: retryable
+// consumer errors redeliver a message, permanent errors reject it, and
+// accepted data is never delivered twice.
+func TestConsumeContract_ClientAck(t *testing.T) {
+	for _, signal := range []pipeline.Signal{pipeline.SignalLogs, pipeline.SignalTraces} {
+		t.Run(signal.String(), func(t *testing.T) {
+			broker := newTestBroker()
+			receivertest.CheckConsumeContract(receivertest.CheckConsumeContractParams{
+				T:             t,
+				Factory:       newBrokerFactory(broker),
+				Signal:        signal,
+				Config:        newTestConfig(solaceconfig.InitialConnectBlock),
+				Generator:     &brokerGenerator{broker: broker, signal: signal},
+				GenerateCount: 1000,
+			})
+			assert.Equal(t, 0, broker.Pending(testQueue)+broker.Unacked(testQueue), "every message is settled")
+		})
+	}
+}
+
+// TestConsumeContract_AutoAck checks at-most-once delivery: every message
+// reaches the consumer exactly once and is dropped if the consumer fails.
+func TestConsumeContract_AutoAck(t *testing.T) {
+	for _, signal := range []pipeline.Signal{pipeline.SignalLogs, pipeline.SignalTraces} {
+		t.Run(signal.String(), func(t *testing.T) {
+			broker := newTestBroker()
+			var mu sync.Mutex
+			seen := map[string]int{}
+			var failed int
+			record := func(ids []string) error {
+				mu.Lock()
+				defer mu.Unlock()
+				for _, id := range ids {
+					seen[id]++
+				}
+				switch rand.Intn(3) {
+				case 0:
+					failed += len(ids)
+					return errors.New("non permanent error")
+				case 1:
+					failed += len(ids)
+					return consumererror.NewPermanent(errors.New("permanent error"))
+				}
+				return nil
+			}
+			logs, err := consumer.NewLogs(func(_ context.Context, ld plog.Logs) error {
+				return record(logIDs(ld))
+			})
+			require.NoError(t, err)
+			traces, err := consumer.NewTraces(func(_ context.Context, td ptrace.Traces) error {
+				return record(spanIDs(td))
+			})
+			require.NoError(t, err)
+
+			cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+			cfg.AckMode = solaceconfig.AckModeAuto
+			r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, logs, traces, nil, broker.NewMessagingService())
+			require.NoError(t, err)
+			require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+			const count = 1000
+			generator := &brokerGenerator{broker: broker, signal: signal}
+			for i := 0; i < count; i++ {
+				generator.Generate()
+			}
+			require.Eventually(t, func() bool { return broker.Acked(testQueue) == count }, 5*time.Second, time.Millisecond)
+			require.NoError(t, r.Shutdown(context.Background()))
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Len(t, seen, count)
+			for id, n := range seen {
+				assert.Equal(t, 1, n, "message %s delivered more than once", id)
+			}
+			assert.Positive(t, failed)
+			assert.Equal(t, 0, broker.Pending(testQueue))
+		})
+	}
+}
+
+func logIDs(ld plog.Logs) []string {
+	var ids []string
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		for j := 0; j < ld.ResourceLogs().At(i).ScopeLogs().Len(); j++ {
+			records := ld.ResourceLogs().At(i).ScopeLogs().At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				if id, ok := records.At(k).Attributes().Get(receivertest.UniqueIDAttrName); ok {
+					ids = append(ids, id.Str())
+				}
+			}
+		}
+	}
+	return ids
+}
+
+func spanIDs(td ptrace.Traces) []string {
+	var ids []string
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		for j := 0; j < td.ResourceSpans().At(i).ScopeSpans().Len(); j++ {
+			spans := td.ResourceSpans().At(i).ScopeSpans().At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				if id, ok := spans.At(k).Attributes().Get(receivertest.UniqueIDAttrName); ok {
+					ids = append(ids, id.Str())
+				}
+			}
+		}
+	}
+	return ids
+}
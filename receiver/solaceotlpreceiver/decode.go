@@ -0,0 +1,496 @@
+package solaceotlpreceiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pipeline"
+	"go.opentelemetry.io/collector/pipeline/xpipeline"
+	"google.golang.org/protobuf/encoding/protowire"
+	"solace.dev/go/messaging/pkg/solace/message"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/otlplegacy"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/simplejson"
+)
+
+// Payload formats recognized by decodePayload
+const (
+	formatProto       = solaceconfig.FormatProto
+	formatBase64Proto = solaceconfig.FormatBase64Proto
+	formatOTLPJSON    = solaceconfig.FormatOTLPJSON
+	formatJSON        = solaceconfig.FormatJSON
+)
+
+// maxDecompressedSize bounds the size of a decompressed payload
+const maxDecompressedSize = 64 << 20
+
+// maxPooledBuffer bounds the capacity of the buffers kept for reuse
+const maxPooledBuffer = 8 << 20
+
+// gzipMagic starts every gzip stream
+var gzipMagic = []byte{0x1f, 0x8b}
+
+var errUnknownFormat = errors.New("payload is neither OTLP protobuf, OTLP/JSON nor simplified JSON")
+
+var (
+	// bufferPool recycles the buffers of decompressed and base64-decoded
+	// payloads. pdata and encoding/json copy what they keep, so a buffer is
+	// reused as soon as its payload is decoded.
+	bufferPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+	// gzipPool recycles gzip readers
+	gzipPool sync.Pool
+)
+
+// base64Chars marks the bytes of the standard base64 alphabet and the line
+// breaks that the decoder skips
+var base64Chars = func() (chars [256]bool) {
+	for _, c := range []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/=\r\n") {
+		chars[c] = true
+	}
+	return chars
+}()
+
+// decoded is the telemetry of one message
+type decoded struct {
+	signal   pipeline.Signal
+	format   string
+	logs     plog.Logs
+	traces   ptrace.Traces
+	metrics  pmetric.Metrics
+	profiles pprofile.Profiles
+	legacy   []otlplegacy.Version // deprecated OTLP versions of a protobuf payload
+}
+
+// messagePayload returns the payload of a message. A structured string
+// payload is sliced out of its SDT encoding instead of being copied again.
+func messagePayload(msg message.InboundMessage) ([]byte, bool) {
+	if payload, ok := msg.GetPayloadAsBytes(); ok {
+		return unwrapSDTString(payload), true
+	}
+	if s, ok := msg.GetPayloadAsString(); ok {
+		return []byte(s), true
+	}
+	return nil, false
+}
+
+// unwrapSDTString returns the text of an SDT string container, a type tag
+// followed by the big-endian container length and a NUL-terminated string,
+// and any other payload unchanged
+func unwrapSDTString(payload []byte) []byte {
+	if len(payload) < 2 || payload[0]&^0x03 != 0x1c {
+		return payload
+	}
+	header := 2 + int(payload[0]&0x03)
+	if len(payload) < header+1 || payload[len(payload)-1] != 0 {
+		return payload
+	}
+	size := 0
+	for _, b := range payload[1:header] {
+		size = size<<8 | int(b)
+	}
+	if size != len(payload) {
+		return payload
+	}
+	return payload[header : len(payload)-1]
+}
+
+// scratch holds the pooled buffers of one decodePayload call
+type scratch struct {
+	buffers [3]*bytes.Buffer
+	n       int
+}
+
+// buffer takes an empty buffer from the pool
+func (s *scratch) buffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	if s.n < len(s.buffers) {
+		s.buffers[s.n] = buf
+		s.n++
+	}
+	return buf
+}
+
+// release returns the buffers to the pool
+func (s *scratch) release() {
+	for _, buf := range s.buffers[:s.n] {
+		if buf.Cap() <= maxPooledBuffer {
+			bufferPool.Put(buf)
+		}
+	}
+	s.n = 0
+}
+
+// decodePayload detects the format of a payload and decodes it. Payloads may
+// be gzip-compressed before or after base64 encoding. The payload is parsed
+// once; it is not retained.
+func decodePayload(payload []byte) (decoded, error) {
+	var s scratch
+	defer s.release()
+
+	payload, err := s.decompress(payload)
+	if err != nil {
+		return decoded{}, err
+	}
+	if trimmed := bytes.TrimSpace(payload); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return decodeJSON(trimmed)
+	}
+	if isBase64(payload) {
+		if raw, err := s.decodeBase64(payload); err == nil {
+			if raw, err = s.decompress(raw); err != nil {
+				return decoded{}, err
+			}
+			if d, ok := decodeProto(raw); ok {
+				d.format = formatBase64Proto
+				return d, nil
+			}
+		}
+	}
+	if d, ok := decodeProto(payload); ok {
+		d.format = formatProto
+		return d, nil
+	}
+	return decoded{}, errUnknownFormat
+}
+
+// isBase64 reports whether payload may be base64 text. Binary payloads fail
+// within the first bytes and skip base64 decoding.
+func isBase64(payload []byte) bool {
+	for _, c := range payload {
+		if !base64Chars[c] {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeBase64 decodes base64 text into a pooled buffer
+func (s *scratch) decodeBase64(payload []byte) ([]byte, error) {
+	buf := s.buffer()
+	buf.Grow(base64.StdEncoding.DecodedLen(len(payload)))
+	dst := buf.AvailableBuffer()[:base64.StdEncoding.DecodedLen(len(payload))]
+	n, err := base64.StdEncoding.Decode(dst, payload)
+	if err != nil {
+		return nil, err
+	}
+	return dst[:n], nil
+}
+
+// decompress inflates gzip payloads into a pooled buffer and returns any
+// other payload unchanged
+func (s *scratch) decompress(payload []byte) ([]byte, error) {
+	if !bytes.HasPrefix(payload, gzipMagic) {
+		return payload, nil
+	}
+	zr, err := gzipReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gzip payload: %w", err)
+	}
+	defer gzipPool.Put(zr)
+	buf := s.buffer()
+	if _, err := buf.ReadFrom(io.LimitReader(zr, maxDecompressedSize+1)); err != nil {
+		return nil, fmt.Errorf("failed to decompress payload: %w", err)
+	}
+	if buf.Len() > maxDecompressedSize {
+		return nil, fmt.Errorf("decompressed payload exceeds %d bytes", maxDecompressedSize)
+	}
+	return buf.Bytes(), nil
+}
+
+// gzipReader takes a gzip reader from the pool and resets it to r
+func gzipReader(r io.Reader) (*gzip.Reader, error) {
+	if zr, ok := gzipPool.Get().(*gzip.Reader); ok {
+		if err := zr.Reset(r); err != nil {
+			gzipPool.Put(zr)
+			return nil, err
+		}
+		return zr, nil
+	}
+	return gzip.NewReader(r)
+}
+
+// decodeProto decodes OTLP protobuf logs, traces, metrics or profiles. The
+// signals whose records may hold the fields of the first record are tried in
+// turn, so that the payload is usually unmarshaled once. Payloads without
+// records are tried as logs, then as traces. Logs and traces of deprecated
+// OTLP versions are translated first.
+func decodeProto(payload []byte) (decoded, bool) {
+	signals := sniffProtoSignals(payload)
+	var legacy []otlplegacy.Version
+	if signals == 0 || signals&(sniffLogs|sniffTraces) != 0 {
+		var translated []byte
+		if translated, legacy = otlplegacy.Translate(payload); legacy != nil {
+			payload = translated
+			signals = sniffProtoSignals(payload) & (sniffLogs | sniffTraces)
+		}
+	}
+	if signals == 0 {
+		signals = sniffLogs | sniffTraces
+	}
+	for i, signal := range sniffSignals {
+		if signals&(1<<i) == 0 {
+			continue
+		}
+		if d, ok := unmarshalProto(signal, payload); ok {
+			d.legacy = legacy
+			return d, true
+		}
+	}
+	return decoded{}, false
+}
+
+// unmarshalProto unmarshals OTLP protobuf of signal
+func unmarshalProto(signal pipeline.Signal, payload []byte) (decoded, bool) {
+	d := decoded{signal: signal}
+	var err error
+	switch signal {
+	case pipeline.SignalLogs:
+		d.logs, err = (&plog.ProtoUnmarshaler{}).UnmarshalLogs(payload)
+	case pipeline.SignalTraces:
+		d.traces, err = (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(payload)
+	case pipeline.SignalMetrics:
+		d.metrics, err = (&pmetric.ProtoUnmarshaler{}).UnmarshalMetrics(payload)
+	default:
+		d.profiles, err = (&pprofile.ProtoUnmarshaler{}).UnmarshalProfiles(payload)
+	}
+	return d, err == nil
+}
+
+// Signals whose records may hold a field, as a bit set
+const (
+	sniffLogs = 1 << iota
+	sniffMetrics
+	sniffTraces
+	sniffProfiles
+)
+
+// sniffSignals are the signals of the sniff bits, by precedence
+var sniffSignals = [...]pipeline.Signal{pipeline.SignalLogs, pipeline.SignalMetrics, pipeline.SignalTraces, xpipeline.SignalProfiles}
+
+// sniffProtoSignals tells OTLP logs, traces, metrics and profiles apart by
+// the fields of the first log record, span, metric or profile. All requests
+// nest records as field 1 (resource), field 2 (scope) and field 2 (record),
+// but the record fields differ in number, type and length. It returns the
+// signals whose records may hold every field, or none if no record is found.
+// Spans and profiles nearly always hold a field of their own; logs and
+// metrics are told by the precedence of sniffSignals.
+func sniffProtoSignals(payload []byte) int {
+	resource, ok := firstBytesField(payload, 1)
+	if !ok {
+		return 0
+	}
+	scope, ok := firstBytesField(resource, 2)
+	if !ok {
+		return 0
+	}
+	record, ok := firstBytesField(scope, 2)
+	if !ok || len(record) == 0 {
+		return 0
+	}
+	signals := sniffLogs | sniffMetrics | sniffTraces | sniffProfiles
+	for len(record) > 0 && signals != 0 {
+		num, typ, n := protowire.ConsumeTag(record)
+		if n < 0 {
+			return 0
+		}
+		record = record[n:]
+		n = protowire.ConsumeFieldValue(num, typ, record)
+		if n < 0 {
+			return 0
+		}
+		var size int
+		if typ == protowire.BytesType {
+			v, _ := protowire.ConsumeBytes(record)
+			size = len(v)
+		}
+		signals &= fieldSignals(num, typ, size)
+		record = record[n:]
+	}
+	return signals
+}
+
+// fieldSignals returns the signals whose records may hold field num of
+// wire type typ. Trace and span IDs are told by their size in bytes.
+func fieldSignals(num protowire.Number, typ protowire.Type, size int) int {
+	switch typ {
+	case protowire.Fixed64Type:
+		switch num {
+		case 1, 11: // time_unix_nano, observed_time_unix_nano
+			return sniffLogs
+		case 7, 8: // start_time_unix_nano, end_time_unix_nano
+			return sniffTraces
+		}
+	case protowire.Fixed32Type:
+		switch num {
+		case 8: // flags
+			return sniffLogs
+		case 16: // flags
+			return sniffTraces
+		}
+	case protowire.VarintType:
+		switch num {
+		case 2, 7: // severity_number, dropped_attributes_count
+			return sniffLogs
+		case 6, 10: // kind, dropped_attributes_count
+			return sniffTraces
+		case 12, 14: // dropped_events_count, duration_nanos; dropped_links_count, period
+			return sniffTraces | sniffProfiles
+		case 5, 11, 15, 16, 19, 22: // unpacked indices, time_nanos, default_sample_type_strindex, dropped_attributes_count
+			return sniffProfiles
+		}
+	case protowire.BytesType:
+		switch num {
+		case 1: // name, trace_id, sample_type
+			return sniffMetrics | idSignal(size, 16, sniffTraces) | sniffProfiles
+		case 2: // description, span_id, sample
+			return sniffMetrics | idSignal(size, 8, sniffTraces) | sniffProfiles
+		case 3, 5: // severity_text, unit, trace_state, mapping_table; body, gauge, name, location_indices
+			return sniffLogs | sniffMetrics | sniffTraces | sniffProfiles
+		case 4: // parent_span_id, location_table
+			return idSignal(size, 8, sniffTraces) | sniffProfiles
+		case 6: // attributes, function_table
+			return sniffLogs | sniffProfiles
+		case 7: // sum, attribute_table
+			return sniffMetrics | sniffProfiles
+		case 9: // trace_id, histogram, attributes, link_table
+			return idSignal(size, 16, sniffLogs) | sniffMetrics | sniffTraces | sniffProfiles
+		case 10: // span_id, exponential_histogram, string_table
+			return idSignal(size, 8, sniffLogs) | sniffMetrics | sniffProfiles
+		case 11: // summary, events
+			return sniffMetrics | sniffTraces
+		case 12: // event_name, metadata
+			return sniffLogs | sniffMetrics
+		case 13, 15: // links, period_type; status, comment_strindices
+			return sniffTraces | sniffProfiles
+		case 8, 17, 20, 21, 22: // attribute_units, profile_id, original_payload_format, original_payload, attribute_indices
+			return sniffProfiles
+		}
+	}
+	return 0
+}
+
+// idSignal returns signal if size fits an ID of idSize bytes, which may be
+// empty
+func idSignal(size, idSize, signal int) int {
+	if size == 0 || size == idSize {
+		return signal
+	}
+	return 0
+}
+
+// firstBytesField returns the value of the first length-delimited field num
+func firstBytesField(b []byte, num protowire.Number) ([]byte, bool) {
+	for len(b) > 0 {
+		n, typ, tagLen := protowire.ConsumeTag(b)
+		if tagLen < 0 {
+			return nil, false
+		}
+		b = b[tagLen:]
+		if n == num && typ == protowire.BytesType {
+			v, vLen := protowire.ConsumeBytes(b)
+			return v, vLen >= 0
+		}
+		vLen := protowire.ConsumeFieldValue(n, typ, b)
+		if vLen < 0 {
+			return nil, false
+		}
+		b = b[vLen:]
+	}
+	return nil, false
+}
+
+// decodeJSON decodes an OTLP/JSON export request, a simplified JSON log,
+// simplified JSON spans or simplified JSON metrics
+func decodeJSON(payload []byte) (decoded, error) {
+	if payload[0] == '[' {
+		traces, err := simplejson.UnmarshalTraces(payload)
+		if err != nil {
+			return decoded{}, err
+		}
+		return decoded{signal: pipeline.SignalTraces, format: formatJSON, traces: traces}, nil
+	}
+	var probe struct {
+		ResourceLogs          json.RawMessage `json:"resourceLogs"`
+		ResourceLogsSnake     json.RawMessage `json:"resource_logs"`
+		ResourceSpans         json.RawMessage `json:"resourceSpans"`
+		ResourceSpansSnake    json.RawMessage `json:"resource_spans"`
+		ResourceMetrics       json.RawMessage `json:"resourceMetrics"`
+		ResourceMetricsSnake  json.RawMessage `json:"resource_metrics"`
+		ResourceProfiles      json.RawMessage `json:"resourceProfiles"`
+		ResourceProfilesSnake json.RawMessage `json:"resource_profiles"`
+		Metrics               json.RawMessage `json:"metrics"`
+		DataPoints            json.RawMessage `json:"data_points"`
+	}
+	if err := json.Unmarshal(payload, &probe); err != nil {
+		return decoded{}, fmt.Errorf("failed to unmarshal JSON payload: %w", err)
+	}
+	switch {
+	case probe.ResourceLogs != nil || probe.ResourceLogsSnake != nil:
+		logs, err := (&plog.JSONUnmarshaler{}).UnmarshalLogs(payload)
+		if err != nil {
+			return decoded{}, fmt.Errorf("failed to unmarshal OTLP/JSON logs: %w", err)
+		}
+		return decoded{signal: pipeline.SignalLogs, format: formatOTLPJSON, logs: logs}, nil
+	case probe.ResourceSpans != nil || probe.ResourceSpansSnake != nil:
+		traces, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(payload)
+		if err != nil {
+			return decoded{}, fmt.Errorf("failed to unmarshal OTLP/JSON traces: %w", err)
+		}
+		return decoded{signal: pipeline.SignalTraces, format: formatOTLPJSON, traces: traces}, nil
+	case probe.ResourceMetrics != nil || probe.ResourceMetricsSnake != nil:
+		metrics, err := (&pmetric.JSONUnmarshaler{}).UnmarshalMetrics(payload)
+		if err != nil {
+			return decoded{}, fmt.Errorf("failed to unmarshal OTLP/JSON metrics: %w", err)
+		}
+		return decoded{signal: pipeline.SignalMetrics, format: formatOTLPJSON, metrics: metrics}, nil
+	case probe.ResourceProfiles != nil || probe.ResourceProfilesSnake != nil:
+		profiles, err := (&pprofile.JSONUnmarshaler{}).UnmarshalProfiles(payload)
+		if err != nil {
+			return decoded{}, fmt.Errorf("failed to unmarshal OTLP/JSON profiles: %w", err)
+		}
+		return decoded{signal: xpipeline.SignalProfiles, format: formatOTLPJSON, profiles: profiles}, nil
+	case probe.Metrics != nil || probe.DataPoints != nil:
+		metrics, err := simplejson.UnmarshalMetrics(payload)
+		if err != nil {
+			return decoded{}, err
+		}
+		return decoded{signal: pipeline.SignalMetrics, format: formatJSON, metrics: metrics}, nil
+	}
+	if !isJSONTrace(payload) {
+		logs, err := simplejson.UnmarshalLogs(payload)
+		if err != nil {
+			return decoded{}, err
+		}
+		return decoded{signal: pipeline.SignalLogs, format: formatJSON, logs: logs}, nil
+	}
+	traces, err := simplejson.UnmarshalTraces(payload)
+	if err != nil {
+		return decoded{}, err
+	}
+	return decoded{signal: pipeline.SignalTraces, format: formatJSON, traces: traces}, nil
+}
+
+// isJSONTrace reports whether a JSON object carries span fields. The log
+// struct accepts any object, so spans would otherwise decode as empty logs.
+func isJSONTrace(payload []byte) bool {
+	var probe struct {
+		Name      *string          `json:"name"`
+		StartTime *json.RawMessage `json:"start_time"`
+		Spans     *json.RawMessage `json:"spans"`
+	}
+	if err := json.Unmarshal(payload, &probe); err != nil {
+		return false
+	}
+	return probe.Name != nil || probe.StartTime != nil || probe.Spans != nil
+}
@@ -0,0 +1,91 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+	"solace.dev/go/messaging/pkg/solace/message"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/dedup"
+)
+
+// dedupStorageName names the storage client of the deduplication window
+const dedupStorageName = "dedup"
+
+// loadDedup creates the deduplication window and restores it from the
+// configured storage extension
+func (r *Receiver) loadDedup(ctx context.Context, host component.Host) error {
+	cfg := r.config.Dedup
+	if !cfg.Enabled {
+		return nil
+	}
+	r.dedupWindow = dedup.NewWindow(cfg.WindowSize)
+	if cfg.Storage == nil {
+		return nil
+	}
+	client, err := r.storageClient(ctx, host, *cfg.Storage, dedupStorageName)
+	if err != nil {
+		return err
+	}
+	restored, err := r.dedupWindow.Load(ctx, client)
+	if err != nil {
+		return errors.Join(err, client.Close(ctx))
+	}
+	r.dedupClient = client
+	r.logger.Info("Loaded deduplication window",
+		zap.Bool("restored", restored),
+		zap.Int("keys", r.dedupWindow.Len()))
+	return nil
+}
+
+// closeDedup closes the storage client of the deduplication window
+func (r *Receiver) closeDedup(ctx context.Context) error {
+	if r.dedupClient == nil {
+		return nil
+	}
+	err := r.dedupClient.Close(ctx)
+	r.dedupClient = nil
+	return err
+}
+
+// dedupKey returns the deduplication key of a message of queue q. Messages
+// without the configured ID are not deduplicated.
+func (r *Receiver) dedupKey(q *queueBinding, msg message.InboundMessage) (string, bool) {
+	if r.dedupWindow == nil {
+		return "", false
+	}
+	var id string
+	if r.config.Dedup.Key == solaceconfig.DedupKeyApplicationMessageID {
+		applicationID, ok := msg.GetApplicationMessageID()
+		if !ok || applicationID == "" {
+			return "", false
+		}
+		id = applicationID
+	} else {
+		rgmid, ok := msg.GetReplicationGroupMessageID()
+		if !ok || rgmid == nil {
+			return "", false
+		}
+		id = rgmid.String()
+	}
+	// The same message spooled to two queues is consumed once per queue
+	return q.name + "/" + id, true
+}
+
+// isDuplicate reports whether msg is eligible for deduplication and was already consumed
+func (r *Receiver) isDuplicate(msg message.InboundMessage, key string) bool {
+	if r.config.Dedup.Eligible != solaceconfig.DedupEligibleAll && !msg.IsRedelivered() {
+		return false
+	}
+	return r.dedupWindow.Contains(key)
+}
+
+// rememberMessage adds the key of a consumed message to the deduplication window
+func (r *Receiver) rememberMessage(key string) {
+	if err := r.dedupWindow.Add(context.Background(), key); err != nil {
+		r.logger.Warn("Failed to persist deduplication key", zap.String("key", key), zap.Error(err))
+	}
+}
@@ -0,0 +1,166 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/storagetest"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/solacetest"
+)
+
+var fileStorageID = component.MustNewID("file_storage")
+
+// newDedupConfig returns a test configuration with deduplication enabled
+func newDedupConfig(eligible string) *solaceconfig.Config {
+	cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+	cfg.Dedup.Enabled = true
+	cfg.Dedup.Eligible = eligible
+	return cfg
+}
+
+// duplicateCount returns the value of the duplicate_messages metric
+func duplicateCount(t *testing.T, tel *componenttest.Telemetry) int64 {
+	got, err := tel.GetMetric("otelcol_receiver_solaceotlp_duplicate_messages")
+	if err != nil {
+		return 0
+	}
+	var total int64
+	for _, dp := range got.Data.(metricdata.Sum[int64]).DataPoints {
+		total += dp.Value
+	}
+	return total
+}
+
+func TestDedup_DroppedAcksAcrossRestart(t *testing.T) {
+	tel := componenttest.NewTelemetry()
+	t.Cleanup(func() { require.NoError(t, tel.Shutdown(context.Background())) })
+	settings := receivertest.NewNopSettings(typeStr)
+	settings.TelemetrySettings = tel.NewTelemetrySettings()
+	cfg := newDedupConfig(solaceconfig.DedupEligibleRedelivered)
+	cfg.Dedup.Storage = &fileStorageID
+	host := extensionHost{extensions: map[component.ID]component.Component{fileStorageID: storagetest.New()}}
+
+	s := newFaultScenario(t)
+	start := func(service any) *Receiver {
+		r, err := NewReceiver(settings, cfg, s.recorder.consumer(t), nil, nil, service)
+		require.NoError(t, err)
+		require.NoError(t, r.Start(context.Background(), host))
+		return r
+	}
+	service := solacetest.WithFaults(s.broker.NewMessagingService(), solacetest.Faults{AckDropRate: 0.2, Seed: 3})
+	r := start(service)
+	require.Eventually(t, func() bool { return s.recorder.unique() == faultMessages }, 5*time.Second, time.Millisecond)
+	dropped := service.Injector().Counts().DroppedAcks
+	require.Positive(t, dropped)
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	restarted := start(s.broker.NewMessagingService())
+	require.Eventually(t, func() bool { return s.broker.Acked(testQueue) == faultMessages }, 5*time.Second, time.Millisecond)
+	require.NoError(t, restarted.Shutdown(context.Background()))
+	assert.Equal(t, faultMessages, s.recorder.deliveries(), "redeliveries of consumed messages are dropped")
+	assert.Equal(t, int64(dropped), duplicateCount(t, tel))
+}
+
+func TestDedup_Eligible(t *testing.T) {
+	for eligible, wantRecords := range map[string]int{
+		solaceconfig.DedupEligibleRedelivered: 2,
+		solaceconfig.DedupEligibleAll:         1,
+	} {
+		t.Run(eligible, func(t *testing.T) {
+			broker := newTestBroker()
+			cfg := newDedupConfig(eligible)
+			cfg.Dedup.Key = solaceconfig.DedupKeyApplicationMessageID
+			sink := new(consumertest.LogsSink)
+			r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, sink, nil, nil, broker.NewMessagingService())
+			require.NoError(t, err)
+			startReceiver(t, r)
+
+			broker.Publish("otel/logs", []byte(testLogsPayload), solacetest.WithApplicationMessageID("order-1"))
+			broker.Publish("otel/logs", []byte(testLogsPayload), solacetest.WithApplicationMessageID("order-1"))
+			broker.Publish("otel/logs", []byte(testLogsPayload))
+			require.Eventually(t, func() bool { return broker.Acked(testQueue) == 3 }, time.Second, time.Millisecond)
+			assert.Equal(t, wantRecords+1, sink.LogRecordCount(), "messages without the key are not deduplicated")
+		})
+	}
+}
+
+func TestDedup_FailedMessageIsNotRemembered(t *testing.T) {
+	broker := newTestBroker()
+	var calls atomic.Int32
+	failOnce, err := consumer.NewLogs(func(context.Context, plog.Logs) error {
+		if calls.Add(1) == 1 {
+			return assert.AnError
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), newDedupConfig(solaceconfig.DedupEligibleRedelivered),
+		failOnce, nil, nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	startReceiver(t, r)
+
+	broker.Publish("otel/logs", []byte(testLogsPayload))
+	require.Eventually(t, func() bool { return broker.Acked(testQueue) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, int32(2), calls.Load(), "the redelivery of a failed message is consumed")
+}
+
+func TestDedup_MissingStorage(t *testing.T) {
+	cfg := newDedupConfig(solaceconfig.DedupEligibleRedelivered)
+	cfg.Dedup.Storage = &fileStorageID
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, consumertest.NewNop(), nil, nil, newTestBroker().NewMessagingService())
+	require.NoError(t, err)
+	assert.ErrorContains(t, r.Start(context.Background(), componenttest.NewNopHost()), "storage extension")
+
+	host := extensionHost{extensions: map[component.ID]component.Component{fileStorageID: plainExtension{}}}
+	assert.ErrorContains(t, r.Start(context.Background(), host), "is not a storage extension")
+}
+
+func TestDedup_LoadFailureClosesStorage(t *testing.T) {
+	cfg := newDedupConfig(solaceconfig.DedupEligibleRedelivered)
+	cfg.Dedup.Storage = &fileStorageID
+	storage := storagetest.New()
+	storage.SetErr(assert.AnError)
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, consumertest.NewNop(), nil, nil, newTestBroker().NewMessagingService())
+	require.NoError(t, err)
+
+	host := extensionHost{extensions: map[component.ID]component.Component{fileStorageID: storage}}
+	assert.ErrorIs(t, r.Start(context.Background(), host), assert.AnError)
+	assert.Equal(t, 1, storage.Closed())
+}
+
+func TestConfigValidate_Dedup(t *testing.T) {
+	for name, tc := range map[string]struct {
+		dedup   solaceconfig.DedupConfig
+		wantErr bool
+	}{
+		"application id": {dedup: solaceconfig.DedupConfig{Enabled: true, Key: solaceconfig.DedupKeyApplicationMessageID, Eligible: solaceconfig.DedupEligibleAll, WindowSize: 1}},
+		"disabled":       {dedup: solaceconfig.DedupConfig{}},
+		"unknown key":    {dedup: solaceconfig.DedupConfig{Key: "correlation_id"}, wantErr: true},
+		"unknown scope":  {dedup: solaceconfig.DedupConfig{Eligible: "some"}, wantErr: true},
+		"empty window":   {dedup: solaceconfig.DedupConfig{Enabled: true}, wantErr: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+			cfg.Dedup = tc.dedup
+			err := cfg.Validate()
+			if tc.wantErr {
+				assert.ErrorContains(t, err, "dedup: ")
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package solaceotlpreceiver
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+// loadEncodings resolves the encoding extensions configured for the signals
+// the receiver serves
+func (r *Receiver) loadEncodings(host component.Host) error {
+	if id := r.config.Logs.Encoding; id != nil && r.logsConsumer != nil {
+		unmarshaler, err := loadEncoding[plog.Unmarshaler](host, *id, pipeline.SignalLogs)
+		if err != nil {
+			return err
+		}
+		r.logsUnmarshaler = unmarshaler
+	}
+	if id := r.config.Traces.Encoding; id != nil && r.tracesConsumer != nil {
+		unmarshaler, err := loadEncoding[ptrace.Unmarshaler](host, *id, pipeline.SignalTraces)
+		if err != nil {
+			return err
+		}
+		r.tracesUnmarshaler = unmarshaler
+	}
+	if id := r.config.Metrics.Encoding; id != nil && r.metricsConsumer != nil {
+		unmarshaler, err := loadEncoding[pmetric.Unmarshaler](host, *id, pipeline.SignalMetrics)
+		if err != nil {
+			return err
+		}
+		r.metricsUnmarshaler = unmarshaler
+	}
+	return nil
+}
+
+// loadEncoding looks up the extension id and checks that it unmarshals signal
+func loadEncoding[T any](host component.Host, id component.ID, signal pipeline.Signal) (T, error) {
+	var unmarshaler T
+	extension, ok := host.GetExtensions()[id]
+	if !ok {
+		return unmarshaler, fmt.Errorf("encoding extension %q not found", id)
+	}
+	unmarshaler, ok = extension.(T)
+	if !ok {
+		return unmarshaler, fmt.Errorf("extension %q is not a %s unmarshaler", id, signal)
+	}
+	return unmarshaler, nil
+}
+
+// decode decodes a payload with the encoding extensions of the signals of
+// queue q. Signals without an encoding are decoded by decodePayload; its
+// result is discarded if it belongs to a signal with an encoding.
+func (r *Receiver) decode(q *queueBinding, payload []byte) (decoded, error) {
+	var errs []error
+	if r.logsUnmarshaler != nil && q.carries(pipeline.SignalLogs) {
+		logs, err := r.logsUnmarshaler.UnmarshalLogs(payload)
+		if err == nil {
+			return decoded{signal: pipeline.SignalLogs, format: r.config.Logs.Encoding.String(), logs: logs}, nil
+		}
+		errs = append(errs, fmt.Errorf("encoding %q failed to unmarshal logs: %w", r.config.Logs.Encoding, err))
+	}
+	if r.tracesUnmarshaler != nil && q.carries(pipeline.SignalTraces) {
+		traces, err := r.tracesUnmarshaler.UnmarshalTraces(payload)
+		if err == nil {
+			return decoded{signal: pipeline.SignalTraces, format: r.config.Traces.Encoding.String(), traces: traces}, nil
+		}
+		errs = append(errs, fmt.Errorf("encoding %q failed to unmarshal traces: %w", r.config.Traces.Encoding, err))
+	}
+	if r.metricsUnmarshaler != nil && q.carries(pipeline.SignalMetrics) {
+		metrics, err := r.metricsUnmarshaler.UnmarshalMetrics(payload)
+		if err == nil {
+			return decoded{signal: pipeline.SignalMetrics, format: r.config.Metrics.Encoding.String(), metrics: metrics}, nil
+		}
+		errs = append(errs, fmt.Errorf("encoding %q failed to unmarshal metrics: %w", r.config.Metrics.Encoding, err))
+	}
+	if r.builtinSignals() == 0 {
+		return decoded{}, errors.Join(errs...)
+	}
+	d, err := decodePayload(payload)
+	if err != nil {
+		return decoded{}, errors.Join(append(errs, err)...)
+	}
+	if (d.signal == pipeline.SignalLogs && r.logsUnmarshaler != nil) ||
+		(d.signal == pipeline.SignalTraces && r.tracesUnmarshaler != nil) ||
+		(d.signal == pipeline.SignalMetrics && r.metricsUnmarshaler != nil) {
+		return decoded{}, errors.Join(errs...)
+	}
+	return d, nil
+}
+
+// builtinSignals returns the number of served signals without an encoding extension
+func (r *Receiver) builtinSignals() int {
+	n := 0
+	if r.logsConsumer != nil && r.logsUnmarshaler == nil {
+		n++
+	}
+	if r.tracesConsumer != nil && r.tracesUnmarshaler == nil {
+		n++
+	}
+	if r.metricsConsumer != nil && r.metricsUnmarshaler == nil {
+		n++
+	}
+	if r.profilesConsumer != nil {
+		n++
+	}
+	return n
+}
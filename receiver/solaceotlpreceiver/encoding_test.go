@@ -0,0 +1,256 @@
+package solaceotlpreceiver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/testdata"
+)
+
+var (
+	lineEncodingID    = component.MustNewID("line_encoding")
+	jsonEncodingID    = component.MustNewID("json_encoding")
+	metricsEncodingID = component.MustNewID("metrics_encoding")
+	plainExtensionID  = component.MustNewID("plain")
+)
+
+// extensionHost is a component.Host with extensions
+type extensionHost struct {
+	extensions map[component.ID]component.Component
+}
+
+func (h extensionHost) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+func newEncodingHost() extensionHost {
+	return extensionHost{extensions: map[component.ID]component.Component{
+		lineEncodingID:    lineEncoding{},
+		jsonEncodingID:    &jsonTracesEncoding{},
+		metricsEncodingID: &jsonMetricsEncoding{},
+		plainExtensionID:  plainExtension{},
+	}}
+}
+
+// lineEncoding is an encoding extension that makes each line of text a log record
+type lineEncoding struct {
+	component.StartFunc
+	component.ShutdownFunc
+}
+
+func (lineEncoding) UnmarshalLogs(buf []byte) (plog.Logs, error) {
+	if !utf8.Valid(buf) {
+		return plog.Logs{}, errors.New("payload is not text")
+	}
+	logs := plog.NewLogs()
+	records := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for _, line := range bytes.Split(bytes.TrimSpace(buf), []byte("\n")) {
+		records.AppendEmpty().Body().SetStr(string(line))
+	}
+	return logs, nil
+}
+
+// jsonTracesEncoding is an encoding extension for OTLP/JSON traces
+type jsonTracesEncoding struct {
+	component.StartFunc
+	component.ShutdownFunc
+	ptrace.JSONUnmarshaler
+}
+
+// jsonMetricsEncoding is an encoding extension for OTLP/JSON metrics
+type jsonMetricsEncoding struct {
+	component.StartFunc
+	component.ShutdownFunc
+	pmetric.JSONUnmarshaler
+}
+
+// plainExtension is an extension that is no encoding
+type plainExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+}
+
+// startEncodingReceiver starts a receiver for the consumers with cfg against a fake broker
+func startEncodingReceiver(t *testing.T, cfg *solaceconfig.Config, logs *consumertest.LogsSink, traces *consumertest.TracesSink) func(payload []byte) {
+	broker := newTestBroker()
+	r := newEncodingReceiver(t, cfg, logs, traces, broker.NewMessagingService())
+	require.NoError(t, r.Start(context.Background(), newEncodingHost()))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+	return func(payload []byte) {
+		broker.Publish("otel/telemetry", payload)
+	}
+}
+
+// newEncodingReceiver creates a receiver that serves the non-nil sinks
+func newEncodingReceiver(t *testing.T, cfg *solaceconfig.Config, logs *consumertest.LogsSink, traces *consumertest.TracesSink, opts ...interface{}) *Receiver {
+	var r *Receiver
+	var err error
+	switch {
+	case logs != nil && traces != nil:
+		r, err = NewReceiver(receivertest.NewNopSettings(typeStr), cfg, logs, traces, nil, opts...)
+	case logs != nil:
+		r, err = NewReceiver(receivertest.NewNopSettings(typeStr), cfg, logs, nil, nil, opts...)
+	default:
+		r, err = NewReceiver(receivertest.NewNopSettings(typeStr), cfg, nil, traces, nil, opts...)
+	}
+	require.NoError(t, err)
+	return r
+}
+
+func TestEncoding_LogsExtension(t *testing.T) {
+	cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+	cfg.Logs.Encoding = &lineEncodingID
+	sink := new(consumertest.LogsSink)
+	publish := startEncodingReceiver(t, cfg, sink, nil)
+
+	publish([]byte("first line\nsecond line"))
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 2 }, time.Second, time.Millisecond)
+	records := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	assert.Equal(t, "first line", records.At(0).Body().Str())
+	assert.Equal(t, "second line", records.At(1).Body().Str())
+}
+
+func TestEncoding_ExtensionErrorRejectsMessage(t *testing.T) {
+	broker := newTestBroker()
+	cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+	cfg.Logs.Encoding = &lineEncodingID
+	sink := new(consumertest.LogsSink)
+	r := newEncodingReceiver(t, cfg, sink, nil, broker.NewMessagingService())
+	require.NoError(t, r.Start(context.Background(), newEncodingHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+	broker.Publish("otel/logs", []byte{0xff, 0xfe, 0x00})
+	require.Eventually(t, func() bool { return len(broker.Rejected(testQueue)) == 1 }, time.Second, time.Millisecond)
+	assert.Zero(t, sink.LogRecordCount())
+}
+
+func TestEncoding_BuiltinDecodersForOtherSignal(t *testing.T) {
+	cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+	cfg.Logs.Encoding = &lineEncodingID
+	logs := new(consumertest.LogsSink)
+	traces := new(consumertest.TracesSink)
+	publish := startEncodingReceiver(t, cfg, logs, traces)
+
+	raw, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(testdata.RandomTraces(rand.New(rand.NewSource(1))))
+	require.NoError(t, err)
+	publish(raw)
+	require.Eventually(t, func() bool { return len(traces.AllTraces()) == 1 }, time.Second, time.Millisecond)
+
+	// text is decoded by the extension even if it is valid base64
+	publish([]byte("CgIKAA=="))
+	require.Eventually(t, func() bool { return logs.LogRecordCount() == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, "CgIKAA==", logs.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+}
+
+func TestEncoding_OnlyExtensionsOfQueueSignals(t *testing.T) {
+	broker := newSignalQueuesBroker()
+	cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+	cfg.Logs.Encoding = &lineEncodingID
+	cfg.Logs.Queues = []string{"logs"}
+	cfg.Traces.Encoding = &jsonEncodingID
+	cfg.Traces.Queues = []string{"traces"}
+	logs := new(consumertest.LogsSink)
+	traces := new(consumertest.TracesSink)
+	r := newEncodingReceiver(t, cfg, logs, traces, broker.NewMessagingService())
+	require.NoError(t, r.Start(context.Background(), newEncodingHost()))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+
+	// OTLP/JSON is text, which the logs encoding would take as a log line
+	payload, err := (&ptrace.JSONMarshaler{}).MarshalTraces(testdata.RandomTraces(rand.New(rand.NewSource(3))))
+	require.NoError(t, err)
+	require.NoError(t, broker.PublishToQueue("traces", payload))
+	require.NoError(t, broker.PublishToQueue("logs", []byte("a log line")))
+	require.Eventually(t, func() bool {
+		return broker.Acked("traces") == 1 && broker.Acked("logs") == 1
+	}, time.Second, time.Millisecond)
+	assert.Len(t, traces.AllTraces(), 1)
+	assert.Equal(t, 1, logs.LogRecordCount())
+}
+
+func TestEncoding_TracesExtension(t *testing.T) {
+	cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+	cfg.Traces.Encoding = &jsonEncodingID
+	sink := new(consumertest.TracesSink)
+	publish := startEncodingReceiver(t, cfg, nil, sink)
+
+	want := testdata.RandomTraces(rand.New(rand.NewSource(2)))
+	payload, err := (&ptrace.JSONMarshaler{}).MarshalTraces(want)
+	require.NoError(t, err)
+	publish(payload)
+	require.Eventually(t, func() bool { return len(sink.AllTraces()) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, want, sink.AllTraces()[0])
+}
+
+func TestEncoding_MetricsExtension(t *testing.T) {
+	broker := newTestBroker()
+	cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+	cfg.Metrics.Encoding = &metricsEncodingID
+	sink := new(consumertest.MetricsSink)
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, nil, nil, sink, broker.NewMessagingService())
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), newEncodingHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+	want := pmetric.NewMetrics()
+	metric := want.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("queue.depth")
+	metric.SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(7)
+	payload, err := (&pmetric.JSONMarshaler{}).MarshalMetrics(want)
+	require.NoError(t, err)
+	broker.Publish("otel/metrics", payload)
+	require.Eventually(t, func() bool { return len(sink.AllMetrics()) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, want, sink.AllMetrics()[0])
+
+	// the extension, not the simplified format, decodes every payload once configured
+	broker.Publish("otel/metrics", []byte(`{"name": "queue.depth", "type": "gauge", "data_points": [{"value": 7}]}`))
+	require.Eventually(t, func() bool { return len(sink.AllMetrics()) == 2 }, time.Second, time.Millisecond)
+	assert.Zero(t, sink.AllMetrics()[1].MetricCount())
+}
+
+func TestEncoding_StartFailsOnInvalidExtension(t *testing.T) {
+	for name, id := range map[string]component.ID{
+		"missing":      component.MustNewID("missing"),
+		"not encoding": plainExtensionID,
+		"wrong signal": jsonEncodingID,
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+			cfg.Logs.Encoding = &id
+			r := newEncodingReceiver(t, cfg, new(consumertest.LogsSink), nil, newTestBroker().NewMessagingService())
+			assert.Error(t, r.Start(context.Background(), newEncodingHost()))
+		})
+	}
+}
+
+func TestEncoding_Unmarshal(t *testing.T) {
+	cfg := createDefaultConfig().(*solaceconfig.Config)
+	require.NoError(t, confmap.NewFromStringMap(map[string]any{
+		"logs":    map[string]any{"encoding": "text_encoding/plain"},
+		"traces":  map[string]any{"encoding": "otlp_encoding"},
+		"metrics": map[string]any{"encoding": "otlp_encoding/metrics"},
+	}).Unmarshal(cfg))
+	require.NotNil(t, cfg.Logs.Encoding)
+	assert.Equal(t, component.MustNewIDWithName("text_encoding", "plain"), *cfg.Logs.Encoding)
+	assert.Equal(t, component.MustNewID("otlp_encoding"), *cfg.Traces.Encoding)
+	assert.Equal(t, component.MustNewIDWithName("otlp_encoding", "metrics"), *cfg.Metrics.Encoding)
+
+	assert.Error(t, confmap.NewFromStringMap(map[string]any{
+		"logs": map[string]any{"encoding": "not a valid/id/"},
+	}).Unmarshal(createDefaultConfig()))
+}
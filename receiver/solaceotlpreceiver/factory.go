@@ -3,31 +3,72 @@ package solaceotlpreceiver
 import (
 	"context"
 	"fmt"
+	"time"
 
 	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/xreceiver"
 )
 
 var (
 	typeStr = component.MustNewType("solaceotlp")
 )
 
-// NewFactory creates a factory for Solace OTLP receiver.
+// NewFactory creates a factory for Solace OTLP receiver. Profiles receivers
+// are only created if the receiver.solaceotlp.profiles feature gate is
+// enabled.
 func NewFactory() receiver.Factory {
-	return receiver.NewFactory(
-		typeStr,
-		createDefaultConfig,
-		receiver.WithTraces(createTracesReceiver, component.StabilityLevelStable),
-		receiver.WithLogs(createLogsReceiver, component.StabilityLevelAlpha),
+	return xreceiver.NewFactory(typeStr, createDefaultConfig,
+		xreceiver.WithTraces(createTracesReceiver, component.StabilityLevelStable),
+		xreceiver.WithLogs(createLogsReceiver, component.StabilityLevelAlpha),
+		xreceiver.WithMetrics(createMetricsReceiver, component.StabilityLevelAlpha),
+		xreceiver.WithProfiles(createProfilesReceiver, component.StabilityLevelDevelopment),
 	)
 }
 
 // createDefaultConfig creates the default configuration for the receiver
 func createDefaultConfig() component.Config {
 	return &solaceconfig.Config{
-		Queue: "telemetry",
+		Queue:          "telemetry",
+		InitialConnect: solaceconfig.InitialConnectBlock,
+		AckMode:        solaceconfig.AckModeClient,
+		ConnectRetry: solaceconfig.ConnectRetryConfig{
+			InitialInterval: time.Second,
+			MaxInterval:     30 * time.Second,
+			Multiplier:      2,
+		},
+		Timestamps: solaceconfig.TimestampsConfig{
+			Fill:       solaceconfig.FillSender,
+			MaxFuture:  10 * time.Minute,
+			MaxPast:    7 * 24 * time.Hour,
+			OutOfRange: solaceconfig.OutOfRangeFlag,
+		},
+		Dedup: solaceconfig.DedupConfig{
+			Key:        solaceconfig.DedupKeyReplicationGroupMessageID,
+			Eligible:   solaceconfig.DedupEligibleRedelivered,
+			WindowSize: 10000,
+		},
+		Buffer: solaceconfig.BufferConfig{
+			MaxBatches: 10000,
+			Retry: solaceconfig.ConnectRetryConfig{
+				InitialInterval: time.Second,
+				MaxInterval:     30 * time.Second,
+				Multiplier:      2,
+			},
+		},
+		LoadShedding: solaceconfig.LoadSheddingConfig{
+			Key:       solaceconfig.SheddingKeyPriority,
+			Default:   4,
+			Threshold: 7,
+			Bands: []solaceconfig.SheddingBandConfig{
+				{Name: "low", Min: 0},
+				{Name: "normal", Min: 4},
+			},
+			Action:   solaceconfig.SheddingActionDrop,
+			Cooldown: 10 * time.Second,
+		},
 	}
 }
 
@@ -43,10 +84,11 @@ func createTracesReceiver(
 	}
 
 	conf := cfg.(*solaceconfig.Config)
-	receiver, err := NewReceiver(settings, conf, nil, consumer)
+	receiver, err := receivers.get(settings, conf)
 	if err != nil {
 		return nil, err
 	}
+	receiver.tracesConsumer = consumer
 	return receiver, nil
 }
 
@@ -62,9 +104,30 @@ func createLogsReceiver(
 	}
 
 	conf := cfg.(*solaceconfig.Config)
-	receiver, err := NewReceiver(settings, conf, consumer, nil)
+	receiver, err := receivers.get(settings, conf)
 	if err != nil {
 		return nil, err
 	}
+	receiver.logsConsumer = consumer
+	return receiver, nil
+}
+
+// createMetricsReceiver creates a new metrics receiver
+func createMetricsReceiver(
+	_ context.Context,
+	settings receiver.Settings,
+	cfg component.Config,
+	consumer consumer.Metrics,
+) (receiver.Metrics, error) {
+	if consumer == nil {
+		return nil, fmt.Errorf("nil consumer")
+	}
+
+	conf := cfg.(*solaceconfig.Config)
+	receiver, err := receivers.get(settings, conf)
+	if err != nil {
+		return nil, err
+	}
+	receiver.metricsConsumer = consumer
 	return receiver, nil
 }
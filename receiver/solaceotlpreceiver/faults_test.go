@@ -0,0 +1,172 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pipeline"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/solacetest"
+)
+
+const faultMessages = 100
+
+// idRecorder counts how often each log record reached the consumer
+type idRecorder struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func newIDRecorder() *idRecorder {
+	return &idRecorder{counts: map[string]int{}}
+}
+
+func (r *idRecorder) consumer(t *testing.T) consumer.Logs {
+	next, err := consumer.NewLogs(func(_ context.Context, ld plog.Logs) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for _, id := range logIDs(ld) {
+			r.counts[id]++
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return next
+}
+
+// unique returns the number of distinct records seen
+func (r *idRecorder) unique() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.counts)
+}
+
+// deliveries returns the number of records seen, duplicates included
+func (r *idRecorder) deliveries() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	total := 0
+	for _, n := range r.counts {
+		total += n
+	}
+	return total
+}
+
+// faultScenario runs receivers against a broker holding faultMessages log messages
+type faultScenario struct {
+	t        *testing.T
+	broker   *solacetest.Broker
+	recorder *idRecorder
+}
+
+func newFaultScenario(t *testing.T) *faultScenario {
+	s := &faultScenario{t: t, broker: newTestBroker(), recorder: newIDRecorder()}
+	generator := &brokerGenerator{broker: s.broker, signal: pipeline.SignalLogs}
+	for i := 0; i < faultMessages; i++ {
+		generator.Generate()
+	}
+	return s
+}
+
+// start starts a receiver on service
+func (s *faultScenario) start(service any, initialConnect string) *Receiver {
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), newTestConfig(initialConnect),
+		s.recorder.consumer(s.t), consumertest.NewNop(), nil, service)
+	require.NoError(s.t, err)
+	require.NoError(s.t, r.Start(context.Background(), componenttest.NewNopHost()))
+	return r
+}
+
+// recover shuts r down and drains the queue with a receiver without faults
+func (s *faultScenario) recover(r *Receiver) {
+	require.NoError(s.t, r.Shutdown(context.Background()))
+	clean := s.start(s.broker.NewMessagingService(), solaceconfig.InitialConnectBlock)
+	require.Eventually(s.t, func() bool { return s.broker.Acked(testQueue) == faultMessages }, 5*time.Second, time.Millisecond)
+	require.NoError(s.t, clean.Shutdown(context.Background()))
+	assert.Equal(s.t, faultMessages, s.recorder.unique(), "no message is lost")
+	assert.Equal(s.t, 0, s.broker.Pending(testQueue)+s.broker.Unacked(testQueue))
+}
+
+func TestFaults_ConnectErrors(t *testing.T) {
+	s := newFaultScenario(t)
+	service := solacetest.WithFaults(s.broker.NewMessagingService(), solacetest.Faults{ConnectFailures: 3})
+	r := s.start(service, solaceconfig.InitialConnectBackground)
+
+	require.Eventually(t, func() bool { return s.broker.Acked(testQueue) == faultMessages }, 5*time.Second, time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, 3, service.Injector().Counts().ConnectErrors)
+	assert.Equal(t, faultMessages, s.recorder.unique())
+	assert.Equal(t, faultMessages, s.recorder.deliveries(), "each message is consumed once")
+}
+
+func TestFaults_BlockingConnectError(t *testing.T) {
+	s := newFaultScenario(t)
+	service := solacetest.WithFaults(s.broker.NewMessagingService(), solacetest.Faults{ConnectFailures: 1})
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), newTestConfig(solaceconfig.InitialConnectBlock),
+		s.recorder.consumer(t), consumertest.NewNop(), nil, service)
+	require.NoError(t, err)
+
+	assert.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, faultMessages, s.broker.Pending(testQueue), "messages stay on the broker")
+}
+
+func TestFaults_SlowConsumerShutdown(t *testing.T) {
+	s := newFaultScenario(t)
+	service := solacetest.WithFaults(s.broker.NewMessagingService(), solacetest.Faults{MaxLatency: 2 * time.Millisecond, Seed: 1})
+	r := s.start(service, solaceconfig.InitialConnectBlock)
+
+	require.Eventually(t, func() bool { return s.recorder.unique() >= 20 }, 5*time.Second, time.Millisecond)
+	s.recover(r)
+	assert.Equal(t, faultMessages, s.recorder.deliveries(), "drained messages are not redelivered")
+}
+
+func TestFaults_DuplicateDeliveries(t *testing.T) {
+	s := newFaultScenario(t)
+	service := solacetest.WithFaults(s.broker.NewMessagingService(), solacetest.Faults{DuplicateRate: 0.3, Seed: 2})
+	r := s.start(service, solaceconfig.InitialConnectBlock)
+
+	require.Eventually(t, func() bool { return s.broker.Acked(testQueue) == faultMessages }, 5*time.Second, time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+	duplicates := service.Injector().Counts().Duplicates
+	assert.Positive(t, duplicates)
+	assert.Equal(t, faultMessages, s.recorder.unique())
+	assert.Equal(t, faultMessages+duplicates, s.recorder.deliveries(), "duplicates are consumed at least once")
+}
+
+func TestFaults_DroppedAcks(t *testing.T) {
+	s := newFaultScenario(t)
+	service := solacetest.WithFaults(s.broker.NewMessagingService(), solacetest.Faults{AckDropRate: 0.2, Seed: 3})
+	r := s.start(service, solaceconfig.InitialConnectBlock)
+
+	require.Eventually(t, func() bool { return s.recorder.unique() == faultMessages }, 5*time.Second, time.Millisecond)
+	dropped := service.Injector().Counts().DroppedAcks
+	require.Positive(t, dropped)
+	require.Eventually(t, func() bool { return s.broker.Acked(testQueue) == faultMessages-dropped }, time.Second, time.Millisecond)
+	assert.Equal(t, dropped, s.broker.Unacked(testQueue))
+
+	s.recover(r)
+	assert.Equal(t, faultMessages+dropped, s.recorder.deliveries(), "messages with a dropped ack are redelivered")
+}
+
+func TestFaults_MidStreamTermination(t *testing.T) {
+	s := newFaultScenario(t)
+	service := solacetest.WithFaults(s.broker.NewMessagingService(), solacetest.Faults{TerminateAfter: 20})
+	r := s.start(service, solaceconfig.InitialConnectBlock)
+
+	require.Eventually(t, func() bool { return service.Injector().Counts().Terminations == 1 }, 5*time.Second, time.Millisecond)
+	require.Eventually(t, func() bool { return s.broker.Unacked(testQueue) == 0 }, time.Second, time.Millisecond)
+	assert.Less(t, s.recorder.unique(), faultMessages, "a terminated flow stops delivery")
+
+	s.recover(r)
+	assert.GreaterOrEqual(t, s.recorder.deliveries(), faultMessages)
+}
@@ -0,0 +1,86 @@
+package solaceotlpreceiver
+
+import (
+	"encoding/base64"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/testdata"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/solacetest"
+)
+
+// newFuzzReceiver creates a receiver without a broker that discards everything it decodes
+func newFuzzReceiver(f *testing.F) *Receiver {
+	cfg := newTestConfig("")
+	cfg.BrokerSpans.Enabled = true
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, consumertest.NewNop(), consumertest.NewNop(), nil)
+	if err != nil {
+		f.Fatal(err)
+	}
+	return r
+}
+
+// addProtoSeeds adds OTLP protobuf payloads of both signals to the corpus
+func addProtoSeeds(f *testing.F, encode func([]byte) []byte) {
+	rnd := rand.New(rand.NewSource(1))
+	logs, err := (&plog.ProtoMarshaler{}).MarshalLogs(testdata.RandomLogs(rnd))
+	if err != nil {
+		f.Fatal(err)
+	}
+	traces, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(testdata.RandomTraces(rnd))
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(encode(logs))
+	f.Add(encode(traces))
+}
+
+// addPayloadSeeds adds the golden test payloads to the corpus
+func addPayloadSeeds(f *testing.F) {
+	payloads, err := filepath.Glob(filepath.Join("testdata", "payloads", "*"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, path := range payloads {
+		payload, err := os.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(payload)
+	}
+}
+
+func encodeBase64(b []byte) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(b))
+}
+
+// FuzzHandleMessage feeds arbitrary payloads through the base64 protobuf,
+// JSON log and JSON trace paths of HandleMessage
+func FuzzHandleMessage(f *testing.F) {
+	addProtoSeeds(f, encodeBase64)
+	addPayloadSeeds(f)
+	r := newFuzzReceiver(f)
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		r.HandleMessage(solacetest.NewMessage("otel/fuzz", payload,
+			solacetest.WithProperty("traceparent", string(payload)),
+			solacetest.WithSenderTimestamp(time.Unix(0, int64(len(payload))))))
+	})
+}
+
+// FuzzHandleMessageBase64Proto feeds arbitrary protobuf bytes, base64-encoded,
+// to the OTLP decoders of HandleMessage
+func FuzzHandleMessageBase64Proto(f *testing.F) {
+	addProtoSeeds(f, func(b []byte) []byte { return b })
+	r := newFuzzReceiver(f)
+	f.Fuzz(func(t *testing.T, proto []byte) {
+		r.HandleMessage(solacetest.NewMessage("otel/fuzz", encodeBase64(proto)))
+	})
+}
@@ -0,0 +1,93 @@
+package solaceotlpreceiver
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/solacetest"
+)
+
+var update = flag.Bool("update", false, "regenerate the golden files in testdata/golden")
+
+// goldenResult is what the receiver made of one payload
+type goldenResult struct {
+	Format string          `json:"format,omitempty"`
+	Signal string          `json:"signal,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	Data   json.RawMessage `json:"data,omitempty"`
+}
+
+// TestGolden decodes every payload in testdata/payloads through the receiver
+// and compares the result with testdata/golden/<payload>.golden.json. Run with
+// -update to regenerate the golden files.
+func TestGolden(t *testing.T) {
+	payloads, err := filepath.Glob(filepath.Join("testdata", "payloads", "*"))
+	require.NoError(t, err)
+	require.NotEmpty(t, payloads)
+
+	for _, path := range payloads {
+		name := filepath.Base(path)
+		t.Run(name, func(t *testing.T) {
+			payload, err := os.ReadFile(path)
+			require.NoError(t, err)
+			got := decodeGolden(t, payload)
+
+			goldenPath := filepath.Join("testdata", "golden", name+".golden.json")
+			if *update {
+				require.NoError(t, os.MkdirAll(filepath.Dir(goldenPath), 0o755))
+				require.NoError(t, os.WriteFile(goldenPath, got, 0o600))
+				return
+			}
+			want, err := os.ReadFile(goldenPath)
+			require.NoError(t, err, "missing golden file; run go test -run TestGolden -update")
+			assert.Equal(t, string(want), string(got))
+		})
+	}
+}
+
+// decodeGolden passes payload through HandleMessage and renders what reached the consumers
+func decodeGolden(t *testing.T, payload []byte) []byte {
+	logs, traces, metrics := new(consumertest.LogsSink), new(consumertest.TracesSink), new(consumertest.MetricsSink)
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), newTestConfig(solaceconfig.InitialConnectBlock), logs, traces, metrics)
+	require.NoError(t, err)
+	r.HandleMessage(solacetest.NewMessage("otel/golden", payload))
+
+	var result goldenResult
+	if d, err := decodePayload(payload); err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Format, result.Signal = d.format, d.signal.String()
+	}
+	switch {
+	case len(logs.AllLogs()) > 0:
+		require.Len(t, logs.AllLogs(), 1)
+		result.Data, err = (&plog.JSONMarshaler{}).MarshalLogs(logs.AllLogs()[0])
+	case len(traces.AllTraces()) > 0:
+		require.Len(t, traces.AllTraces(), 1)
+		result.Data, err = (&ptrace.JSONMarshaler{}).MarshalTraces(traces.AllTraces()[0])
+	case len(metrics.AllMetrics()) > 0:
+		require.Len(t, metrics.AllMetrics(), 1)
+		result.Data, err = (&pmetric.JSONMarshaler{}).MarshalMetrics(metrics.AllMetrics()[0])
+	}
+	require.NoError(t, err)
+
+	out, err := json.Marshal(result)
+	require.NoError(t, err)
+	var indented bytes.Buffer
+	require.NoError(t, json.Indent(&indented, out, "", "  "))
+	indented.WriteByte('\n')
+	return indented.Bytes()
+}
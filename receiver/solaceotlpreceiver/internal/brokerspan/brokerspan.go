@@ -0,0 +1,174 @@
+package brokerspan
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"solace.dev/go/messaging/pkg/solace/message"
+)
+
+// ScopeName is the instrumentation scope of synthesized broker spans
+const ScopeName = "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver"
+
+// TraceparentProperty is the user property carrying a W3C trace context
+const TraceparentProperty = "traceparent"
+
+// Parent describes the trace context a broker span is parented on
+type Parent struct {
+	TraceID pcommon.TraceID
+	SpanID  pcommon.SpanID
+}
+
+// IsEmpty reports whether no trace context was found
+func (p Parent) IsEmpty() bool {
+	return p.TraceID.IsEmpty()
+}
+
+// ParentFromProperties reads the W3C traceparent user property of the message
+func ParentFromProperties(msg message.InboundMessage) (Parent, bool) {
+	value, ok := msg.GetProperty(TraceparentProperty)
+	if !ok {
+		return Parent{}, false
+	}
+	str, ok := value.(string)
+	if !ok {
+		return Parent{}, false
+	}
+	return parseTraceparent(str)
+}
+
+// ParentFromTraces returns the context of the first span in the payload
+func ParentFromTraces(traces ptrace.Traces) (Parent, bool) {
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		scopeSpans := traces.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < scopeSpans.Len(); j++ {
+			spans := scopeSpans.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if !span.TraceID().IsEmpty() {
+					return Parent{TraceID: span.TraceID(), SpanID: span.SpanID()}, true
+				}
+			}
+		}
+	}
+	return Parent{}, false
+}
+
+// ParentFromLogs returns the trace context of the first correlated log record in the payload
+func ParentFromLogs(logs plog.Logs) (Parent, bool) {
+	for i := 0; i < logs.ResourceLogs().Len(); i++ {
+		scopeLogs := logs.ResourceLogs().At(i).ScopeLogs()
+		for j := 0; j < scopeLogs.Len(); j++ {
+			records := scopeLogs.At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				record := records.At(k)
+				if !record.TraceID().IsEmpty() {
+					return Parent{TraceID: record.TraceID(), SpanID: record.SpanID()}, true
+				}
+			}
+		}
+	}
+	return Parent{}, false
+}
+
+// Append adds a CONSUMER span covering the broker hop of msg to traces.
+// The span starts at the sender timestamp and ends at receivedAt; it returns
+// false if the message carries no sender timestamp.
+func Append(traces ptrace.Traces, msg message.InboundMessage, receivedAt time.Time, parent Parent) bool {
+	sentAt, ok := msg.GetSenderTimestamp()
+	if !ok || sentAt.IsZero() {
+		return false
+	}
+
+	resourceSpans := traces.ResourceSpans().AppendEmpty()
+	resourceSpans.Resource().Attributes().PutStr("messaging.system", "solace")
+	scopeSpans := resourceSpans.ScopeSpans().AppendEmpty()
+	scopeSpans.Scope().SetName(ScopeName)
+	span := scopeSpans.Spans().AppendEmpty()
+
+	destination := msg.GetDestinationName()
+	if destination != "" {
+		span.SetName("receive " + destination)
+	} else {
+		span.SetName("receive")
+	}
+	span.SetKind(ptrace.SpanKindConsumer)
+
+	if parent.IsEmpty() {
+		span.SetTraceID(newTraceID())
+	} else {
+		span.SetTraceID(parent.TraceID)
+		span.SetParentSpanID(parent.SpanID)
+	}
+	span.SetSpanID(newSpanID())
+
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(sentAt))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(receivedAt))
+
+	setAttributes(span.Attributes(), msg, destination)
+	return true
+}
+
+// setAttributes applies the messaging semantic-convention attributes
+func setAttributes(attrs pcommon.Map, msg message.InboundMessage, destination string) {
+	attrs.PutStr("messaging.system", "solace")
+	attrs.PutStr("messaging.operation.type", "receive")
+	attrs.PutStr("messaging.operation.name", "receive")
+	if destination != "" {
+		attrs.PutStr("messaging.destination.name", destination)
+	}
+	if id, ok := msg.GetApplicationMessageID(); ok && id != "" {
+		attrs.PutStr("messaging.message.id", id)
+	}
+	if id, ok := msg.GetCorrelationID(); ok && id != "" {
+		attrs.PutStr("messaging.message.conversation_id", id)
+	}
+	if payload, ok := msg.GetPayloadAsBytes(); ok {
+		attrs.PutInt("messaging.message.body.size", int64(len(payload)))
+	}
+	if id, ok := msg.GetSenderID(); ok && id != "" {
+		attrs.PutStr("messaging.client.id", id)
+	}
+	if rgmid, ok := msg.GetReplicationGroupMessageID(); ok && rgmid != nil {
+		attrs.PutStr("messaging.solace.replication_group_message_id", rgmid.String())
+	}
+	if msg.IsRedelivered() {
+		attrs.PutBool("messaging.solace.redelivered", true)
+	}
+}
+
+// parseTraceparent parses a W3C traceparent header value
+func parseTraceparent(s string) (Parent, bool) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) < 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return Parent{}, false
+	}
+	var parent Parent
+	if _, err := hex.Decode(parent.TraceID[:], []byte(parts[1])); err != nil {
+		return Parent{}, false
+	}
+	if _, err := hex.Decode(parent.SpanID[:], []byte(parts[2])); err != nil {
+		return Parent{}, false
+	}
+	if parent.TraceID.IsEmpty() {
+		return Parent{}, false
+	}
+	return parent, true
+}
+
+func newTraceID() pcommon.TraceID {
+	var traceID pcommon.TraceID
+	_, _ = rand.Read(traceID[:])
+	return traceID
+}
+
+func newSpanID() pcommon.SpanID {
+	var spanID pcommon.SpanID
+	_, _ = rand.Read(spanID[:])
+	return spanID
+}
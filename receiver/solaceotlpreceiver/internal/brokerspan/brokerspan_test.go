@@ -0,0 +1,112 @@
+package brokerspan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"solace.dev/go/messaging/pkg/solace/message"
+	"solace.dev/go/messaging/pkg/solace/message/rgmid"
+	"solace.dev/go/messaging/pkg/solace/message/sdt"
+)
+
+type testMessage struct {
+	message.InboundMessage
+	sentAt     time.Time
+	properties sdt.Map
+}
+
+func (m *testMessage) GetSenderTimestamp() (time.Time, bool) {
+	return m.sentAt, !m.sentAt.IsZero()
+}
+
+func (m *testMessage) GetProperty(name string) (sdt.Data, bool) {
+	v, ok := m.properties[name]
+	return v, ok
+}
+
+func (m *testMessage) GetDestinationName() string { return "otel/traces" }
+
+func (m *testMessage) GetApplicationMessageID() (string, bool) { return "msg-1", true }
+
+func (m *testMessage) GetCorrelationID() (string, bool) { return "", false }
+
+func (m *testMessage) GetPayloadAsBytes() ([]byte, bool) { return []byte("payload"), true }
+
+func (m *testMessage) GetSenderID() (string, bool) { return "", false }
+
+func (m *testMessage) GetReplicationGroupMessageID() (rgmid.ReplicationGroupMessageID, bool) {
+	return nil, false
+}
+
+func (m *testMessage) IsRedelivered() bool { return false }
+
+func TestAppend_ParentFromProperties(t *testing.T) {
+	sentAt := time.Unix(100, 0)
+	receivedAt := sentAt.Add(250 * time.Millisecond)
+	msg := &testMessage{
+		sentAt: sentAt,
+		properties: sdt.Map{
+			TraceparentProperty: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		},
+	}
+
+	parent, ok := ParentFromProperties(msg)
+	require.True(t, ok)
+
+	traces := ptrace.NewTraces()
+	require.True(t, Append(traces, msg, receivedAt, parent))
+
+	span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	assert.Equal(t, ptrace.SpanKindConsumer, span.Kind())
+	assert.Equal(t, "receive otel/traces", span.Name())
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", span.TraceID().String())
+	assert.Equal(t, "b7ad6b7169203331", span.ParentSpanID().String())
+	assert.Equal(t, pcommon.NewTimestampFromTime(sentAt), span.StartTimestamp())
+	assert.Equal(t, pcommon.NewTimestampFromTime(receivedAt), span.EndTimestamp())
+
+	system, _ := span.Attributes().Get("messaging.system")
+	assert.Equal(t, "solace", system.Str())
+	destination, _ := span.Attributes().Get("messaging.destination.name")
+	assert.Equal(t, "otel/traces", destination.Str())
+	id, _ := span.Attributes().Get("messaging.message.id")
+	assert.Equal(t, "msg-1", id.Str())
+}
+
+func TestAppend_ParentFromPayload(t *testing.T) {
+	traces := ptrace.NewTraces()
+	span := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetTraceID(pcommon.TraceID{1, 2, 3})
+	span.SetSpanID(pcommon.SpanID{4, 5, 6})
+
+	parent, ok := ParentFromTraces(traces)
+	require.True(t, ok)
+
+	msg := &testMessage{sentAt: time.Unix(100, 0)}
+	require.True(t, Append(traces, msg, time.Unix(101, 0), parent))
+	require.Equal(t, 2, traces.ResourceSpans().Len())
+
+	brokerSpan := traces.ResourceSpans().At(1).ScopeSpans().At(0).Spans().At(0)
+	assert.Equal(t, span.TraceID(), brokerSpan.TraceID())
+	assert.Equal(t, span.SpanID(), brokerSpan.ParentSpanID())
+}
+
+func TestAppend_NoSenderTimestamp(t *testing.T) {
+	traces := ptrace.NewTraces()
+	assert.False(t, Append(traces, &testMessage{}, time.Now(), Parent{}))
+	assert.Equal(t, 0, traces.ResourceSpans().Len())
+}
+
+func TestParseTraceparent_Invalid(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"00-xyz-b7ad6b7169203331-01",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+	} {
+		_, ok := parseTraceparent(value)
+		assert.False(t, ok, value)
+	}
+}
@@ -0,0 +1,125 @@
+// Package dedup remembers the keys of the last messages a receiver consumed,
+// so that redeliveries of consumed messages can be recognized.
+package dedup
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"go.opentelemetry.io/collector/extension/xextension/storage"
+)
+
+// Storage keys: the window size, the next slot of the ring and one key per slot
+const (
+	sizeKey     = "size"
+	nextKey     = "next"
+	slotsPrefix = "slot/"
+)
+
+// Window is a bounded set of the most recently added keys. When it is full,
+// adding a key forgets the oldest one. It is safe for concurrent use.
+type Window struct {
+	mu     sync.Mutex
+	slots  []string // ring of keys in the order they were added
+	next   int      // slot of the next key
+	keys   map[string]struct{}
+	client storage.Client // persists the window, if set
+}
+
+// NewWindow creates an empty window of size keys
+func NewWindow(size int) *Window {
+	return &Window{slots: make([]string, size), keys: make(map[string]struct{}, size)}
+}
+
+// Contains reports whether key is in the window
+func (w *Window) Contains(key string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, ok := w.keys[key]
+	return ok
+}
+
+// Len returns the number of keys in the window
+func (w *Window) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.keys)
+}
+
+// Add adds key to the window and persists it if the window has a storage
+// client. The key stays in the window if persisting fails.
+func (w *Window) Add(ctx context.Context, key string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.keys[key]; ok {
+		return nil
+	}
+	slot := w.next
+	delete(w.keys, w.slots[slot])
+	w.slots[slot] = key
+	w.keys[key] = struct{}{}
+	w.next = (slot + 1) % len(w.slots)
+	if w.client == nil {
+		return nil
+	}
+	return w.client.Batch(ctx,
+		storage.SetOperation(slotKey(slot), []byte(key)),
+		storage.SetOperation(nextKey, []byte(strconv.Itoa(w.next))))
+}
+
+// Load restores the window from client and persists later keys to it. A
+// window stored with another size is discarded. It reports whether a stored
+// window was restored.
+func (w *Window) Load(ctx context.Context, client storage.Client) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.client = client
+	ops := []*storage.Operation{storage.GetOperation(sizeKey), storage.GetOperation(nextKey)}
+	if err := client.Batch(ctx, ops...); err != nil {
+		return false, fmt.Errorf("failed to load deduplication window: %w", err)
+	}
+	size, sizeErr := strconv.Atoi(string(ops[0].Value))
+	next, nextErr := strconv.Atoi(string(ops[1].Value))
+	if ops[0].Value == nil || sizeErr != nil || nextErr != nil || size != len(w.slots) || next < 0 || next >= size {
+		return false, w.resetLocked(ctx, size)
+	}
+
+	slots := make([]*storage.Operation, size)
+	for i := range slots {
+		slots[i] = storage.GetOperation(slotKey(i))
+	}
+	if err := client.Batch(ctx, slots...); err != nil {
+		return false, fmt.Errorf("failed to load deduplication window: %w", err)
+	}
+	for i, op := range slots {
+		if len(op.Value) == 0 {
+			continue
+		}
+		w.slots[i] = string(op.Value)
+		w.keys[w.slots[i]] = struct{}{}
+	}
+	w.next = next
+	return true, nil
+}
+
+// resetLocked deletes the slots of a stored window of oldSize keys and stores
+// the size of w
+func (w *Window) resetLocked(ctx context.Context, oldSize int) error {
+	var ops []*storage.Operation
+	for i := 0; i < oldSize; i++ {
+		ops = append(ops, storage.DeleteOperation(slotKey(i)))
+	}
+	ops = append(ops,
+		storage.SetOperation(sizeKey, []byte(strconv.Itoa(len(w.slots)))),
+		storage.SetOperation(nextKey, []byte(strconv.Itoa(w.next))))
+	if err := w.client.Batch(ctx, ops...); err != nil {
+		return fmt.Errorf("failed to reset deduplication window: %w", err)
+	}
+	return nil
+}
+
+func slotKey(slot int) string {
+	return slotsPrefix + strconv.Itoa(slot)
+}
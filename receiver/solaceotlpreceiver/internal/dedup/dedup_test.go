@@ -0,0 +1,71 @@
+package dedup
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/storagetest"
+)
+
+func TestWindow_ForgetsOldestKey(t *testing.T) {
+	w := NewWindow(2)
+	for _, key := range []string{"a", "b", "b", "c"} {
+		require.NoError(t, w.Add(context.Background(), key))
+	}
+	assert.False(t, w.Contains("a"))
+	assert.True(t, w.Contains("b"))
+	assert.True(t, w.Contains("c"))
+	assert.Equal(t, 2, w.Len())
+}
+
+func TestWindow_Load(t *testing.T) {
+	ctx := context.Background()
+	client := storagetest.New()
+	w := NewWindow(2)
+	restored, err := w.Load(ctx, client)
+	require.NoError(t, err)
+	assert.False(t, restored, "empty storage")
+	for _, key := range []string{"a", "b", "c"} {
+		require.NoError(t, w.Add(ctx, key))
+	}
+
+	restart := NewWindow(2)
+	restored, err = restart.Load(ctx, client)
+	require.NoError(t, err)
+	assert.True(t, restored)
+	assert.False(t, restart.Contains("a"))
+	assert.True(t, restart.Contains("b"))
+	assert.True(t, restart.Contains("c"))
+	require.NoError(t, restart.Add(ctx, "d"))
+	assert.False(t, restart.Contains("b"), "the ring continues where it stopped")
+
+	resized := NewWindow(3)
+	restored, err = resized.Load(ctx, client)
+	require.NoError(t, err)
+	assert.False(t, restored, "a window of another size is discarded")
+	assert.Zero(t, resized.Len())
+	_, ok := client.Value("slot/0")
+	assert.False(t, ok)
+	size, _ := client.Value("size")
+	assert.Equal(t, "3", string(size))
+}
+
+func TestWindow_StorageErrors(t *testing.T) {
+	ctx := context.Background()
+	client := storagetest.New()
+	client.SetErr(errors.New("disk full"))
+	_, err := NewWindow(2).Load(ctx, client)
+	require.ErrorContains(t, err, "disk full")
+
+	client.SetErr(nil)
+	w := NewWindow(2)
+	_, err = w.Load(ctx, client)
+	require.NoError(t, err)
+	client.SetErr(errors.New("disk full"))
+	require.ErrorContains(t, w.Add(ctx, "a"), "disk full")
+	assert.True(t, w.Contains("a"), "a key that was not persisted stays in memory")
+}
@@ -0,0 +1,237 @@
+// Package otlplegacy translates OTLP protobuf export requests written against
+// deprecated versions of the protocol into the current message shape.
+package otlplegacy
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Version names a deprecated OTLP message shape by the protocol versions that
+// produced it
+type Version string
+
+const (
+	// InstrumentationLibrary is instrumentation_library_spans and
+	// instrumentation_library_logs as field 1000 of a resource, written by
+	// OTLP v0.15 to v0.18 before the field was replaced by scope_spans and
+	// scope_logs
+	InstrumentationLibrary Version = "v0.15-v0.18"
+	// DeprecatedStatusCode is a span status with only the deprecated gRPC
+	// style code, written by OTLP v0.8 and earlier
+	DeprecatedStatusCode Version = "v0.8"
+)
+
+// Field numbers of the export requests
+const (
+	requestResource  protowire.Number = 1    // resource_spans, resource_logs
+	resourceScope    protowire.Number = 2    // scope_spans, scope_logs
+	resourceLibrary  protowire.Number = 1000 // instrumentation_library_spans, instrumentation_library_logs
+	scopeRecord      protowire.Number = 2    // spans, log_records
+	spanStatus       protowire.Number = 15
+	statusDeprecated protowire.Number = 1 // deprecated_code
+	statusCode       protowire.Number = 3
+
+	statusCodeError = 2
+)
+
+// Translate rewrites an ExportTraceServiceRequest or ExportLogsServiceRequest
+// with deprecated fields into the current shape and reports the deprecated
+// versions found. Current and malformed payloads are returned unchanged, so
+// the common case does not allocate.
+func Translate(payload []byte) ([]byte, []Version) {
+	versions := detect(payload)
+	if len(versions) == 0 {
+		return payload, nil
+	}
+	out, ok := translateRequest(payload)
+	if !ok {
+		return payload, nil
+	}
+	return out, versions
+}
+
+// detect returns the deprecated versions of an export request
+func detect(payload []byte) []Version {
+	var library, status bool
+	request := fields{b: payload}
+	for request.next() {
+		if !request.isBytes(requestResource) {
+			continue
+		}
+		resource := fields{b: request.value}
+		for resource.next() {
+			switch {
+			case resource.isBytes(resourceLibrary):
+				library = true
+				status = status || hasDeprecatedStatus(resource.value)
+			case resource.isBytes(resourceScope):
+				status = status || hasDeprecatedStatus(resource.value)
+			}
+		}
+	}
+	var versions []Version
+	if library {
+		versions = append(versions, InstrumentationLibrary)
+	}
+	if status {
+		versions = append(versions, DeprecatedStatusCode)
+	}
+	return versions
+}
+
+// hasDeprecatedStatus reports whether a span of a scope has a deprecated status
+func hasDeprecatedStatus(scope []byte) bool {
+	records := fields{b: scope}
+	for records.next() {
+		if !records.isBytes(scopeRecord) {
+			continue
+		}
+		record := fields{b: records.value}
+		for record.next() {
+			if record.isBytes(spanStatus) {
+				if _, ok := deprecatedCode(record.value); ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// deprecatedCode returns the deprecated code of a status without a current code
+func deprecatedCode(status []byte) (uint64, bool) {
+	var code uint64
+	var deprecated, current bool
+	s := fields{b: status}
+	for s.next() {
+		switch {
+		case s.num == statusDeprecated && s.typ == protowire.VarintType:
+			code, deprecated = s.varint, true
+		case s.num == statusCode:
+			current = true
+		}
+	}
+	return code, deprecated && !current && !s.err
+}
+
+func translateRequest(payload []byte) ([]byte, bool) {
+	out := make([]byte, 0, len(payload)+16)
+	request := fields{b: payload}
+	for request.next() {
+		if !request.isBytes(requestResource) {
+			out = append(out, request.raw...)
+			continue
+		}
+		resource, ok := translateResource(request.value)
+		if !ok {
+			return nil, false
+		}
+		out = protowire.AppendTag(out, requestResource, protowire.BytesType)
+		out = protowire.AppendBytes(out, resource)
+	}
+	return out, !request.err
+}
+
+// translateResource moves instrumentation library blocks to the scope field;
+// both share their wire format
+func translateResource(b []byte) ([]byte, bool) {
+	out := make([]byte, 0, len(b))
+	resource := fields{b: b}
+	for resource.next() {
+		if !resource.isBytes(resourceLibrary) && !resource.isBytes(resourceScope) {
+			out = append(out, resource.raw...)
+			continue
+		}
+		scope, ok := translateScope(resource.value)
+		if !ok {
+			return nil, false
+		}
+		out = protowire.AppendTag(out, resourceScope, protowire.BytesType)
+		out = protowire.AppendBytes(out, scope)
+	}
+	return out, !resource.err
+}
+
+func translateScope(b []byte) ([]byte, bool) {
+	out := make([]byte, 0, len(b))
+	scope := fields{b: b}
+	for scope.next() {
+		if !scope.isBytes(scopeRecord) {
+			out = append(out, scope.raw...)
+			continue
+		}
+		record, ok := translateRecord(scope.value)
+		if !ok {
+			return nil, false
+		}
+		out = protowire.AppendTag(out, scopeRecord, protowire.BytesType)
+		out = protowire.AppendBytes(out, record)
+	}
+	return out, !scope.err
+}
+
+// translateRecord sets the current status code of a span from its deprecated
+// code: OK stays unset, every other code is an error
+func translateRecord(b []byte) ([]byte, bool) {
+	out := make([]byte, 0, len(b)+2)
+	record := fields{b: b}
+	for record.next() {
+		if !record.isBytes(spanStatus) {
+			out = append(out, record.raw...)
+			continue
+		}
+		status := record.value
+		if code, ok := deprecatedCode(status); ok && code != 0 {
+			status = protowire.AppendTag(append([]byte(nil), status...), statusCode, protowire.VarintType)
+			status = protowire.AppendVarint(status, statusCodeError)
+		}
+		out = protowire.AppendTag(out, spanStatus, protowire.BytesType)
+		out = protowire.AppendBytes(out, status)
+	}
+	return out, !record.err
+}
+
+// fields iterates over the fields of an encoded message
+type fields struct {
+	b      []byte
+	num    protowire.Number
+	typ    protowire.Type
+	raw    []byte // the whole field, tag included
+	value  []byte // the value of a length-delimited field
+	varint uint64 // the value of a varint field
+	err    bool
+}
+
+// next advances to the next field. It returns false at the end of the
+// message and on malformed input, which sets err.
+func (f *fields) next() bool {
+	if len(f.b) == 0 || f.err {
+		return false
+	}
+	num, typ, tagLen := protowire.ConsumeTag(f.b)
+	if tagLen < 0 {
+		f.err = true
+		return false
+	}
+	valueLen := protowire.ConsumeFieldValue(num, typ, f.b[tagLen:])
+	if valueLen < 0 {
+		f.err = true
+		return false
+	}
+	f.num, f.typ = num, typ
+	f.raw = f.b[:tagLen+valueLen]
+	f.value, f.varint = nil, 0
+	switch typ {
+	case protowire.BytesType:
+		f.value, _ = protowire.ConsumeBytes(f.b[tagLen:])
+	case protowire.VarintType:
+		f.varint, _ = protowire.ConsumeVarint(f.b[tagLen:])
+	}
+	f.b = f.b[tagLen+valueLen:]
+	return true
+}
+
+// isBytes reports whether the current field is the length-delimited field num
+func (f *fields) isBytes(num protowire.Number) bool {
+	return f.num == num && f.typ == protowire.BytesType
+}
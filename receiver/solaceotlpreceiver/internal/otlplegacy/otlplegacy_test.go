@@ -0,0 +1,138 @@
+package otlplegacy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// message encodes length-delimited fields given as number/value pairs
+func message(fields ...any) []byte {
+	var b []byte
+	for i := 0; i < len(fields); i += 2 {
+		num := protowire.Number(fields[i].(int))
+		switch v := fields[i+1].(type) {
+		case []byte:
+			b = protowire.AppendTag(b, num, protowire.BytesType)
+			b = protowire.AppendBytes(b, v)
+		case string:
+			b = protowire.AppendTag(b, num, protowire.BytesType)
+			b = protowire.AppendString(b, v)
+		case uint64:
+			b = protowire.AppendTag(b, num, protowire.VarintType)
+			b = protowire.AppendVarint(b, v)
+		}
+	}
+	return b
+}
+
+// legacyTraces returns a v0.15 request with instrumentation_library_spans
+// whose spans carry the given statuses
+func legacyTraces(statuses ...[]byte) []byte {
+	library := []any{1, message(1, "lib", 2, "1.0")}
+	for _, status := range statuses {
+		library = append(library, 2, message(5, "span", 15, status))
+	}
+	return message(1, message(
+		1, message(1, message(1, "service.name", 2, message(1, "api"))),
+		1000, message(library...),
+	))
+}
+
+func TestTranslate_InstrumentationLibrarySpans(t *testing.T) {
+	payload, versions := Translate(legacyTraces(message(2, "ok")))
+	assert.Equal(t, []Version{InstrumentationLibrary}, versions)
+
+	traces, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(payload)
+	require.NoError(t, err)
+	require.Equal(t, 1, traces.SpanCount())
+	resourceSpans := traces.ResourceSpans().At(0)
+	assert.Equal(t, map[string]any{"service.name": "api"}, resourceSpans.Resource().Attributes().AsRaw())
+	scopeSpans := resourceSpans.ScopeSpans().At(0)
+	assert.Equal(t, "lib", scopeSpans.Scope().Name())
+	assert.Equal(t, "1.0", scopeSpans.Scope().Version())
+	assert.Equal(t, "span", scopeSpans.Spans().At(0).Name())
+	assert.Equal(t, "ok", scopeSpans.Spans().At(0).Status().Message())
+}
+
+func TestTranslate_InstrumentationLibraryLogs(t *testing.T) {
+	payload := message(1, message(1000, message(
+		1, message(1, "lib"),
+		2, message(5, message(1, "hello")),
+		2, message(5, message(1, "world")),
+	)))
+	payload, versions := Translate(payload)
+	assert.Equal(t, []Version{InstrumentationLibrary}, versions)
+
+	logs, err := (&plog.ProtoUnmarshaler{}).UnmarshalLogs(payload)
+	require.NoError(t, err)
+	require.Equal(t, 2, logs.LogRecordCount())
+	scopeLogs := logs.ResourceLogs().At(0).ScopeLogs().At(0)
+	assert.Equal(t, "lib", scopeLogs.Scope().Name())
+	assert.Equal(t, "world", scopeLogs.LogRecords().At(1).Body().Str())
+}
+
+func TestTranslate_DeprecatedStatusCode(t *testing.T) {
+	payload, versions := Translate(legacyTraces(
+		message(1, uint64(2), 2, "unknown"),  // deprecated UNKNOWN_ERROR
+		message(1, uint64(0)),                // deprecated OK
+		message(1, uint64(13), 3, uint64(1)), // current code wins
+	))
+	assert.Equal(t, []Version{InstrumentationLibrary, DeprecatedStatusCode}, versions)
+
+	traces, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(payload)
+	require.NoError(t, err)
+	spans := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	require.Equal(t, 3, spans.Len())
+	assert.Equal(t, ptrace.StatusCodeError, spans.At(0).Status().Code())
+	assert.Equal(t, "unknown", spans.At(0).Status().Message())
+	assert.Equal(t, ptrace.StatusCodeUnset, spans.At(1).Status().Code())
+	assert.Equal(t, ptrace.StatusCodeOk, spans.At(2).Status().Code())
+}
+
+func TestTranslate_CurrentPayloadUnchanged(t *testing.T) {
+	traces := ptrace.NewTraces()
+	span := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("span")
+	span.Status().SetCode(ptrace.StatusCodeError)
+	want, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(traces)
+	require.NoError(t, err)
+
+	got, versions := Translate(want)
+	assert.Empty(t, versions)
+	assert.Same(t, &want[0], &got[0], "current payloads are not copied")
+}
+
+func TestTranslate_MalformedPayloadUnchanged(t *testing.T) {
+	payload := legacyTraces(message(1, uint64(2)))
+	truncated := payload[:len(payload)-1]
+	got, versions := Translate(truncated)
+	assert.Empty(t, versions)
+	assert.Equal(t, truncated, got)
+}
+
+func FuzzTranslate(f *testing.F) {
+	f.Add(legacyTraces(message(1, uint64(2)), message(2, "ok")))
+	f.Add(message(1, message(1000, message(2, message(5, message(1, "hello"))))))
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		before, beforeErr := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(payload)
+		translated, versions := Translate(payload)
+		if len(versions) == 0 {
+			if string(translated) != string(payload) {
+				t.Fatal("payload without deprecated fields was changed")
+			}
+			return
+		}
+		after, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(translated)
+		if beforeErr == nil && err != nil {
+			t.Fatalf("translation broke a valid payload: %v", err)
+		}
+		if beforeErr == nil && after.SpanCount() < before.SpanCount() {
+			t.Fatalf("translation lost spans: %d, want at least %d", after.SpanCount(), before.SpanCount())
+		}
+	})
+}
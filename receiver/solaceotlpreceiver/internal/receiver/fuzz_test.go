@@ -0,0 +1,32 @@
+package receiver
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/solacetest"
+)
+
+func newFuzzBase() *BaseReceiver {
+	return NewBaseReceiver(receivertest.NewNopSettings(component.MustNewType("solaceotlp")), &solaceconfig.Config{})
+}
+
+// FuzzLogsReceiver feeds arbitrary payloads to the logs decoder
+func FuzzLogsReceiver(f *testing.F) {
+	r := NewLogsReceiver(newFuzzBase(), consumertest.NewNop())
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		r.HandleMessage(solacetest.NewMessage("otel/logs", payload))
+	})
+}
+
+// FuzzTracesReceiver feeds arbitrary payloads to the traces decoder
+func FuzzTracesReceiver(f *testing.F) {
+	r := NewTracesReceiver(newFuzzBase(), consumertest.NewNop())
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		r.HandleMessage(solacetest.NewMessage("otel/traces", payload))
+	})
+}
@@ -1,21 +1,17 @@
 package receiver
 
 import (
-	"encoding/hex"
-
 	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/util"
 )
 
 // HexStringToTraceID converts a hex string to a TraceID
 func HexStringToTraceID(s string) (pcommon.TraceID, error) {
-	var traceID pcommon.TraceID
-	_, err := hex.Decode(traceID[:], []byte(s))
-	return traceID, err
+	return util.HexStringToTraceID(s)
 }
 
 // HexStringToSpanID converts a hex string to a SpanID
 func HexStringToSpanID(s string) (pcommon.SpanID, error) {
-	var spanID pcommon.SpanID
-	_, err := hex.Decode(spanID[:], []byte(s))
-	return spanID, err
+	return util.HexStringToSpanID(s)
 }
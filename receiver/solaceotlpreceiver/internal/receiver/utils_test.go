@@ -32,3 +32,39 @@ func TestHexStringToSpanID_Invalid(t *testing.T) {
 	_, err := util.HexStringToSpanID(hexStr)
 	assert.Error(t, err)
 }
+
+func TestHexStringToTraceID_Oversized(t *testing.T) {
+	hexStr := "00112233445566778899aabbccddeeff00"
+	_, err := util.HexStringToTraceID(hexStr)
+	assert.Error(t, err)
+}
+
+func TestHexStringToSpanID_Oversized(t *testing.T) {
+	hexStr := "001122334455667788"
+	_, err := util.HexStringToSpanID(hexStr)
+	assert.Error(t, err)
+}
+
+func TestParseTraceID_HexAndBase64(t *testing.T) {
+	for _, s := range []string{"00112233445566778899aabbccddeeff", "ABEiM0RVZneImaq7zN3u/w==", "ABEiM0RVZneImaq7zN3u_w"} {
+		traceID, err := util.ParseTraceID(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, "00112233445566778899aabbccddeeff", traceID.String(), s)
+	}
+}
+
+func TestParseSpanID_HexAndBase64(t *testing.T) {
+	for _, s := range []string{"0011223344556677", "ABEiM0RVZnc=", "ABEiM0RVZnc"} {
+		spanID, err := util.ParseSpanID(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, "0011223344556677", spanID.String(), s)
+	}
+}
+
+func TestParseTraceID_Invalid(t *testing.T) {
+	for _, s := range []string{"invalidhex", "00112233445566778899aabbccddeeff00", "ABEiM0RVZnc="} {
+		traceID, err := util.ParseTraceID(s)
+		assert.Error(t, err, s)
+		assert.True(t, traceID.IsEmpty(), s)
+	}
+}
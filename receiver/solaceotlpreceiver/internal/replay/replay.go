@@ -0,0 +1,37 @@
+// Package replay parses where a Solace message replay starts.
+package replay
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"solace.dev/go/messaging"
+	"solace.dev/go/messaging/pkg/solace/config"
+)
+
+// All replays all messages in the replay log
+const All = "all"
+
+// rgmidPrefix starts the textual form of a replication group message ID
+const rgmidPrefix = "rmid1:"
+
+// ParseStrategy parses the start of a replay: "all", an RFC 3339 time or a
+// replication group message ID, after which the replay starts
+func ParseStrategy(from string) (config.ReplayStrategy, error) {
+	switch {
+	case from == All:
+		return config.ReplayStrategyAllMessages(), nil
+	case strings.HasPrefix(from, rgmidPrefix):
+		id, err := messaging.ReplicationGroupMessageIDOf(from)
+		if err != nil {
+			return config.ReplayStrategy{}, fmt.Errorf("invalid replication group message ID %q: %w", from, err)
+		}
+		return config.ReplayStrategyReplicationGroupMessageID(id), nil
+	}
+	date, err := time.Parse(time.RFC3339, from)
+	if err != nil {
+		return config.ReplayStrategy{}, fmt.Errorf("replay must start from %q, an RFC 3339 time or a replication group message ID, got %q", All, from)
+	}
+	return config.ReplayStrategyTimeBased(date), nil
+}
@@ -0,0 +1,32 @@
+package replay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"solace.dev/go/messaging/pkg/solace/config"
+)
+
+func TestParseStrategy(t *testing.T) {
+	strategy, err := ParseStrategy(All)
+	require.NoError(t, err)
+	assert.Equal(t, config.PersistentReplayAll, strategy.GetStrategy())
+
+	strategy, err = ParseStrategy("2025-06-02T09:30:00+02:00")
+	require.NoError(t, err)
+	assert.Equal(t, config.PersistentReplayTimeBased, strategy.GetStrategy())
+	assert.True(t, time.Date(2025, 6, 2, 7, 30, 0, 0, time.UTC).Equal(strategy.GetData().(time.Time)))
+
+	const id = "rmid1:0f4a0-5ce5e7f0c7a-00000000-00000002"
+	strategy, err = ParseStrategy(id)
+	require.NoError(t, err)
+	assert.Equal(t, config.PersistentReplayIDBased, strategy.GetStrategy())
+	assert.Equal(t, id, strategy.GetData().(interface{ String() string }).String())
+
+	for _, from := range []string{"", "yesterday", "2025-06-02", "rmid1:nope"} {
+		_, err := ParseStrategy(from)
+		assert.Error(t, err, from)
+	}
+}
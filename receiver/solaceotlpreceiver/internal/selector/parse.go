@@ -0,0 +1,435 @@
+package selector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenKeyword
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of selector"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// keywords of the selector syntax, matched case-insensitively
+var keywords = map[string]bool{
+	"AND": true, "OR": true, "NOT": true, "BETWEEN": true, "IN": true,
+	"LIKE": true, "ESCAPE": true, "IS": true, "NULL": true, "TRUE": true, "FALSE": true,
+}
+
+type lexer struct {
+	input string
+	pos   int
+}
+
+// next returns the next token of the input
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.input) && isSpace(l.input[l.pos]) {
+		l.pos++
+	}
+	start := l.pos
+	if l.pos >= len(l.input) {
+		return token{kind: tokenEOF, pos: start}, nil
+	}
+	r, size := utf8.DecodeRuneInString(l.input[l.pos:])
+	switch {
+	case r == '\'':
+		return l.string()
+	case r >= '0' && r <= '9' || r == '.' && l.pos+1 < len(l.input) && isDigit(l.input[l.pos+1]):
+		return l.number()
+	case isIdentifierStart(r):
+		l.pos += size
+		for l.pos < len(l.input) {
+			r, size := utf8.DecodeRuneInString(l.input[l.pos:])
+			if !isIdentifierPart(r) {
+				break
+			}
+			l.pos += size
+		}
+		text := l.input[start:l.pos]
+		if upper := strings.ToUpper(text); keywords[upper] {
+			return token{kind: tokenKeyword, text: upper, pos: start}, nil
+		}
+		return token{kind: tokenIdentifier, text: text, pos: start}, nil
+	}
+	for _, op := range []string{"<>", "<=", ">=", "=", "<", ">", "+", "-", "*", "/", "(", ")", ","} {
+		if strings.HasPrefix(l.input[l.pos:], op) {
+			l.pos += len(op)
+			return token{kind: tokenOperator, text: op, pos: start}, nil
+		}
+	}
+	return token{}, fmt.Errorf("unexpected character %q at position %d", r, start)
+}
+
+// string lexes a quoted string literal, in which two quotes stand for one
+func (l *lexer) string() (token, error) {
+	start := l.pos
+	var b strings.Builder
+	for l.pos++; l.pos < len(l.input); l.pos++ {
+		c := l.input[l.pos]
+		if c != '\'' {
+			b.WriteByte(c)
+			continue
+		}
+		if l.pos+1 < len(l.input) && l.input[l.pos+1] == '\'' {
+			b.WriteByte(c)
+			l.pos++
+			continue
+		}
+		l.pos++
+		return token{kind: tokenString, text: b.String(), pos: start}, nil
+	}
+	return token{}, fmt.Errorf("unterminated string at position %d", start)
+}
+
+// number lexes an integer or decimal literal with an optional exponent
+func (l *lexer) number() (token, error) {
+	start := l.pos
+	for l.pos < len(l.input) && (isDigit(l.input[l.pos]) || l.input[l.pos] == '.') {
+		l.pos++
+	}
+	if l.pos < len(l.input) && (l.input[l.pos] == 'e' || l.input[l.pos] == 'E') {
+		l.pos++
+		if l.pos < len(l.input) && (l.input[l.pos] == '+' || l.input[l.pos] == '-') {
+			l.pos++
+		}
+		for l.pos < len(l.input) && isDigit(l.input[l.pos]) {
+			l.pos++
+		}
+	}
+	return token{kind: tokenNumber, text: l.input[start:l.pos], pos: start}, nil
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentifierStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_' || r == '$'
+}
+
+func isIdentifierPart(r rune) bool {
+	return isIdentifierStart(r) || unicode.IsDigit(r)
+}
+
+// parser parses selectors by recursive descent, from the lowest precedence:
+// OR, AND, NOT, predicates, + and -, * and /, unary + and -
+type parser struct {
+	lexer lexer
+	tok   token
+}
+
+func (p *parser) next() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf(format+" at position %d", append(args, p.tok.pos)...)
+}
+
+// accept consumes the current token if it is the keyword or operator text
+func (p *parser) accept(text string) (bool, error) {
+	if (p.tok.kind != tokenKeyword && p.tok.kind != tokenOperator) || p.tok.text != text {
+		return false, nil
+	}
+	return true, p.next()
+}
+
+// expect consumes the keyword or operator text
+func (p *parser) expect(text string) error {
+	ok, err := p.accept(text)
+	if err == nil && !ok {
+		err = p.errorf("expected %q, got %s", text, p.tok)
+	}
+	return err
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	for err == nil {
+		var ok bool
+		if ok, err = p.accept("OR"); err != nil || !ok {
+			break
+		}
+		var right node
+		if right, err = p.parseAnd(); err == nil {
+			left = or{left, right}
+		}
+	}
+	return left, err
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	for err == nil {
+		var ok bool
+		if ok, err = p.accept("AND"); err != nil || !ok {
+			break
+		}
+		var right node
+		if right, err = p.parseNot(); err == nil {
+			left = and{left, right}
+		}
+	}
+	return left, err
+}
+
+func (p *parser) parseNot() (node, error) {
+	ok, err := p.accept("NOT")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		operand, err := p.parseNot()
+		return not{operand}, err
+	}
+	return p.parsePredicate()
+}
+
+// parsePredicate parses a comparison, BETWEEN, IN, LIKE or IS NULL
+func (p *parser) parsePredicate() (node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenOperator {
+		switch op := p.tok.text; op {
+		case "=", "<>", "<", "<=", ">", ">=":
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			right, err := p.parseSum()
+			return comparison{op, left, right}, err
+		}
+	}
+	if p.tok.kind != tokenKeyword {
+		return left, nil
+	}
+	if p.tok.text == "IS" {
+		return p.parseIsNull(left)
+	}
+	negated, err := p.accept("NOT")
+	if err != nil {
+		return nil, err
+	}
+	switch p.tok.text {
+	case "BETWEEN":
+		return p.parseBetween(left, negated)
+	case "IN":
+		return p.parseIn(left, negated)
+	case "LIKE":
+		return p.parseLike(left, negated)
+	}
+	if negated {
+		return nil, p.errorf("expected BETWEEN, IN or LIKE after NOT, got %s", p.tok)
+	}
+	return left, nil
+}
+
+func (p *parser) parseBetween(operand node, negated bool) (node, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	low, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("AND"); err != nil {
+		return nil, err
+	}
+	high, err := p.parseSum()
+	return between{operand, low, high, negated}, err
+}
+
+func (p *parser) parseIn(operand node, negated bool) (node, error) {
+	id, ok := operand.(identifier)
+	if !ok {
+		return nil, p.errorf("IN requires a property")
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var values []string
+	for {
+		if p.tok.kind != tokenString {
+			return nil, p.errorf("IN requires string literals, got %s", p.tok)
+		}
+		values = append(values, p.tok.text)
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		more, err := p.accept(",")
+		if err != nil {
+			return nil, err
+		}
+		if !more {
+			break
+		}
+	}
+	return in{id, values, negated}, p.expect(")")
+}
+
+func (p *parser) parseLike(operand node, negated bool) (node, error) {
+	id, ok := operand.(identifier)
+	if !ok {
+		return nil, p.errorf("LIKE requires a property")
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokenString {
+		return nil, p.errorf("LIKE requires a string pattern, got %s", p.tok)
+	}
+	pattern := p.tok.text
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	var escape rune
+	ok, err := p.accept("ESCAPE")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if p.tok.kind != tokenString || utf8.RuneCountInString(p.tok.text) != 1 {
+			return nil, p.errorf("ESCAPE requires a single character, got %s", p.tok)
+		}
+		escape, _ = utf8.DecodeRuneInString(p.tok.text)
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+	re, err := likePattern(pattern, escape)
+	if err != nil {
+		return nil, err
+	}
+	return like{id, re, negated}, nil
+}
+
+func (p *parser) parseIsNull(operand node) (node, error) {
+	id, ok := operand.(identifier)
+	if !ok {
+		return nil, p.errorf("IS NULL requires a property")
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	negated, err := p.accept("NOT")
+	if err != nil {
+		return nil, err
+	}
+	return isNull{id, negated}, p.expect("NULL")
+}
+
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	for err == nil && p.tok.kind == tokenOperator && (p.tok.text == "+" || p.tok.text == "-") {
+		op := p.tok.text[0]
+		if err = p.next(); err != nil {
+			break
+		}
+		var right node
+		if right, err = p.parseProduct(); err == nil {
+			left = arithmetic{op, left, right}
+		}
+	}
+	return left, err
+}
+
+func (p *parser) parseProduct() (node, error) {
+	left, err := p.parseUnary()
+	for err == nil && p.tok.kind == tokenOperator && (p.tok.text == "*" || p.tok.text == "/") {
+		op := p.tok.text[0]
+		if err = p.next(); err != nil {
+			break
+		}
+		var right node
+		if right, err = p.parseUnary(); err == nil {
+			left = arithmetic{op, left, right}
+		}
+	}
+	return left, err
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.tok.kind == tokenOperator && (p.tok.text == "+" || p.tok.text == "-") {
+		minus := p.tok.text == "-"
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		operand, err := p.parseUnary()
+		if minus {
+			return negate{operand}, err
+		}
+		return operand, err
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.tok
+	switch {
+	case tok.kind == tokenIdentifier:
+		return identifier{tok.text}, p.next()
+	case tok.kind == tokenString:
+		return literal{tok.text}, p.next()
+	case tok.kind == tokenNumber:
+		v, err := parseNumber(tok.text)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", tok.text)
+		}
+		return literal{v}, p.next()
+	case tok.kind == tokenKeyword && (tok.text == "TRUE" || tok.text == "FALSE"):
+		return literal{tok.text == "TRUE"}, p.next()
+	case tok.kind == tokenOperator && tok.text == "(":
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return expr, p.expect(")")
+	}
+	return nil, p.errorf("unexpected %s", tok)
+}
+
+// parseNumber parses an integer literal as int64 and other numbers as float64
+func parseNumber(text string) (value, error) {
+	if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return i, nil
+	}
+	return strconv.ParseFloat(text, 64)
+}
@@ -0,0 +1,351 @@
+// Package selector evaluates Solace message selectors, the SQL92 subset of JMS
+// message selectors, on message properties.
+package selector
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Selector is a parsed message selector
+type Selector struct {
+	source string
+	root   node
+}
+
+// Lookup returns the value of a message property or header field. It
+// reports false if the message has no such property.
+type Lookup func(name string) (any, bool)
+
+// Parse parses a selector expression such as
+// "env = 'prod' AND severity >= 13". It supports comparisons, arithmetic,
+// AND, OR, NOT, [NOT] BETWEEN, [NOT] IN, [NOT] LIKE with ESCAPE and
+// IS [NOT] NULL.
+func Parse(s string) (*Selector, error) {
+	p := &parser{lexer: lexer{input: s}}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenEOF {
+		return nil, fmt.Errorf("selector is empty")
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokenEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	return &Selector{source: s, root: root}, nil
+}
+
+// String returns the selector expression
+func (s *Selector) String() string {
+	return s.source
+}
+
+// Matches reports whether the selector is true for the properties of lookup.
+// Like on the broker, an expression that is unknown, for example because a
+// property is missing, does not match.
+func (s *Selector) Matches(lookup Lookup) bool {
+	result, _ := s.root.eval(lookup).(bool)
+	return result
+}
+
+// A value is nil (unknown), bool, int64, float64 or string
+type value = any
+
+type node interface {
+	eval(Lookup) value
+}
+
+// normalize converts a property value to a selector value
+func normalize(v any) value {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if u := rv.Uint(); u <= math.MaxInt64 {
+			return int64(u)
+		}
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	}
+	return nil
+}
+
+type literal struct{ v value }
+
+func (n literal) eval(Lookup) value { return n.v }
+
+type identifier struct{ name string }
+
+func (n identifier) eval(lookup Lookup) value {
+	v, ok := lookup(n.name)
+	if !ok {
+		return nil
+	}
+	return normalize(v)
+}
+
+type and struct{ left, right node }
+
+func (n and) eval(lookup Lookup) value {
+	left, right := n.left.eval(lookup), n.right.eval(lookup)
+	if left == false || right == false {
+		return false
+	}
+	if left == true && right == true {
+		return true
+	}
+	return nil
+}
+
+type or struct{ left, right node }
+
+func (n or) eval(lookup Lookup) value {
+	left, right := n.left.eval(lookup), n.right.eval(lookup)
+	if left == true || right == true {
+		return true
+	}
+	if left == false && right == false {
+		return false
+	}
+	return nil
+}
+
+type not struct{ operand node }
+
+func (n not) eval(lookup Lookup) value {
+	if b, ok := n.operand.eval(lookup).(bool); ok {
+		return !b
+	}
+	return nil
+}
+
+type negate struct{ operand node }
+
+func (n negate) eval(lookup Lookup) value {
+	switch v := n.operand.eval(lookup).(type) {
+	case int64:
+		return -v
+	case float64:
+		return -v
+	}
+	return nil
+}
+
+type comparison struct {
+	op          string
+	left, right node
+}
+
+func (n comparison) eval(lookup Lookup) value {
+	return compare(n.op, n.left.eval(lookup), n.right.eval(lookup))
+}
+
+// compare applies a comparison operator. Strings and booleans only support
+// = and <>; values of different types are unknown.
+func compare(op string, left, right value) value {
+	if order, ok := numericOrder(left, right); ok {
+		return ordered(op, order, 0)
+	}
+	if left == nil || right == nil || reflect.TypeOf(left) != reflect.TypeOf(right) {
+		return nil
+	}
+	switch left.(type) {
+	case string, bool:
+		switch op {
+		case "=":
+			return left == right
+		case "<>":
+			return left != right
+		}
+	}
+	return nil
+}
+
+// numericOrder returns -1, 0 or 1 if left is less than, equal to or greater
+// than right, comparing integers exactly. It reports false if either value is
+// not a number.
+func numericOrder(left, right value) (int, bool) {
+	if li, ok := left.(int64); ok {
+		if ri, ok := right.(int64); ok {
+			return cmp.Compare(li, ri), true
+		}
+	}
+	l, lok := number(left)
+	r, rok := number(right)
+	if !lok || !rok || math.IsNaN(l) || math.IsNaN(r) {
+		return 0, false
+	}
+	return cmp.Compare(l, r), true
+}
+
+// ordered applies a comparison operator to ordered values
+func ordered[T cmp.Ordered](op string, l, r T) value {
+	switch op {
+	case "=":
+		return l == r
+	case "<>":
+		return l != r
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	case ">=":
+		return l >= r
+	}
+	return nil
+}
+
+func number(v value) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+type arithmetic struct {
+	op          byte
+	left, right node
+}
+
+func (n arithmetic) eval(lookup Lookup) value {
+	left, right := n.left.eval(lookup), n.right.eval(lookup)
+	li, lint := left.(int64)
+	ri, rint := right.(int64)
+	if lint && rint {
+		switch n.op {
+		case '+':
+			return li + ri
+		case '-':
+			return li - ri
+		case '*':
+			return li * ri
+		case '/':
+			if ri == 0 {
+				return nil
+			}
+			if li%ri == 0 {
+				return li / ri
+			}
+		}
+	}
+	l, lok := number(left)
+	r, rok := number(right)
+	if !lok || !rok {
+		return nil
+	}
+	switch n.op {
+	case '+':
+		return l + r
+	case '-':
+		return l - r
+	case '*':
+		return l * r
+	}
+	if r == 0 {
+		return nil
+	}
+	return l / r
+}
+
+type between struct {
+	operand, low, high node
+	negated            bool
+}
+
+func (n between) eval(lookup Lookup) value {
+	v := n.operand.eval(lookup)
+	result := and{literal{compare(">=", v, n.low.eval(lookup))}, literal{compare("<=", v, n.high.eval(lookup))}}.eval(lookup)
+	if n.negated {
+		return not{literal{result}}.eval(lookup)
+	}
+	return result
+}
+
+type in struct {
+	operand identifier
+	values  []string
+	negated bool
+}
+
+func (n in) eval(lookup Lookup) value {
+	s, ok := n.operand.eval(lookup).(string)
+	if !ok {
+		return nil
+	}
+	for _, v := range n.values {
+		if s == v {
+			return !n.negated
+		}
+	}
+	return n.negated
+}
+
+type like struct {
+	operand identifier
+	pattern *regexp.Regexp
+	negated bool
+}
+
+func (n like) eval(lookup Lookup) value {
+	s, ok := n.operand.eval(lookup).(string)
+	if !ok {
+		return nil
+	}
+	return n.pattern.MatchString(s) != n.negated
+}
+
+// likePattern compiles a LIKE pattern: "%" matches any sequence, "_" any
+// character and escape makes the next character literal
+func likePattern(pattern string, escape rune) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("(?s)^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case escape != 0 && r == escape:
+			escaped = true
+		case r == '%':
+			b.WriteString(".*")
+		case r == '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	if escaped {
+		return nil, fmt.Errorf("LIKE pattern %q ends with the escape character", pattern)
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+type isNull struct {
+	operand identifier
+	negated bool
+}
+
+func (n isNull) eval(lookup Lookup) value {
+	return (n.operand.eval(lookup) == nil) != n.negated
+}
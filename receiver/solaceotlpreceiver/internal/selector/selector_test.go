@@ -0,0 +1,89 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectorMatches(t *testing.T) {
+	properties := map[string]any{
+		"env":      "prod",
+		"service":  "checkout-api",
+		"severity": int32(17),
+		"ratio":    0.25,
+		"sampled":  true,
+		"count":    uint64(3),
+		"path":     "50%_off",
+	}
+	lookup := func(name string) (any, bool) {
+		v, ok := properties[name]
+		return v, ok
+	}
+
+	for selector, want := range map[string]bool{
+		"env = 'prod'":                                true,
+		"env <> 'prod'":                               false,
+		"ENV = 'prod'":                                false,
+		"env = 'prod' and severity >= 13":             true,
+		"env = 'dev' OR severity > 20":                false,
+		"NOT env = 'dev'":                             true,
+		"severity BETWEEN 13 AND 20":                  true,
+		"severity NOT BETWEEN 13 AND 20":              false,
+		"severity + count * 2 = 23":                   true,
+		"severity / 2 = 8.5":                          true,
+		"-severity < -10":                             true,
+		"ratio < 0.5 AND ratio > 2.5E-1":              false,
+		"ratio = 25e-2":                               true,
+		"sampled = TRUE":                              true,
+		"sampled":                                     true,
+		"service IN ('checkout-api', 'cart-api')":     true,
+		"service NOT IN ('checkout-api')":             false,
+		"service LIKE 'checkout-%'":                   true,
+		"service LIKE 'checkout-a_i'":                 true,
+		"service NOT LIKE '%-api'":                    false,
+		"path LIKE '50!%!_off' ESCAPE '!'":            true,
+		"path LIKE '50!%off' ESCAPE '!'":              false,
+		"missing IS NULL":                             true,
+		"env IS NOT NULL":                             true,
+		"(env = 'dev' OR env = 'prod') AND count = 3": true,
+		"env = 'it''s'":                               false,
+		// Unknown values do not match, also when negated
+		"missing = 'x'":                 false,
+		"NOT missing = 'x'":             false,
+		"missing = 'x' OR env = 'prod'": true,
+		"env > 'a'":                     false,
+		"env = 1":                       false,
+		"NOT env = 1":                   false,
+		"severity / 0 = 1":              false,
+	} {
+		s, err := Parse(selector)
+		require.NoError(t, err, selector)
+		assert.Equal(t, want, s.Matches(lookup), selector)
+		assert.Equal(t, selector, s.String())
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, selector := range []string{
+		"",
+		"env =",
+		"env = 'prod",
+		"env = 'prod' AND",
+		"(env = 'prod'",
+		"env = 'prod')",
+		"env NOT 'prod'",
+		"env IN ()",
+		"env IN (1, 2)",
+		"'prod' IN ('prod')",
+		"env LIKE 5",
+		"env LIKE 'a!' ESCAPE '!'",
+		"env LIKE 'a' ESCAPE 'ab'",
+		"env IS 'prod'",
+		"env # 'prod'",
+	} {
+		_, err := Parse(selector)
+		assert.Error(t, err, selector)
+	}
+}
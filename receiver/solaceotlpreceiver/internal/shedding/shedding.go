@@ -0,0 +1,90 @@
+// Package shedding decides which messages are shed under back-pressure by
+// the band of their rank, such as the Solace message priority.
+package shedding
+
+import (
+	"sync"
+	"time"
+)
+
+// Band is a range of ranks that is shed as a whole
+type Band struct {
+	Name string
+	Min  int // lowest rank of the band; the band ends below the next one
+}
+
+// Shedder tracks back-pressure and sheds the lowest bands first. Each
+// refusal of the pipeline sheds one more band, at most once per cooldown,
+// and each cooldown without a refusal sheds one band less.
+type Shedder struct {
+	bands     []Band // ascending by Min
+	threshold int
+	cooldown  time.Duration
+
+	mu      sync.Mutex
+	level   int       // number of bands shed, from the lowest
+	changed time.Time // last change of level
+	refused time.Time // last refusal
+}
+
+// New creates a Shedder for bands in ascending order. Ranks at or above
+// threshold are never shed.
+func New(bands []Band, threshold int, cooldown time.Duration) *Shedder {
+	return &Shedder{bands: bands, threshold: threshold, cooldown: cooldown}
+}
+
+// Refused records that the pipeline refused a message at now. It reports
+// whether one more band is shed.
+func (s *Shedder) Refused(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recover(now)
+	s.refused = now
+	if s.level == len(s.bands) || (s.level > 0 && now.Sub(s.changed) < s.cooldown) {
+		return false
+	}
+	s.level++
+	s.changed = now
+	return true
+}
+
+// Shed returns the band of rank and whether messages of it are shed at now.
+// Ranks at or above the threshold belong to no band.
+func (s *Shedder) Shed(rank int, now time.Time) (string, bool) {
+	if rank >= s.threshold {
+		return "", false
+	}
+	band := 0
+	for i, b := range s.bands {
+		if rank >= b.Min {
+			band = i
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recover(now)
+	return s.bands[band].Name, band < s.level
+}
+
+// Level returns the number of bands shed at now
+func (s *Shedder) Level(now time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recover(now)
+	return s.level
+}
+
+// recover sheds one band less per cooldown since the last refusal or change
+func (s *Shedder) recover(now time.Time) {
+	for s.level > 0 {
+		last := s.changed
+		if s.refused.After(last) {
+			last = s.refused
+		}
+		if now.Sub(last) < s.cooldown {
+			return
+		}
+		s.level--
+		s.changed = last.Add(s.cooldown)
+	}
+}
@@ -0,0 +1,47 @@
+package shedding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShedder(t *testing.T) {
+	s := New([]Band{{Name: "low", Min: 0}, {Name: "normal", Min: 4}}, 7, 10*time.Second)
+	start := time.Unix(1_700_000_000, 0)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+	shed := func(rank int, now time.Time) bool {
+		_, shed := s.Shed(rank, now)
+		return shed
+	}
+
+	band, shedding := s.Shed(2, start)
+	assert.Equal(t, "low", band)
+	assert.False(t, shedding, "nothing is shed without back-pressure")
+	band, _ = s.Shed(6, start)
+	assert.Equal(t, "normal", band)
+	band, _ = s.Shed(9, start)
+	assert.Empty(t, band, "ranks at the threshold belong to no band")
+
+	assert.True(t, s.Refused(at(0)))
+	assert.True(t, shed(3, at(0)))
+	assert.False(t, shed(4, at(0)))
+
+	assert.False(t, s.Refused(at(time.Second)))
+	assert.Equal(t, 1, s.Level(at(time.Second)), "a burst of refusals escalates once per cooldown")
+
+	s.Refused(at(10 * time.Second))
+	assert.Equal(t, 2, s.Level(at(10*time.Second)))
+	assert.True(t, shed(6, at(10*time.Second)))
+	assert.False(t, shed(7, at(10*time.Second)), "ranks at the threshold are never shed")
+
+	s.Refused(at(15 * time.Second))
+	assert.Equal(t, 2, s.Level(at(15*time.Second)), "at most all bands are shed")
+
+	assert.Equal(t, 2, s.Level(at(24*time.Second)))
+	assert.Equal(t, 1, s.Level(at(25*time.Second)), "one band recovers per cooldown without refusals")
+	assert.False(t, shed(6, at(25*time.Second)))
+	assert.True(t, shed(0, at(34*time.Second)))
+	assert.Equal(t, 0, s.Level(at(35*time.Second)))
+}
@@ -0,0 +1,69 @@
+package simplejson
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// addPayloadSeeds adds the simplified JSON payloads of the golden corpus
+func addPayloadSeeds(f *testing.F) {
+	paths, err := filepath.Glob(filepath.Join("..", "..", "testdata", "payloads", "*.simple*.json"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, path := range paths {
+		payload, err := os.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(payload)
+	}
+}
+
+func FuzzUnmarshalLogs(f *testing.F) {
+	addPayloadSeeds(f)
+	f.Add([]byte(`{"body": {"kvlistValue": {"values": [{"key": "a", "value": {"arrayValue": {"values": [1]}}}]}}}`))
+	f.Add([]byte(`{"time": 1.5e300, "severity_text": "ERROR9"}`))
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		logs, err := UnmarshalLogs(payload)
+		if err == nil && logs.LogRecordCount() != 1 {
+			t.Fatalf("decoded %d log records, want 1", logs.LogRecordCount())
+		}
+	})
+}
+
+func FuzzUnmarshalTraces(f *testing.F) {
+	addPayloadSeeds(f)
+	f.Add([]byte(`[{"kind": "server", "events": [{"time": "2025-06-02T09:30:00Z"}]}, {"links": [{"trace_id": ""}]}]`))
+	f.Add([]byte(`{"spans": [{"status": {"code": 2}}], "resource": {"attributes": [{"key": "a", "value": 1}]}}`))
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		traces, err := UnmarshalTraces(payload)
+		if err != nil {
+			return
+		}
+		for i := 0; i < traces.ResourceSpans().Len(); i++ {
+			scopeSpans := traces.ResourceSpans().At(i).ScopeSpans()
+			for j := 0; j < scopeSpans.Len(); j++ {
+				if scopeSpans.At(j).Spans().Len() == 0 {
+					t.Fatalf("scope %d of resource %d has no spans", j, i)
+				}
+			}
+		}
+	})
+}
+
+func FuzzUnmarshalMetrics(f *testing.F) {
+	addPayloadSeeds(f)
+	f.Add([]byte(`{"name": "a", "type": "histogram", "data_points": [{"explicit_bounds": [1], "bucket_counts": [1, 2], "min": 0, "max": 3}]}`))
+	f.Add([]byte(`{"metrics": [{"name": "b", "type": "exponential_histogram", "data_points": [{"scale": -3, "positive": {"offset": -2, "bucket_counts": [1]}}]}]}`))
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		metrics, err := UnmarshalMetrics(payload)
+		if err != nil {
+			return
+		}
+		if metrics.DataPointCount() < metrics.MetricCount() {
+			t.Fatalf("decoded %d metrics with %d data points", metrics.MetricCount(), metrics.DataPointCount())
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/util"
+)
+
+// logJSON is a simplified JSON log record
+type logJSON struct {
+	Resource               *resourceJSON `json:"resource"`
+	Scope                  *scopeJSON    `json:"scope"`
+	TimeUnixNano           nanos         `json:"time_unix_nano"`
+	Time                   instant       `json:"time"`
+	ObservedTimeUnixNano   nanos         `json:"observed_time_unix_nano"`
+	ObservedTime           instant       `json:"observed_time"`
+	SeverityNumber         json.Number   `json:"severity_number"`
+	SeverityText           string        `json:"severity_text"`
+	Body                   any           `json:"body"`
+	Attributes             any           `json:"attributes"`
+	DroppedAttributesCount json.Number   `json:"dropped_attributes_count"`
+	Flags                  json.Number   `json:"flags"`
+	TraceID                string        `json:"trace_id"`
+	SpanID                 string        `json:"span_id"`
+	EventName              string        `json:"event_name"`
+}
+
+// severities maps severity texts to the first number of their range
+var severities = map[string]plog.SeverityNumber{
+	"TRACE":    plog.SeverityNumberTrace,
+	"DEBUG":    plog.SeverityNumberDebug,
+	"INFO":     plog.SeverityNumberInfo,
+	"NOTICE":   plog.SeverityNumberInfo2,
+	"WARN":     plog.SeverityNumberWarn,
+	"WARNING":  plog.SeverityNumberWarn,
+	"ERROR":    plog.SeverityNumberError,
+	"ERR":      plog.SeverityNumberError,
+	"CRITICAL": plog.SeverityNumberFatal,
+	"FATAL":    plog.SeverityNumberFatal,
+}
+
+// UnmarshalLogs converts a simplified JSON log record. Times are given in
+// nanoseconds (time_unix_nano) or as RFC3339 string or epoch milliseconds
+// (time); trace and span IDs as hex or base64. A missing severity number is
+// inferred from the severity text. IDs that cannot be parsed are ignored.
+func UnmarshalLogs(payload []byte) (plog.Logs, error) {
+	var data logJSON
+	if err := unmarshal(payload, &data); err != nil {
+		return plog.Logs{}, fmt.Errorf("failed to unmarshal log data: %w", err)
+	}
+
+	logs := plog.NewLogs()
+	resourceLogs := logs.ResourceLogs().AppendEmpty()
+	schemaURL, err := data.Resource.copyTo(resourceLogs.Resource())
+	if err != nil {
+		return plog.Logs{}, err
+	}
+	resourceLogs.SetSchemaUrl(schemaURL)
+	scopeLogs := resourceLogs.ScopeLogs().AppendEmpty()
+	if schemaURL, err = data.Scope.copyTo(scopeLogs.Scope()); err != nil {
+		return plog.Logs{}, err
+	}
+	scopeLogs.SetSchemaUrl(schemaURL)
+
+	record := scopeLogs.LogRecords().AppendEmpty()
+	record.SetTimestamp(timestampOf(data.TimeUnixNano, data.Time))
+	record.SetObservedTimestamp(timestampOf(data.ObservedTimeUnixNano, data.ObservedTime))
+	severity, err := severityOf(data.SeverityNumber, data.SeverityText)
+	if err != nil {
+		return plog.Logs{}, err
+	}
+	record.SetSeverityNumber(severity)
+	record.SetSeverityText(data.SeverityText)
+	if err := putValue(record.Body(), data.Body); err != nil {
+		return plog.Logs{}, fmt.Errorf("body: %w", err)
+	}
+	if err := putAttributes(record.Attributes(), data.Attributes); err != nil {
+		return plog.Logs{}, fmt.Errorf("attributes: %w", err)
+	}
+	dropped, err := uint32Of(data.DroppedAttributesCount)
+	if err != nil {
+		return plog.Logs{}, fmt.Errorf("dropped_attributes_count: %w", err)
+	}
+	record.SetDroppedAttributesCount(dropped)
+	flags, err := uint32Of(data.Flags)
+	if err != nil {
+		return plog.Logs{}, fmt.Errorf("flags: %w", err)
+	}
+	record.SetFlags(plog.LogRecordFlags(flags))
+	record.SetEventName(data.EventName)
+
+	if data.TraceID != "" {
+		if traceID, err := util.ParseTraceID(data.TraceID); err == nil {
+			record.SetTraceID(traceID)
+		}
+	}
+	if data.SpanID != "" {
+		if spanID, err := util.ParseSpanID(data.SpanID); err == nil {
+			record.SetSpanID(spanID)
+		}
+	}
+	return logs, nil
+}
+
+// severityOf returns the given severity number or infers it from text such
+// as "warn" or "INFO2"
+func severityOf(number json.Number, text string) (plog.SeverityNumber, error) {
+	if number != "" {
+		n, err := number.Int64()
+		if err != nil || n < 0 || n > int64(plog.SeverityNumberFatal4) {
+			return 0, fmt.Errorf("severity_number must be between 0 and 24, got %s", number)
+		}
+		if n != 0 {
+			return plog.SeverityNumber(n), nil
+		}
+	}
+	text = strings.ToUpper(strings.TrimSpace(text))
+	name := strings.TrimRight(text, "1234")
+	severity, ok := severities[name]
+	if !ok {
+		return plog.SeverityNumberUnspecified, nil
+	}
+	if suffix := text[len(name):]; len(suffix) == 1 {
+		severity += plog.SeverityNumber(suffix[0] - '1')
+	}
+	return severity, nil
+}
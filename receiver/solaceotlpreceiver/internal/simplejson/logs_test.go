@@ -0,0 +1,183 @@
+package simplejson
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// firstRecord unmarshals payload and returns its only log record
+func firstRecord(t *testing.T, payload string) plog.LogRecord {
+	logs, err := UnmarshalLogs([]byte(payload))
+	require.NoError(t, err)
+	require.Equal(t, 1, logs.LogRecordCount())
+	return logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+}
+
+func TestUnmarshalLogs_Attributes(t *testing.T) {
+	record := firstRecord(t, `{"attributes": [
+		{"key": "int", "value": 9007199254740993},
+		{"key": "negative", "value": -3},
+		{"key": "double", "value": 1.5},
+		{"key": "exponent", "value": 1e3},
+		{"key": "bool", "value": true},
+		{"key": "null", "value": null},
+		{"key": "list", "value": [1, "a", [true]]},
+		{"key": "map", "value": {"nested": {"deep": 1}}},
+		{"key": "typed", "value": {"doubleValue": "2"}}
+	]}`)
+	attrs := record.Attributes().AsRaw()
+	assert.Equal(t, int64(9007199254740993), attrs["int"])
+	assert.Equal(t, int64(-3), attrs["negative"])
+	assert.Equal(t, 1.5, attrs["double"])
+	assert.Equal(t, 1000.0, attrs["exponent"])
+	assert.Nil(t, attrs["null"])
+	assert.Equal(t, true, attrs["bool"])
+	assert.Equal(t, []any{int64(1), "a", []any{true}}, attrs["list"])
+	assert.Equal(t, map[string]any{"nested": map[string]any{"deep": int64(1)}}, attrs["map"])
+	assert.Equal(t, 2.0, attrs["typed"])
+}
+
+func TestUnmarshalLogs_AttributesObject(t *testing.T) {
+	record := firstRecord(t, `{"attributes": {"b": 2, "a": "x"}}`)
+	assert.Equal(t, map[string]any{"a": "x", "b": int64(2)}, record.Attributes().AsRaw())
+	var keys []string
+	record.Attributes().Range(func(k string, _ pcommon.Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+	assert.Equal(t, []string{"a", "b"}, keys, "object keys are stored in order")
+}
+
+func TestUnmarshalLogs_Body(t *testing.T) {
+	for name, tc := range map[string]struct {
+		body string
+		want any
+	}{
+		"string": {`"hello"`, "hello"},
+		"number": {`7`, int64(7)},
+		"map":    {`{"msg": "hi", "n": 1}`, map[string]any{"msg": "hi", "n": int64(1)}},
+		"array":  {`["a", 1]`, []any{"a", int64(1)}},
+		"typed":  {`{"stringValue": "typed"}`, "typed"},
+		"null":   {`null`, nil},
+	} {
+		t.Run(name, func(t *testing.T) {
+			record := firstRecord(t, `{"body": `+tc.body+`}`)
+			assert.Equal(t, tc.want, record.Body().AsRaw())
+		})
+	}
+}
+
+func TestUnmarshalLogs_Timestamps(t *testing.T) {
+	want := time.Date(2025, 6, 2, 9, 30, 0, 123000000, time.UTC)
+	for name, payload := range map[string]string{
+		"nanoseconds":         `{"time_unix_nano": 1748856600123000000}`,
+		"nanosecond string":   `{"time_unix_nano": "1748856600123000000"}`,
+		"RFC3339":             `{"time": "2025-06-02T09:30:00.123Z"}`,
+		"RFC3339 with offset": `{"time": "2025-06-02T11:30:00.123+02:00"}`,
+		"epoch milliseconds":  `{"time": 1748856600123}`,
+		"nanoseconds win":     `{"time_unix_nano": 1748856600123000000, "time": 0}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, want, firstRecord(t, payload).Timestamp().AsTime())
+		})
+	}
+	record := firstRecord(t, `{"observed_time": "2025-06-02T09:30:00.123Z"}`)
+	assert.Equal(t, want, record.ObservedTimestamp().AsTime())
+	assert.Zero(t, record.Timestamp())
+}
+
+func TestUnmarshalLogs_Severity(t *testing.T) {
+	for text, want := range map[string]plog.SeverityNumber{
+		"":         plog.SeverityNumberUnspecified,
+		"trace":    plog.SeverityNumberTrace,
+		"DEBUG":    plog.SeverityNumberDebug,
+		"Info":     plog.SeverityNumberInfo,
+		"INFO3":    plog.SeverityNumberInfo3,
+		"warning":  plog.SeverityNumberWarn,
+		"ERROR4":   plog.SeverityNumberError4,
+		"critical": plog.SeverityNumberFatal,
+		"verbose":  plog.SeverityNumberUnspecified,
+	} {
+		t.Run(text, func(t *testing.T) {
+			record := firstRecord(t, `{"severity_text": "`+text+`"}`)
+			assert.Equal(t, want, record.SeverityNumber())
+			assert.Equal(t, text, record.SeverityText())
+		})
+	}
+	record := firstRecord(t, `{"severity_number": 17, "severity_text": "INFO"}`)
+	assert.Equal(t, plog.SeverityNumberError, record.SeverityNumber(), "an explicit number wins")
+}
+
+func TestUnmarshalLogs_IDs(t *testing.T) {
+	wantTrace := pcommon.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	wantSpan := pcommon.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+	for name, payload := range map[string]string{
+		"hex":    `{"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736", "span_id": "00f067aa0ba902b7"}`,
+		"base64": `{"trace_id": "S/kvNXezTaajzpKdDg5HNg==", "span_id": "APBnqgupArc="}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			record := firstRecord(t, payload)
+			assert.Equal(t, wantTrace, record.TraceID())
+			assert.Equal(t, wantSpan, record.SpanID())
+		})
+	}
+	record := firstRecord(t, `{"trace_id": "not an id", "span_id": "00f067aa0ba902b7ff"}`)
+	assert.True(t, record.TraceID().IsEmpty(), "invalid IDs are ignored")
+	assert.True(t, record.SpanID().IsEmpty())
+}
+
+func TestUnmarshalLogs_ResourceAndScope(t *testing.T) {
+	logs, err := UnmarshalLogs([]byte(`{
+		"resource": {"attributes": {"service.name": "api"}, "dropped_attributes_count": 1, "schema_url": "https://example.com/r"},
+		"scope": {"name": "lib", "version": "1.0", "attributes": [{"key": "k", "value": "v"}], "schema_url": "https://example.com/s"},
+		"flags": 1,
+		"dropped_attributes_count": 3,
+		"event_name": "login"
+	}`))
+	require.NoError(t, err)
+	resourceLogs := logs.ResourceLogs().At(0)
+	assert.Equal(t, map[string]any{"service.name": "api"}, resourceLogs.Resource().Attributes().AsRaw())
+	assert.Equal(t, uint32(1), resourceLogs.Resource().DroppedAttributesCount())
+	assert.Equal(t, "https://example.com/r", resourceLogs.SchemaUrl())
+	scopeLogs := resourceLogs.ScopeLogs().At(0)
+	assert.Equal(t, "lib", scopeLogs.Scope().Name())
+	assert.Equal(t, "1.0", scopeLogs.Scope().Version())
+	assert.Equal(t, map[string]any{"k": "v"}, scopeLogs.Scope().Attributes().AsRaw())
+	assert.Equal(t, "https://example.com/s", scopeLogs.SchemaUrl())
+	record := scopeLogs.LogRecords().At(0)
+	assert.True(t, record.Flags().IsSampled())
+	assert.Equal(t, uint32(3), record.DroppedAttributesCount())
+	assert.Equal(t, "login", record.EventName())
+}
+
+func TestUnmarshalLogs_Errors(t *testing.T) {
+	for name, payload := range map[string]string{
+		"not JSON":             `{`,
+		"trailing data":        `{} {}`,
+		"attributes string":    `{"attributes": "a=b"}`,
+		"attribute not object": `{"attributes": ["a"]}`,
+		"attribute key":        `{"attributes": [{"key": 1, "value": 2}]}`,
+		"typed int":            `{"attributes": [{"key": "a", "value": {"intValue": "x"}}]}`,
+		"typed bytes":          `{"body": {"bytesValue": "%%"}}`,
+		"number out of range":  `{"body": 1e400}`,
+		"typed array":          `{"body": {"arrayValue": "x"}}`,
+		"time":                 `{"time": "yesterday"}`,
+		"negative time":        `{"time": -1}`,
+		"nanoseconds":          `{"time_unix_nano": 1.5}`,
+		"severity number":      `{"severity_number": 25}`,
+		"flags":                `{"flags": -1}`,
+		"dropped count":        `{"dropped_attributes_count": 1.5}`,
+		"resource":             `{"resource": {"attributes": 1}}`,
+		"scope":                `{"scope": {"dropped_attributes_count": "x"}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := UnmarshalLogs([]byte(payload))
+			assert.Error(t, err)
+		})
+	}
+}
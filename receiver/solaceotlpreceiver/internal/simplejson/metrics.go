@@ -0,0 +1,474 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// metricsJSON is a list of simplified JSON metrics that share a resource and
+// scope, or a single metric with its resource and scope
+type metricsJSON struct {
+	Resource *resourceJSON `json:"resource"`
+	Scope    *scopeJSON    `json:"scope"`
+	Metrics  []metricJSON  `json:"metrics"`
+	metricJSON
+}
+
+// metricJSON is a simplified JSON metric
+type metricJSON struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Unit        string      `json:"unit"`
+	Type        string      `json:"type"`
+	Temporality any         `json:"temporality"`
+	Monotonic   bool        `json:"monotonic"`
+	DataPoints  []pointJSON `json:"data_points"`
+}
+
+// pointJSON is a data point of any metric type
+type pointJSON struct {
+	Attributes        any         `json:"attributes"`
+	StartTimeUnixNano nanos       `json:"start_time_unix_nano"`
+	StartTime         instant     `json:"start_time"`
+	TimeUnixNano      nanos       `json:"time_unix_nano"`
+	Time              instant     `json:"time"`
+	Flags             json.Number `json:"flags"`
+
+	// gauge and sum
+	Value json.Number `json:"value"`
+
+	// histogram and exponential histogram
+	Count          json.Number   `json:"count"`
+	Sum            json.Number   `json:"sum"`
+	Min            json.Number   `json:"min"`
+	Max            json.Number   `json:"max"`
+	BucketCounts   []json.Number `json:"bucket_counts"`
+	ExplicitBounds []json.Number `json:"explicit_bounds"`
+	Scale          json.Number   `json:"scale"`
+	ZeroCount      json.Number   `json:"zero_count"`
+	ZeroThreshold  json.Number   `json:"zero_threshold"`
+	Positive       *bucketsJSON  `json:"positive"`
+	Negative       *bucketsJSON  `json:"negative"`
+}
+
+// bucketsJSON is a range of exponential histogram buckets
+type bucketsJSON struct {
+	Offset       json.Number   `json:"offset"`
+	BucketCounts []json.Number `json:"bucket_counts"`
+}
+
+// Metric types of the simplified format
+const (
+	typeGauge                = "gauge"
+	typeSum                  = "sum"
+	typeHistogram            = "histogram"
+	typeExponentialHistogram = "exponential_histogram"
+)
+
+// temporalities maps aggregation temporality names, without their OTLP
+// prefix, to values
+var temporalities = map[string]pmetric.AggregationTemporality{
+	"DELTA":      pmetric.AggregationTemporalityDelta,
+	"CUMULATIVE": pmetric.AggregationTemporalityCumulative,
+}
+
+// Bounds of the exponential histogram scale
+const (
+	minScale = -10
+	maxScale = 20
+)
+
+// UnmarshalMetrics converts simplified JSON metrics. The payload is an object
+// with a "metrics" list, or a single metric; both may carry "resource" and
+// "scope". Errors name the metric, data point and field that are invalid.
+func UnmarshalMetrics(payload []byte) (pmetric.Metrics, error) {
+	var data metricsJSON
+	if err := unmarshal(payload, &data); err != nil {
+		return pmetric.Metrics{}, fmt.Errorf("failed to unmarshal metric data: %w", err)
+	}
+	list := data.Metrics
+	single := data.Metrics == nil
+	if single {
+		list = []metricJSON{data.metricJSON}
+	} else if data.Name != "" || data.Type != "" || data.DataPoints != nil {
+		return pmetric.Metrics{}, errors.New("a payload with a metrics list cannot also be a metric")
+	}
+
+	metrics := pmetric.NewMetrics()
+	resourceMetrics := metrics.ResourceMetrics().AppendEmpty()
+	schemaURL, err := data.Resource.copyTo(resourceMetrics.Resource())
+	if err != nil {
+		return pmetric.Metrics{}, err
+	}
+	resourceMetrics.SetSchemaUrl(schemaURL)
+	scopeMetrics := resourceMetrics.ScopeMetrics().AppendEmpty()
+	if schemaURL, err = data.Scope.copyTo(scopeMetrics.Scope()); err != nil {
+		return pmetric.Metrics{}, err
+	}
+	scopeMetrics.SetSchemaUrl(schemaURL)
+
+	scopeMetrics.Metrics().EnsureCapacity(len(list))
+	for i := range list {
+		if err := list[i].copyTo(scopeMetrics.Metrics().AppendEmpty()); err != nil {
+			if single {
+				return pmetric.Metrics{}, err
+			}
+			return pmetric.Metrics{}, fmt.Errorf("metrics[%d]: %w", i, err)
+		}
+	}
+	return metrics, nil
+}
+
+// copyTo stores the metric
+func (m *metricJSON) copyTo(metric pmetric.Metric) error {
+	if m.Name == "" {
+		return errors.New("name: required")
+	}
+	metric.SetName(m.Name)
+	metric.SetDescription(m.Description)
+	metric.SetUnit(m.Unit)
+
+	metricType := strings.ToLower(m.Type)
+	if m.Monotonic && metricType != typeSum {
+		return fmt.Errorf("%s: monotonic: only allowed for sums", m.Name)
+	}
+	if m.Temporality != nil && metricType == typeGauge {
+		return fmt.Errorf("%s: temporality: not allowed for gauges", m.Name)
+	}
+	temporality, err := temporalityOf(m.Temporality)
+	if err != nil {
+		return fmt.Errorf("%s: temporality: %w", m.Name, err)
+	}
+
+	double := m.hasFractionalValue()
+	var copyPoint func(p *pointJSON) error
+	switch metricType {
+	case typeGauge:
+		points := metric.SetEmptyGauge().DataPoints()
+		copyPoint = func(p *pointJSON) error { return p.copyToNumber(points.AppendEmpty(), double) }
+	case typeSum:
+		sum := metric.SetEmptySum()
+		sum.SetAggregationTemporality(temporality)
+		sum.SetIsMonotonic(m.Monotonic)
+		copyPoint = func(p *pointJSON) error { return p.copyToNumber(sum.DataPoints().AppendEmpty(), double) }
+	case typeHistogram:
+		histogram := metric.SetEmptyHistogram()
+		histogram.SetAggregationTemporality(temporality)
+		copyPoint = func(p *pointJSON) error { return p.copyToHistogram(histogram.DataPoints().AppendEmpty()) }
+	case typeExponentialHistogram:
+		histogram := metric.SetEmptyExponentialHistogram()
+		histogram.SetAggregationTemporality(temporality)
+		copyPoint = func(p *pointJSON) error {
+			return p.copyToExponentialHistogram(histogram.DataPoints().AppendEmpty())
+		}
+	case "":
+		return fmt.Errorf("%s: type: required", m.Name)
+	default:
+		return fmt.Errorf("%s: type: unknown metric type %q, expected %s, %s, %s or %s",
+			m.Name, m.Type, typeGauge, typeSum, typeHistogram, typeExponentialHistogram)
+	}
+	if len(m.DataPoints) == 0 {
+		return fmt.Errorf("%s: data_points: at least one data point is required", m.Name)
+	}
+	for i := range m.DataPoints {
+		if err := copyPoint(&m.DataPoints[i]); err != nil {
+			return fmt.Errorf("%s: data_points[%d]: %w", m.Name, i, err)
+		}
+	}
+	return nil
+}
+
+// hasFractionalValue reports whether a data point value is not an integer
+func (m *metricJSON) hasFractionalValue() bool {
+	for i := range m.DataPoints {
+		if value := m.DataPoints[i].Value; value != "" {
+			if _, err := value.Int64(); err != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// temporalityOf returns the aggregation temporality; a missing one is cumulative
+func temporalityOf(v any) (pmetric.AggregationTemporality, error) {
+	if v == nil {
+		return pmetric.AggregationTemporalityCumulative, nil
+	}
+	return enumOf(v, temporalities, "AGGREGATION_TEMPORALITY_")
+}
+
+// dataPoint is the part that all data point types share
+type dataPoint interface {
+	Attributes() pcommon.Map
+	SetStartTimestamp(pcommon.Timestamp)
+	SetTimestamp(pcommon.Timestamp)
+	SetFlags(pmetric.DataPointFlags)
+}
+
+// copyCommon stores the attributes, times and flags of a data point
+func (p *pointJSON) copyCommon(point dataPoint) error {
+	if err := putAttributes(point.Attributes(), p.Attributes); err != nil {
+		return fmt.Errorf("attributes: %w", err)
+	}
+	point.SetStartTimestamp(timestampOf(p.StartTimeUnixNano, p.StartTime))
+	point.SetTimestamp(timestampOf(p.TimeUnixNano, p.Time))
+	flags, err := uint32Of(p.Flags)
+	if err != nil {
+		return fmt.Errorf("flags: %w", err)
+	}
+	point.SetFlags(pmetric.DataPointFlags(flags))
+	return nil
+}
+
+// hasHistogramFields reports whether any histogram field is set
+func (p *pointJSON) hasHistogramFields() bool {
+	return p.Count != "" || p.Sum != "" || p.Min != "" || p.Max != "" ||
+		p.BucketCounts != nil || p.ExplicitBounds != nil || p.Scale != "" ||
+		p.ZeroCount != "" || p.ZeroThreshold != "" || p.Positive != nil || p.Negative != nil
+}
+
+// copyToNumber stores a gauge or sum data point as double, or as int if the
+// value is integral and double is false
+func (p *pointJSON) copyToNumber(point pmetric.NumberDataPoint, double bool) error {
+	if p.Value == "" {
+		return errors.New("value: required")
+	}
+	if p.hasHistogramFields() {
+		return errors.New("histogram fields are not allowed in gauge and sum data points")
+	}
+	if i, err := p.Value.Int64(); err == nil && !double {
+		point.SetIntValue(i)
+	} else {
+		f, err := finiteOf(p.Value)
+		if err != nil {
+			return fmt.Errorf("value: %w", err)
+		}
+		point.SetDoubleValue(f)
+	}
+	return p.copyCommon(point)
+}
+
+// copyToHistogram stores a histogram data point. A missing count is the sum
+// of the bucket counts.
+func (p *pointJSON) copyToHistogram(point pmetric.HistogramDataPoint) error {
+	if p.Value != "" {
+		return errors.New("value: not allowed in histogram data points, use sum")
+	}
+	if p.Scale != "" || p.ZeroCount != "" || p.ZeroThreshold != "" || p.Positive != nil || p.Negative != nil {
+		return errors.New("exponential histogram fields are not allowed in histogram data points")
+	}
+	bounds := make([]float64, len(p.ExplicitBounds))
+	for i, bound := range p.ExplicitBounds {
+		f, err := finiteOf(bound)
+		if err != nil {
+			return fmt.Errorf("explicit_bounds[%d]: %w", i, err)
+		}
+		if i > 0 && f <= bounds[i-1] {
+			return fmt.Errorf("explicit_bounds[%d]: %v does not exceed the previous bound %v", i, f, bounds[i-1])
+		}
+		bounds[i] = f
+	}
+	counts, total, err := uint64s(p.BucketCounts)
+	if err != nil {
+		return fmt.Errorf("bucket_counts%w", err)
+	}
+	if counts != nil && len(counts) != len(bounds)+1 {
+		return fmt.Errorf("bucket_counts: expected %d buckets for %d explicit_bounds, got %d",
+			len(bounds)+1, len(bounds), len(counts))
+	}
+	if counts == nil && len(bounds) > 0 {
+		return errors.New("explicit_bounds: not allowed without bucket_counts")
+	}
+	count, err := countOf(p.Count, total, counts != nil)
+	if err != nil {
+		return err
+	}
+	point.SetCount(count)
+	point.ExplicitBounds().FromRaw(bounds)
+	point.BucketCounts().FromRaw(counts)
+	if err := p.copySummary(point.SetSum, point.SetMin, point.SetMax); err != nil {
+		return err
+	}
+	return p.copyCommon(point)
+}
+
+// copyToExponentialHistogram stores an exponential histogram data point. A
+// missing count is the sum of the zero count and all bucket counts.
+func (p *pointJSON) copyToExponentialHistogram(point pmetric.ExponentialHistogramDataPoint) error {
+	if p.Value != "" {
+		return errors.New("value: not allowed in exponential histogram data points, use sum")
+	}
+	if p.BucketCounts != nil || p.ExplicitBounds != nil {
+		return errors.New("bucket_counts and explicit_bounds are not allowed in exponential histogram data points, use positive and negative")
+	}
+	scale, err := int64Of(jsonNumberOr(p.Scale, "0"))
+	if err != nil || scale < minScale || scale > maxScale {
+		return fmt.Errorf("scale: must be an integer between %d and %d, got %s", minScale, maxScale, p.Scale)
+	}
+	point.SetScale(int32(scale))
+	zeroCount, err := uint64Of(p.ZeroCount)
+	if err != nil {
+		return fmt.Errorf("zero_count: %w", err)
+	}
+	point.SetZeroCount(zeroCount)
+	if p.ZeroThreshold != "" {
+		threshold, err := finiteOf(p.ZeroThreshold)
+		if err != nil || threshold < 0 {
+			return fmt.Errorf("zero_threshold: must be a non-negative number, got %s", p.ZeroThreshold)
+		}
+		point.SetZeroThreshold(threshold)
+	}
+
+	total := zeroCount
+	buckets := []struct {
+		name string
+		src  *bucketsJSON
+		dst  pmetric.ExponentialHistogramDataPointBuckets
+	}{
+		{"positive", p.Positive, point.Positive()},
+		{"negative", p.Negative, point.Negative()},
+	}
+	for _, b := range buckets {
+		n, err := b.src.copyTo(b.dst)
+		if err != nil {
+			return fmt.Errorf("%s.%w", b.name, err)
+		}
+		if total+n < total {
+			return fmt.Errorf("%s.bucket_counts: total count overflows", b.name)
+		}
+		total += n
+	}
+	count, err := countOf(p.Count, total, p.ZeroCount != "" || p.Positive != nil || p.Negative != nil)
+	if err != nil {
+		return err
+	}
+	point.SetCount(count)
+	if err := p.copySummary(point.SetSum, point.SetMin, point.SetMax); err != nil {
+		return err
+	}
+	return p.copyCommon(point)
+}
+
+// copyTo stores the buckets and returns their total count
+func (b *bucketsJSON) copyTo(dst pmetric.ExponentialHistogramDataPointBuckets) (uint64, error) {
+	if b == nil {
+		return 0, nil
+	}
+	offset, err := int64Of(jsonNumberOr(b.Offset, "0"))
+	if err != nil || offset < math.MinInt32 || offset > math.MaxInt32 {
+		return 0, fmt.Errorf("offset: must be a 32-bit integer, got %s", b.Offset)
+	}
+	dst.SetOffset(int32(offset))
+	counts, total, err := uint64s(b.BucketCounts)
+	if err != nil {
+		return 0, fmt.Errorf("bucket_counts%w", err)
+	}
+	dst.BucketCounts().FromRaw(counts)
+	return total, nil
+}
+
+// copySummary stores the optional sum, min and max of a histogram data point
+func (p *pointJSON) copySummary(setSum, setMin, setMax func(float64)) error {
+	fields := []struct {
+		name  string
+		value json.Number
+		set   func(float64)
+	}{
+		{"sum", p.Sum, setSum},
+		{"min", p.Min, setMin},
+		{"max", p.Max, setMax},
+	}
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		f, err := finiteOf(field.value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field.name, err)
+		}
+		field.set(f)
+	}
+	if p.Min != "" && p.Max != "" {
+		minimum, _ := p.Min.Float64()
+		maximum, _ := p.Max.Float64()
+		if minimum > maximum {
+			return fmt.Errorf("min: %s exceeds max %s", p.Min, p.Max)
+		}
+	}
+	return nil
+}
+
+// countOf returns the given count, checked against the total of the buckets
+// if there are any, or the total if no count is given
+func countOf(count json.Number, total uint64, hasBuckets bool) (uint64, error) {
+	if count == "" {
+		return total, nil
+	}
+	n, err := uint64Of(count)
+	if err != nil {
+		return 0, fmt.Errorf("count: %w", err)
+	}
+	if hasBuckets && n != total {
+		return 0, fmt.Errorf("count: %d does not match the %d values in the buckets", n, total)
+	}
+	return n, nil
+}
+
+// uint64s converts a list of counts and returns their total; errors start
+// with the index of the invalid count
+func uint64s(values []json.Number) ([]uint64, uint64, error) {
+	if values == nil {
+		return nil, 0, nil
+	}
+	counts := make([]uint64, len(values))
+	var total uint64
+	for i, v := range values {
+		n, err := uint64Of(v)
+		if err != nil {
+			return nil, 0, fmt.Errorf("[%d]: %w", i, err)
+		}
+		if total+n < total {
+			return nil, 0, fmt.Errorf("[%d]: total count overflows", i)
+		}
+		counts[i] = n
+		total += n
+	}
+	return counts, total, nil
+}
+
+// uint64Of converts a JSON number to uint64; a missing value is zero
+func uint64Of(v json.Number) (uint64, error) {
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(v.String(), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected an unsigned 64-bit integer, got %s", v)
+	}
+	return n, nil
+}
+
+// finiteOf converts a JSON number to a finite float64
+func finiteOf(v json.Number) (float64, error) {
+	f, err := strconv.ParseFloat(v.String(), 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return 0, fmt.Errorf("expected a finite number, got %s", v)
+	}
+	return f, nil
+}
+
+// jsonNumberOr returns v, or def if v is missing
+func jsonNumberOr(v json.Number, def json.Number) json.Number {
+	if v == "" {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,175 @@
+package simplejson
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// firstMetric unmarshals payload and returns its only metric
+func firstMetric(t *testing.T, payload string) pmetric.Metric {
+	metrics, err := UnmarshalMetrics([]byte(payload))
+	require.NoError(t, err)
+	require.Equal(t, 1, metrics.MetricCount())
+	return metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+}
+
+func TestUnmarshalMetrics_Gauge(t *testing.T) {
+	metric := firstMetric(t, `{
+		"name": "tank.level", "description": "Fill level", "unit": "%", "type": "Gauge",
+		"data_points": [{
+			"value": 42,
+			"time": "2025-06-02T09:30:00.123Z",
+			"start_time_unix_nano": 1748856000000000000,
+			"attributes": {"tank": "A"},
+			"flags": 1
+		}]
+	}`)
+	assert.Equal(t, "tank.level", metric.Name())
+	assert.Equal(t, "Fill level", metric.Description())
+	assert.Equal(t, "%", metric.Unit())
+	require.Equal(t, pmetric.MetricTypeGauge, metric.Type())
+	point := metric.Gauge().DataPoints().At(0)
+	assert.Equal(t, int64(42), point.IntValue())
+	assert.Equal(t, time.Date(2025, 6, 2, 9, 30, 0, 123000000, time.UTC), point.Timestamp().AsTime())
+	assert.Equal(t, time.Date(2025, 6, 2, 9, 20, 0, 0, time.UTC), point.StartTimestamp().AsTime())
+	assert.Equal(t, map[string]any{"tank": "A"}, point.Attributes().AsRaw())
+	assert.True(t, point.Flags().NoRecordedValue())
+}
+
+func TestUnmarshalMetrics_NumberValues(t *testing.T) {
+	metric := firstMetric(t, `{"name": "n", "type": "gauge", "data_points": [{"value": 1}, {"value": 9007199254740993}]}`)
+	assert.Equal(t, pmetric.NumberDataPointValueTypeInt, metric.Gauge().DataPoints().At(0).ValueType())
+	assert.Equal(t, int64(9007199254740993), metric.Gauge().DataPoints().At(1).IntValue())
+
+	metric = firstMetric(t, `{"name": "n", "type": "gauge", "data_points": [{"value": 1}, {"value": 1.5}]}`)
+	points := metric.Gauge().DataPoints()
+	assert.Equal(t, 1.0, points.At(0).DoubleValue(), "a fractional value makes every point a double")
+	assert.Equal(t, 1.5, points.At(1).DoubleValue())
+}
+
+func TestUnmarshalMetrics_Sum(t *testing.T) {
+	for temporality, want := range map[string]pmetric.AggregationTemporality{
+		``:                             pmetric.AggregationTemporalityCumulative,
+		`"temporality": "delta",`:      pmetric.AggregationTemporalityDelta,
+		`"temporality": "CUMULATIVE",`: pmetric.AggregationTemporalityCumulative,
+		`"temporality": "AGGREGATION_TEMPORALITY_DELTA",`: pmetric.AggregationTemporalityDelta,
+		`"temporality": 1,`: pmetric.AggregationTemporalityDelta,
+	} {
+		t.Run(temporality, func(t *testing.T) {
+			metric := firstMetric(t, `{"name": "requests", "type": "sum", `+temporality+` "monotonic": true, "data_points": [{"value": 3}]}`)
+			require.Equal(t, pmetric.MetricTypeSum, metric.Type())
+			assert.Equal(t, want, metric.Sum().AggregationTemporality())
+			assert.True(t, metric.Sum().IsMonotonic())
+			assert.Equal(t, int64(3), metric.Sum().DataPoints().At(0).IntValue())
+		})
+	}
+}
+
+func TestUnmarshalMetrics_Histogram(t *testing.T) {
+	metric := firstMetric(t, `{"name": "latency", "type": "histogram", "temporality": "delta", "data_points": [
+		{"sum": 7.5, "min": 0.1, "max": 4, "explicit_bounds": [0.5, 1], "bucket_counts": [2, 3, 1]},
+		{"count": 4, "sum": 2}
+	]}`)
+	require.Equal(t, pmetric.MetricTypeHistogram, metric.Type())
+	assert.Equal(t, pmetric.AggregationTemporalityDelta, metric.Histogram().AggregationTemporality())
+	point := metric.Histogram().DataPoints().At(0)
+	assert.Equal(t, uint64(6), point.Count(), "count is the sum of the buckets")
+	assert.Equal(t, 7.5, point.Sum())
+	assert.Equal(t, 0.1, point.Min())
+	assert.Equal(t, 4.0, point.Max())
+	assert.Equal(t, []float64{0.5, 1}, point.ExplicitBounds().AsRaw())
+	assert.Equal(t, []uint64{2, 3, 1}, point.BucketCounts().AsRaw())
+
+	point = metric.Histogram().DataPoints().At(1)
+	assert.Equal(t, uint64(4), point.Count())
+	assert.False(t, point.HasMin())
+	assert.Zero(t, point.BucketCounts().Len())
+}
+
+func TestUnmarshalMetrics_ExponentialHistogram(t *testing.T) {
+	metric := firstMetric(t, `{"name": "size", "type": "exponential_histogram", "data_points": [{
+		"scale": -2, "zero_count": 1, "zero_threshold": 0.001, "sum": 100,
+		"positive": {"offset": 3, "bucket_counts": [1, 2]},
+		"negative": {"offset": -1, "bucket_counts": [4]}
+	}]}`)
+	require.Equal(t, pmetric.MetricTypeExponentialHistogram, metric.Type())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, metric.ExponentialHistogram().AggregationTemporality())
+	point := metric.ExponentialHistogram().DataPoints().At(0)
+	assert.Equal(t, int32(-2), point.Scale())
+	assert.Equal(t, uint64(1), point.ZeroCount())
+	assert.Equal(t, 0.001, point.ZeroThreshold())
+	assert.Equal(t, uint64(8), point.Count())
+	assert.Equal(t, 100.0, point.Sum())
+	assert.Equal(t, int32(3), point.Positive().Offset())
+	assert.Equal(t, []uint64{1, 2}, point.Positive().BucketCounts().AsRaw())
+	assert.Equal(t, int32(-1), point.Negative().Offset())
+	assert.Equal(t, []uint64{4}, point.Negative().BucketCounts().AsRaw())
+}
+
+func TestUnmarshalMetrics_List(t *testing.T) {
+	metrics, err := UnmarshalMetrics([]byte(`{
+		"resource": {"attributes": {"service.name": "plc"}, "schema_url": "https://example.com/r"},
+		"scope": {"name": "collector"},
+		"metrics": [
+			{"name": "a", "type": "gauge", "data_points": [{"value": 1}]},
+			{"name": "b", "type": "sum", "data_points": [{"value": 2}]}
+		]
+	}`))
+	require.NoError(t, err)
+	resourceMetrics := metrics.ResourceMetrics().At(0)
+	assert.Equal(t, map[string]any{"service.name": "plc"}, resourceMetrics.Resource().Attributes().AsRaw())
+	assert.Equal(t, "https://example.com/r", resourceMetrics.SchemaUrl())
+	assert.Equal(t, "collector", resourceMetrics.ScopeMetrics().At(0).Scope().Name())
+	assert.Equal(t, 2, metrics.MetricCount())
+	assert.Equal(t, "b", resourceMetrics.ScopeMetrics().At(0).Metrics().At(1).Name())
+}
+
+func TestUnmarshalMetrics_Errors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		payload string
+		want    string
+	}{
+		"not JSON":           {`{`, "failed to unmarshal metric data"},
+		"list and metric":    {`{"name": "a", "metrics": []}`, "cannot also be a metric"},
+		"name":               {`{"type": "gauge", "data_points": [{"value": 1}]}`, "name: required"},
+		"type":               {`{"name": "a", "data_points": [{"value": 1}]}`, "a: type: required"},
+		"unknown type":       {`{"name": "a", "type": "summary"}`, `a: type: unknown metric type "summary"`},
+		"no data points":     {`{"name": "a", "type": "gauge"}`, "a: data_points: at least one"},
+		"value":              {`{"name": "a", "type": "gauge", "data_points": [{}]}`, "a: data_points[0]: value: required"},
+		"value not finite":   {`{"name": "a", "type": "sum", "data_points": [{"value": 1e999}]}`, "a: data_points[0]: value: expected a finite number"},
+		"value type":         {`{"name": "a", "type": "gauge", "data_points": [{"value": true}]}`, "failed to unmarshal metric data"},
+		"gauge with buckets": {`{"name": "a", "type": "gauge", "data_points": [{"value": 1, "count": 1}]}`, "histogram fields are not allowed"},
+		"gauge temporality":  {`{"name": "a", "type": "gauge", "temporality": "delta", "data_points": [{"value": 1}]}`, "a: temporality: not allowed"},
+		"temporality":        {`{"name": "a", "type": "sum", "temporality": "sometimes", "data_points": [{"value": 1}]}`, "a: temporality: unknown value"},
+		"monotonic gauge":    {`{"name": "a", "type": "gauge", "monotonic": true, "data_points": [{"value": 1}]}`, "a: monotonic: only allowed for sums"},
+		"attributes":         {`{"name": "a", "type": "gauge", "data_points": [{"value": 1, "attributes": 1}]}`, "a: data_points[0]: attributes"},
+		"time":               {`{"name": "a", "type": "gauge", "data_points": [{"value": 1, "time": "noon"}]}`, "failed to unmarshal metric data"},
+		"flags":              {`{"name": "a", "type": "gauge", "data_points": [{"value": 1, "flags": -1}]}`, "a: data_points[0]: flags"},
+		"histogram value":    {`{"name": "a", "type": "histogram", "data_points": [{"value": 1}]}`, "a: data_points[0]: value: not allowed"},
+		"bucket count":       {`{"name": "a", "type": "histogram", "data_points": [{"explicit_bounds": [1], "bucket_counts": [1]}]}`, "a: data_points[0]: bucket_counts: expected 2 buckets for 1 explicit_bounds, got 1"},
+		"negative bucket":    {`{"name": "a", "type": "histogram", "data_points": [{"bucket_counts": [-1]}]}`, "a: data_points[0]: bucket_counts[0]: expected an unsigned 64-bit integer"},
+		"bounds order":       {`{"name": "a", "type": "histogram", "data_points": [{"explicit_bounds": [2, 1], "bucket_counts": [1, 1, 1]}]}`, "a: data_points[0]: explicit_bounds[1]"},
+		"bounds only":        {`{"name": "a", "type": "histogram", "data_points": [{"explicit_bounds": [1]}]}`, "explicit_bounds: not allowed without bucket_counts"},
+		"count mismatch":     {`{"name": "a", "type": "histogram", "data_points": [{"count": 3, "bucket_counts": [1]}]}`, "a: data_points[0]: count: 3 does not match the 1 values"},
+		"min above max":      {`{"name": "a", "type": "histogram", "data_points": [{"min": 2, "max": 1}]}`, "a: data_points[0]: min: 2 exceeds max 1"},
+		"sum":                {`{"name": "a", "type": "histogram", "data_points": [{"sum": "x"}]}`, "failed to unmarshal metric data"},
+		"scale":              {`{"name": "a", "type": "exponential_histogram", "data_points": [{"scale": 21}]}`, "a: data_points[0]: scale: must be an integer between -10 and 20"},
+		"zero threshold":     {`{"name": "a", "type": "exponential_histogram", "data_points": [{"zero_threshold": -1}]}`, "a: data_points[0]: zero_threshold"},
+		"offset":             {`{"name": "a", "type": "exponential_histogram", "data_points": [{"positive": {"offset": 3000000000}}]}`, "a: data_points[0]: positive.offset"},
+		"exponential count":  {`{"name": "a", "type": "exponential_histogram", "data_points": [{"count": 1, "zero_count": 2}]}`, "a: data_points[0]: count: 1 does not match the 2 values"},
+		"explicit buckets":   {`{"name": "a", "type": "exponential_histogram", "data_points": [{"bucket_counts": [1]}]}`, "use positive and negative"},
+		"bucket overflow":    {`{"name": "a", "type": "histogram", "data_points": [{"bucket_counts": [18446744073709551615, 1]}]}`, "bucket_counts[1]: total count overflows"},
+		"list index":         {`{"metrics": [{"name": "a", "type": "gauge", "data_points": [{"value": 1}]}, {"name": "b"}]}`, "metrics[1]: b: type: required"},
+		"resource":           {`{"resource": {"attributes": 1}, "name": "a", "type": "gauge", "data_points": [{"value": 1}]}`, "resource attributes"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := UnmarshalMetrics([]byte(tc.payload))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.want)
+		})
+	}
+}
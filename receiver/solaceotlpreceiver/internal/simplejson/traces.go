@@ -0,0 +1,387 @@
+package simplejson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/util"
+)
+
+// spanJSON is a simplified JSON span
+type spanJSON struct {
+	Resource               json.RawMessage `json:"resource"`
+	Scope                  json.RawMessage `json:"scope"`
+	TraceID                string          `json:"trace_id"`
+	SpanID                 string          `json:"span_id"`
+	ParentSpanID           string          `json:"parent_span_id"`
+	TraceState             string          `json:"trace_state"`
+	Flags                  json.Number     `json:"flags"`
+	Name                   string          `json:"name"`
+	Kind                   any             `json:"kind"`
+	StartTime              spanTime        `json:"start_time"`
+	EndTime                spanTime        `json:"end_time"`
+	Attributes             any             `json:"attributes"`
+	DroppedAttributesCount json.Number     `json:"dropped_attributes_count"`
+	Events                 []eventJSON     `json:"events"`
+	DroppedEventsCount     json.Number     `json:"dropped_events_count"`
+	Links                  []linkJSON      `json:"links"`
+	DroppedLinksCount      json.Number     `json:"dropped_links_count"`
+	Status                 struct {
+		Code    any    `json:"code"`
+		Message string `json:"message"`
+	} `json:"status"`
+}
+
+// eventJSON is a span event
+type eventJSON struct {
+	Name                   string      `json:"name"`
+	Time                   spanTime    `json:"time"`
+	Attributes             any         `json:"attributes"`
+	DroppedAttributesCount json.Number `json:"dropped_attributes_count"`
+}
+
+// linkJSON is a span link
+type linkJSON struct {
+	TraceID                string      `json:"trace_id"`
+	SpanID                 string      `json:"span_id"`
+	TraceState             string      `json:"trace_state"`
+	Flags                  json.Number `json:"flags"`
+	Attributes             any         `json:"attributes"`
+	DroppedAttributesCount json.Number `json:"dropped_attributes_count"`
+}
+
+// spansJSON is a list of spans that share a resource and scope
+type spansJSON struct {
+	Resource json.RawMessage   `json:"resource"`
+	Scope    json.RawMessage   `json:"scope"`
+	Spans    []json.RawMessage `json:"spans"`
+}
+
+// spanKinds and statusCodes map enum names, without their OTLP prefix, to values
+var (
+	spanKinds = map[string]ptrace.SpanKind{
+		"UNSPECIFIED": ptrace.SpanKindUnspecified,
+		"INTERNAL":    ptrace.SpanKindInternal,
+		"SERVER":      ptrace.SpanKindServer,
+		"CLIENT":      ptrace.SpanKindClient,
+		"PRODUCER":    ptrace.SpanKindProducer,
+		"CONSUMER":    ptrace.SpanKindConsumer,
+	}
+	statusCodes = map[string]ptrace.StatusCode{
+		"UNSET": ptrace.StatusCodeUnset,
+		"OK":    ptrace.StatusCodeOk,
+		"ERROR": ptrace.StatusCodeError,
+	}
+)
+
+// spanTime is a Unix time in nanoseconds, given as number or decimal string,
+// or an RFC3339 string
+type spanTime pcommon.Timestamp
+
+func (t *spanTime) UnmarshalJSON(b []byte) error {
+	var ns nanos
+	if err := ns.UnmarshalJSON(b); err == nil {
+		*t = spanTime(ns)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("invalid timestamp %s", b)
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return fmt.Errorf("invalid RFC3339 timestamp %q", s)
+	}
+	*t = spanTime(pcommon.NewTimestampFromTime(parsed))
+	return nil
+}
+
+// UnmarshalTraces converts simplified JSON spans. The payload is a span, an
+// array of spans or an object with "spans" and the "resource" and "scope"
+// they share. Spans with equal resource blocks, and within them equal scope
+// blocks, are grouped. A span
+// without parent_span_id is a root span.
+func UnmarshalTraces(payload []byte) (ptrace.Traces, error) {
+	spans, err := splitSpans(payload)
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+	groups := spanGroups{
+		traces:    ptrace.NewTraces(),
+		resources: map[string]ptrace.ResourceSpans{},
+		scopes:    map[[2]string]ptrace.SpanSlice{},
+	}
+	for i, raw := range spans {
+		var data spanJSON
+		if err := unmarshal(raw.span, &data); err != nil {
+			return ptrace.Traces{}, spanError(len(spans), i, fmt.Errorf("failed to unmarshal span: %w", err))
+		}
+		resource, scope := data.Resource, data.Scope
+		if resource == nil {
+			resource = raw.resource
+		}
+		if scope == nil {
+			scope = raw.scope
+		}
+		slice, err := groups.spanSlice(resource, scope)
+		if err != nil {
+			return ptrace.Traces{}, spanError(len(spans), i, err)
+		}
+		if err := data.copyTo(slice.AppendEmpty()); err != nil {
+			return ptrace.Traces{}, spanError(len(spans), i, err)
+		}
+	}
+	return groups.traces, nil
+}
+
+// spanError names the failing span if the payload has several
+func spanError(n, i int, err error) error {
+	if n == 1 {
+		return err
+	}
+	return fmt.Errorf("span %d: %w", i, err)
+}
+
+// rawSpan is a span with the resource and scope of its envelope
+type rawSpan struct {
+	span, resource, scope json.RawMessage
+}
+
+// splitSpans returns the spans of a payload
+func splitSpans(payload []byte) ([]rawSpan, error) {
+	trimmed := bytes.TrimSpace(payload)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var list []json.RawMessage
+		if err := unmarshal(trimmed, &list); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal span list: %w", err)
+		}
+		spans := make([]rawSpan, len(list))
+		for i, span := range list {
+			spans[i] = rawSpan{span: span}
+		}
+		return spans, nil
+	}
+	var envelope spansJSON
+	if err := unmarshal(trimmed, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal trace data: %w", err)
+	}
+	if envelope.Spans == nil {
+		return []rawSpan{{span: trimmed}}, nil
+	}
+	spans := make([]rawSpan, len(envelope.Spans))
+	for i, span := range envelope.Spans {
+		spans[i] = rawSpan{span: span, resource: envelope.Resource, scope: envelope.Scope}
+	}
+	return spans, nil
+}
+
+// spanGroups finds the resource and scope entries of spans by their raw
+// resource and scope blocks
+type spanGroups struct {
+	traces    ptrace.Traces
+	resources map[string]ptrace.ResourceSpans
+	scopes    map[[2]string]ptrace.SpanSlice
+}
+
+// spanSlice returns the spans of the resource and scope, creating them on first use
+func (g *spanGroups) spanSlice(resource, scope json.RawMessage) (ptrace.SpanSlice, error) {
+	key := [2]string{string(resource), string(scope)}
+	if slice, ok := g.scopes[key]; ok {
+		return slice, nil
+	}
+	resourceSpans, ok := g.resources[key[0]]
+	if !ok {
+		resourceSpans = g.traces.ResourceSpans().AppendEmpty()
+		if err := decodeResource(resource, resourceSpans.Resource(), resourceSpans.SetSchemaUrl); err != nil {
+			return ptrace.SpanSlice{}, err
+		}
+		g.resources[key[0]] = resourceSpans
+	}
+	scopeSpans := resourceSpans.ScopeSpans().AppendEmpty()
+	if err := decodeScope(scope, scopeSpans.Scope(), scopeSpans.SetSchemaUrl); err != nil {
+		return ptrace.SpanSlice{}, err
+	}
+	g.scopes[key] = scopeSpans.Spans()
+	return scopeSpans.Spans(), nil
+}
+
+// decodeResource stores a raw resource block and passes on its schema URL
+func decodeResource(raw json.RawMessage, dst pcommon.Resource, setSchemaURL func(string)) error {
+	if raw == nil {
+		return nil
+	}
+	var resource *resourceJSON
+	if err := unmarshal(raw, &resource); err != nil {
+		return fmt.Errorf("failed to unmarshal resource: %w", err)
+	}
+	schemaURL, err := resource.copyTo(dst)
+	setSchemaURL(schemaURL)
+	return err
+}
+
+// decodeScope stores a raw scope block and passes on its schema URL
+func decodeScope(raw json.RawMessage, dst pcommon.InstrumentationScope, setSchemaURL func(string)) error {
+	if raw == nil {
+		return nil
+	}
+	var scope *scopeJSON
+	if err := unmarshal(raw, &scope); err != nil {
+		return fmt.Errorf("failed to unmarshal scope: %w", err)
+	}
+	schemaURL, err := scope.copyTo(dst)
+	setSchemaURL(schemaURL)
+	return err
+}
+
+// copyTo stores the span
+func (s *spanJSON) copyTo(span ptrace.Span) error {
+	traceID, err := util.ParseTraceID(s.TraceID)
+	if s.TraceID != "" && err != nil {
+		return fmt.Errorf("failed to convert trace ID: %w", err)
+	}
+	span.SetTraceID(traceID)
+	spanID, err := util.ParseSpanID(s.SpanID)
+	if s.SpanID != "" && err != nil {
+		return fmt.Errorf("failed to convert span ID: %w", err)
+	}
+	span.SetSpanID(spanID)
+	parentSpanID, err := util.ParseSpanID(s.ParentSpanID)
+	if s.ParentSpanID != "" && err != nil {
+		return fmt.Errorf("failed to convert parent span ID: %w", err)
+	}
+	span.SetParentSpanID(parentSpanID)
+	span.TraceState().FromRaw(s.TraceState)
+	flags, err := uint32Of(s.Flags)
+	if err != nil {
+		return fmt.Errorf("flags: %w", err)
+	}
+	span.SetFlags(flags)
+	span.SetName(s.Name)
+	kind, err := enumOf(s.Kind, spanKinds, "SPAN_KIND_")
+	if err != nil {
+		return fmt.Errorf("kind: %w", err)
+	}
+	span.SetKind(kind)
+	span.SetStartTimestamp(pcommon.Timestamp(s.StartTime))
+	span.SetEndTimestamp(pcommon.Timestamp(s.EndTime))
+	if err := putAttributes(span.Attributes(), s.Attributes); err != nil {
+		return fmt.Errorf("attributes: %w", err)
+	}
+
+	counts := []struct {
+		name  string
+		value json.Number
+		set   func(uint32)
+	}{
+		{"dropped_attributes_count", s.DroppedAttributesCount, span.SetDroppedAttributesCount},
+		{"dropped_events_count", s.DroppedEventsCount, span.SetDroppedEventsCount},
+		{"dropped_links_count", s.DroppedLinksCount, span.SetDroppedLinksCount},
+	}
+	for _, count := range counts {
+		n, err := uint32Of(count.value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", count.name, err)
+		}
+		count.set(n)
+	}
+
+	span.Events().EnsureCapacity(len(s.Events))
+	for i := range s.Events {
+		if err := s.Events[i].copyTo(span.Events().AppendEmpty()); err != nil {
+			return fmt.Errorf("events[%d]: %w", i, err)
+		}
+	}
+	span.Links().EnsureCapacity(len(s.Links))
+	for i := range s.Links {
+		if err := s.Links[i].copyTo(span.Links().AppendEmpty()); err != nil {
+			return fmt.Errorf("links[%d]: %w", i, err)
+		}
+	}
+
+	code, err := enumOf(s.Status.Code, statusCodes, "STATUS_CODE_")
+	if err != nil {
+		return fmt.Errorf("status code: %w", err)
+	}
+	span.Status().SetCode(code)
+	span.Status().SetMessage(s.Status.Message)
+	return nil
+}
+
+// copyTo stores the event
+func (e *eventJSON) copyTo(event ptrace.SpanEvent) error {
+	event.SetName(e.Name)
+	event.SetTimestamp(pcommon.Timestamp(e.Time))
+	if err := putAttributes(event.Attributes(), e.Attributes); err != nil {
+		return fmt.Errorf("attributes: %w", err)
+	}
+	dropped, err := uint32Of(e.DroppedAttributesCount)
+	if err != nil {
+		return fmt.Errorf("dropped_attributes_count: %w", err)
+	}
+	event.SetDroppedAttributesCount(dropped)
+	return nil
+}
+
+// copyTo stores the link
+func (l *linkJSON) copyTo(link ptrace.SpanLink) error {
+	traceID, err := util.ParseTraceID(l.TraceID)
+	if err != nil {
+		return fmt.Errorf("failed to convert trace ID: %w", err)
+	}
+	link.SetTraceID(traceID)
+	spanID, err := util.ParseSpanID(l.SpanID)
+	if err != nil {
+		return fmt.Errorf("failed to convert span ID: %w", err)
+	}
+	link.SetSpanID(spanID)
+	link.TraceState().FromRaw(l.TraceState)
+	flags, err := uint32Of(l.Flags)
+	if err != nil {
+		return fmt.Errorf("flags: %w", err)
+	}
+	link.SetFlags(flags)
+	if err := putAttributes(link.Attributes(), l.Attributes); err != nil {
+		return fmt.Errorf("attributes: %w", err)
+	}
+	dropped, err := uint32Of(l.DroppedAttributesCount)
+	if err != nil {
+		return fmt.Errorf("dropped_attributes_count: %w", err)
+	}
+	link.SetDroppedAttributesCount(dropped)
+	return nil
+}
+
+// enumOf converts an enum given as number or as case-insensitive name, with
+// or without its OTLP prefix
+func enumOf[T ~int32](v any, names map[string]T, prefix string) (T, error) {
+	switch v := v.(type) {
+	case nil:
+		return 0, nil
+	case json.Number:
+		n, err := strconv.ParseInt(v.String(), 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid value %s", v)
+		}
+		for _, value := range names {
+			if T(n) == value {
+				return value, nil
+			}
+		}
+		return 0, fmt.Errorf("unknown value %s", v)
+	case string:
+		name := strings.TrimPrefix(strings.ToUpper(v), prefix)
+		if value, ok := names[name]; ok {
+			return value, nil
+		}
+		return 0, fmt.Errorf("unknown value %q", v)
+	default:
+		return 0, fmt.Errorf("expected a number or a name, got %T", v)
+	}
+}
@@ -0,0 +1,184 @@
+package simplejson
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// firstSpan unmarshals payload and returns its only span
+func firstSpan(t *testing.T, payload string) ptrace.Span {
+	traces, err := UnmarshalTraces([]byte(payload))
+	require.NoError(t, err)
+	require.Equal(t, 1, traces.SpanCount())
+	return traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+}
+
+func TestUnmarshalTraces_RootSpan(t *testing.T) {
+	for name, payload := range map[string]string{
+		"empty parent":   `{"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736", "span_id": "00f067aa0ba902b7", "parent_span_id": ""}`,
+		"missing parent": `{"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736", "span_id": "00f067aa0ba902b7"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			span := firstSpan(t, payload)
+			assert.True(t, span.ParentSpanID().IsEmpty())
+			assert.False(t, span.SpanID().IsEmpty())
+		})
+	}
+}
+
+func TestUnmarshalTraces_Span(t *testing.T) {
+	span := firstSpan(t, `{
+		"trace_id": "S/kvNXezTaajzpKdDg5HNg==",
+		"span_id": "00f067aa0ba902b7",
+		"parent_span_id": "APBnqgupArg=",
+		"trace_state": "vendor=1",
+		"flags": 257,
+		"name": "GET /",
+		"kind": "SPAN_KIND_CLIENT",
+		"start_time": "1748856600123000000",
+		"end_time": "2025-06-02T09:30:01Z",
+		"attributes": {"http.status_code": 200},
+		"dropped_attributes_count": 1,
+		"dropped_events_count": 2,
+		"dropped_links_count": 3,
+		"status": {"code": "STATUS_CODE_OK", "message": "done"}
+	}`)
+	assert.Equal(t, pcommon.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}, span.TraceID())
+	assert.Equal(t, pcommon.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb8}, span.ParentSpanID())
+	assert.Equal(t, "vendor=1", span.TraceState().AsRaw())
+	assert.Equal(t, uint32(257), span.Flags())
+	assert.Equal(t, ptrace.SpanKindClient, span.Kind())
+	assert.Equal(t, time.Date(2025, 6, 2, 9, 30, 0, 123000000, time.UTC), span.StartTimestamp().AsTime())
+	assert.Equal(t, time.Date(2025, 6, 2, 9, 30, 1, 0, time.UTC), span.EndTimestamp().AsTime())
+	assert.Equal(t, map[string]any{"http.status_code": int64(200)}, span.Attributes().AsRaw())
+	assert.Equal(t, uint32(1), span.DroppedAttributesCount())
+	assert.Equal(t, uint32(2), span.DroppedEventsCount())
+	assert.Equal(t, uint32(3), span.DroppedLinksCount())
+	assert.Equal(t, ptrace.StatusCodeOk, span.Status().Code())
+	assert.Equal(t, "done", span.Status().Message())
+}
+
+func TestUnmarshalTraces_Enums(t *testing.T) {
+	for kind, want := range map[string]ptrace.SpanKind{
+		`3`:                    ptrace.SpanKindClient,
+		`"server"`:             ptrace.SpanKindServer,
+		`"Producer"`:           ptrace.SpanKindProducer,
+		`"SPAN_KIND_INTERNAL"`: ptrace.SpanKindInternal,
+		`null`:                 ptrace.SpanKindUnspecified,
+	} {
+		t.Run(kind, func(t *testing.T) {
+			assert.Equal(t, want, firstSpan(t, `{"kind": `+kind+`}`).Kind())
+		})
+	}
+	for code, want := range map[string]ptrace.StatusCode{
+		`2`:       ptrace.StatusCodeError,
+		`"error"`: ptrace.StatusCodeError,
+		`"Ok"`:    ptrace.StatusCodeOk,
+	} {
+		t.Run(code, func(t *testing.T) {
+			assert.Equal(t, want, firstSpan(t, `{"status": {"code": `+code+`}}`).Status().Code())
+		})
+	}
+}
+
+func TestUnmarshalTraces_EventsAndLinks(t *testing.T) {
+	span := firstSpan(t, `{
+		"events": [
+			{"name": "retry", "time": 1748856600123000000, "attributes": [{"key": "attempt", "value": 2}], "dropped_attributes_count": 1},
+			{"name": "done", "time": "2025-06-02T09:30:00.123Z"}
+		],
+		"links": [
+			{"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736", "span_id": "00f067aa0ba902b7", "trace_state": "a=b", "flags": 1, "attributes": {"k": "v"}, "dropped_attributes_count": 2}
+		]
+	}`)
+	require.Equal(t, 2, span.Events().Len())
+	event := span.Events().At(0)
+	assert.Equal(t, "retry", event.Name())
+	assert.Equal(t, map[string]any{"attempt": int64(2)}, event.Attributes().AsRaw())
+	assert.Equal(t, uint32(1), event.DroppedAttributesCount())
+	assert.Equal(t, event.Timestamp(), span.Events().At(1).Timestamp())
+
+	require.Equal(t, 1, span.Links().Len())
+	link := span.Links().At(0)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", link.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", link.SpanID().String())
+	assert.Equal(t, "a=b", link.TraceState().AsRaw())
+	assert.Equal(t, uint32(1), link.Flags())
+	assert.Equal(t, map[string]any{"k": "v"}, link.Attributes().AsRaw())
+	assert.Equal(t, uint32(2), link.DroppedAttributesCount())
+}
+
+func TestUnmarshalTraces_Lists(t *testing.T) {
+	traces, err := UnmarshalTraces([]byte(`[
+		{"name": "a", "resource": {"attributes": {"service.name": "x"}}},
+		{"name": "b", "resource": {"attributes": {"service.name": "y"}}},
+		{"name": "c", "resource": {"attributes": {"service.name": "x"}}},
+		{"name": "d", "resource": {"attributes": {"service.name": "x"}}, "scope": {"name": "lib"}}
+	]`))
+	require.NoError(t, err)
+	require.Equal(t, 2, traces.ResourceSpans().Len())
+	x := traces.ResourceSpans().At(0)
+	assert.Equal(t, map[string]any{"service.name": "x"}, x.Resource().Attributes().AsRaw())
+	require.Equal(t, 2, x.ScopeSpans().Len())
+	assert.Equal(t, 2, x.ScopeSpans().At(0).Spans().Len())
+	assert.Equal(t, "c", x.ScopeSpans().At(0).Spans().At(1).Name())
+	assert.Equal(t, "lib", x.ScopeSpans().At(1).Scope().Name())
+	assert.Equal(t, 4, traces.SpanCount())
+
+	traces, err = UnmarshalTraces([]byte(`{
+		"resource": {"attributes": {"service.name": "x"}, "schema_url": "https://example.com/r"},
+		"scope": {"name": "lib", "schema_url": "https://example.com/s"},
+		"spans": [{"name": "a"}, {"name": "b"}, {"name": "c", "scope": {"name": "other"}}]
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, 1, traces.ResourceSpans().Len())
+	shared := traces.ResourceSpans().At(0)
+	assert.Equal(t, "https://example.com/r", shared.SchemaUrl())
+	assert.Equal(t, "lib", shared.ScopeSpans().At(0).Scope().Name())
+	assert.Equal(t, "https://example.com/s", shared.ScopeSpans().At(0).SchemaUrl())
+	assert.Equal(t, 2, shared.ScopeSpans().At(0).Spans().Len())
+	assert.Equal(t, "other", shared.ScopeSpans().At(1).Scope().Name())
+
+	traces, err = UnmarshalTraces([]byte(`[]`))
+	require.NoError(t, err)
+	assert.Zero(t, traces.SpanCount())
+}
+
+func TestUnmarshalTraces_Errors(t *testing.T) {
+	for name, payload := range map[string]string{
+		"not JSON":          `{`,
+		"trailing data":     `[] {}`,
+		"span not object":   `[1]`,
+		"trace ID":          `{"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736ff"}`,
+		"span ID":           `{"span_id": "xyz"}`,
+		"parent span ID":    `{"parent_span_id": "00f067aa0ba902b7ff"}`,
+		"kind number":       `{"kind": 9}`,
+		"kind name":         `{"kind": "server-ish"}`,
+		"kind type":         `{"kind": true}`,
+		"status code":       `{"status": {"code": "failed"}}`,
+		"start time":        `{"start_time": "yesterday"}`,
+		"negative end time": `{"end_time": -1}`,
+		"flags":             `{"flags": -1}`,
+		"dropped links":     `{"dropped_links_count": 1.5}`,
+		"attributes":        `{"attributes": "a=b"}`,
+		"event time":        `{"events": [{"time": "soon"}]}`,
+		"event attributes":  `{"events": [{"attributes": 1}]}`,
+		"link trace ID":     `{"links": [{"trace_id": "nope"}]}`,
+		"link flags":        `{"links": [{"flags": "x"}]}`,
+		"resource":          `{"resource": {"attributes": 1}}`,
+		"scope":             `{"spans": [{}], "scope": {"attributes": 1}}`,
+		"spans not list":    `{"spans": {}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := UnmarshalTraces([]byte(payload))
+			assert.Error(t, err)
+		})
+	}
+	_, err := UnmarshalTraces([]byte(`[{}, {"kind": 9}]`))
+	assert.ErrorContains(t, err, "span 1: kind")
+}
@@ -0,0 +1,343 @@
+// Package simplejson converts the simplified JSON telemetry formats of the
+// receiver into pdata.
+package simplejson
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// unmarshal decodes payload into v, keeping numbers as json.Number
+func unmarshal(payload []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return errors.New("unexpected data after the JSON value")
+	}
+	return nil
+}
+
+// typedValueKeys are the keys of an OTLP/JSON AnyValue object
+var typedValueKeys = map[string]bool{
+	"stringValue": true, "intValue": true, "doubleValue": true, "boolValue": true,
+	"bytesValue": true, "arrayValue": true, "kvlistValue": true,
+}
+
+// putValue stores a JSON value into dst. Objects with a single OTLP/JSON
+// AnyValue key such as {"intValue": "42"} are taken as typed values, all
+// other objects become maps and arrays become slices.
+func putValue(dst pcommon.Value, v any) error {
+	switch v := v.(type) {
+	case nil:
+	case string:
+		dst.SetStr(v)
+	case bool:
+		dst.SetBool(v)
+	case json.Number:
+		return putNumber(dst, v)
+	case []any:
+		return putSlice(dst.SetEmptySlice(), v)
+	case map[string]any:
+		if len(v) == 1 {
+			for key, typed := range v {
+				if typedValueKeys[key] {
+					return putTypedValue(dst, key, typed)
+				}
+			}
+		}
+		return putMap(dst.SetEmptyMap(), v)
+	default:
+		return fmt.Errorf("unsupported JSON value %T", v)
+	}
+	return nil
+}
+
+// putNumber stores an integral number as int and any other number as double
+func putNumber(dst pcommon.Value, n json.Number) error {
+	if i, err := n.Int64(); err == nil {
+		dst.SetInt(i)
+		return nil
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return fmt.Errorf("invalid number %s: %w", n, err)
+	}
+	dst.SetDouble(f)
+	return nil
+}
+
+func putSlice(dst pcommon.Slice, values []any) error {
+	dst.EnsureCapacity(len(values))
+	for i, v := range values {
+		if err := putValue(dst.AppendEmpty(), v); err != nil {
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// putMap stores the values in key order, as JSON objects have none
+func putMap(dst pcommon.Map, values map[string]any) error {
+	dst.EnsureCapacity(len(values))
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		if err := putValue(dst.PutEmpty(key), values[key]); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// putTypedValue stores an OTLP/JSON AnyValue
+func putTypedValue(dst pcommon.Value, key string, v any) error {
+	switch key {
+	case "stringValue":
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("stringValue must be a string, got %T", v)
+		}
+		dst.SetStr(s)
+	case "intValue":
+		i, err := int64Of(v)
+		if err != nil {
+			return fmt.Errorf("intValue: %w", err)
+		}
+		dst.SetInt(i)
+	case "doubleValue":
+		f, err := float64Of(v)
+		if err != nil {
+			return fmt.Errorf("doubleValue: %w", err)
+		}
+		dst.SetDouble(f)
+	case "boolValue":
+		b, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("boolValue must be a boolean, got %T", v)
+		}
+		dst.SetBool(b)
+	case "bytesValue":
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("bytesValue must be a base64 string, got %T", v)
+		}
+		raw, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return fmt.Errorf("bytesValue: %w", err)
+		}
+		dst.SetEmptyBytes().FromRaw(raw)
+	case "arrayValue":
+		values, err := typedValues(v)
+		if err != nil {
+			return fmt.Errorf("arrayValue: %w", err)
+		}
+		return putSlice(dst.SetEmptySlice(), values)
+	case "kvlistValue":
+		values, err := typedValues(v)
+		if err != nil {
+			return fmt.Errorf("kvlistValue: %w", err)
+		}
+		return putKeyValues(dst.SetEmptyMap(), values)
+	}
+	return nil
+}
+
+// typedValues returns the values list of an arrayValue or kvlistValue object
+func typedValues(v any) ([]any, error) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an object, got %T", v)
+	}
+	switch values := obj["values"].(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		return values, nil
+	default:
+		return nil, fmt.Errorf("values must be a list, got %T", values)
+	}
+}
+
+// putAttributes stores attributes given as object or as list of key/value pairs
+func putAttributes(dst pcommon.Map, v any) error {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		return putMap(dst, v)
+	case []any:
+		return putKeyValues(dst, v)
+	default:
+		return fmt.Errorf("attributes must be an object or a list, got %T", v)
+	}
+}
+
+// putKeyValues stores a list of {"key": ..., "value": ...} pairs
+func putKeyValues(dst pcommon.Map, pairs []any) error {
+	dst.EnsureCapacity(len(pairs))
+	for i, pair := range pairs {
+		kv, ok := pair.(map[string]any)
+		if !ok {
+			return fmt.Errorf("[%d]: key/value pair must be an object, got %T", i, pair)
+		}
+		key, ok := kv["key"].(string)
+		if !ok {
+			return fmt.Errorf("[%d]: key must be a string", i)
+		}
+		if err := putValue(dst.PutEmpty(key), kv["value"]); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// int64Of converts a JSON number or a decimal string to int64
+func int64Of(v any) (int64, error) {
+	switch v := v.(type) {
+	case json.Number:
+		return strconv.ParseInt(v.String(), 10, 64)
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	default:
+		return 0, fmt.Errorf("expected an integer, got %T", v)
+	}
+}
+
+// float64Of converts a JSON number or a numeric string to float64
+func float64Of(v any) (float64, error) {
+	switch v := v.(type) {
+	case json.Number:
+		return v.Float64()
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("expected a number, got %T", v)
+	}
+}
+
+// uint32Of converts a JSON number to uint32; a missing value is zero
+func uint32Of(v json.Number) (uint32, error) {
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(v.String(), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("expected an unsigned 32-bit integer: %w", err)
+	}
+	return uint32(n), nil
+}
+
+// nanos is a Unix timestamp in nanoseconds, given as number or decimal string
+type nanos pcommon.Timestamp
+
+func (n *nanos) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "null" || s == "" {
+		return nil
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid nanosecond timestamp %s", b)
+	}
+	*n = nanos(v)
+	return nil
+}
+
+// instant is a point in time given as RFC3339 string or as Unix epoch milliseconds
+type instant pcommon.Timestamp
+
+func (t *instant) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		parsed, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return fmt.Errorf("invalid RFC3339 timestamp %q", s)
+		}
+		*t = instant(pcommon.NewTimestampFromTime(parsed))
+		return nil
+	}
+	if ms, err := strconv.ParseUint(string(b), 10, 64); err == nil && ms <= math.MaxUint64/1_000_000 {
+		*t = instant(ms * 1e6)
+		return nil
+	}
+	ms, err := strconv.ParseFloat(string(b), 64)
+	if err != nil || ms < 0 || ms > math.MaxUint64/1e6 {
+		return fmt.Errorf("invalid epoch milliseconds %s", b)
+	}
+	*t = instant(ms * 1e6)
+	return nil
+}
+
+// timestampOf returns nanoseconds if set and the instant otherwise
+func timestampOf(ns nanos, at instant) pcommon.Timestamp {
+	if ns != 0 {
+		return pcommon.Timestamp(ns)
+	}
+	return pcommon.Timestamp(at)
+}
+
+// resourceJSON is the resource block of a simplified JSON record
+type resourceJSON struct {
+	Attributes             any         `json:"attributes"`
+	DroppedAttributesCount json.Number `json:"dropped_attributes_count"`
+	SchemaURL              string      `json:"schema_url"`
+}
+
+// copyTo stores the resource and returns its schema URL
+func (r *resourceJSON) copyTo(dst pcommon.Resource) (string, error) {
+	if r == nil {
+		return "", nil
+	}
+	if err := putAttributes(dst.Attributes(), r.Attributes); err != nil {
+		return "", fmt.Errorf("resource attributes: %w", err)
+	}
+	dropped, err := uint32Of(r.DroppedAttributesCount)
+	if err != nil {
+		return "", fmt.Errorf("resource dropped_attributes_count: %w", err)
+	}
+	dst.SetDroppedAttributesCount(dropped)
+	return r.SchemaURL, nil
+}
+
+// scopeJSON is the instrumentation scope block of a simplified JSON record
+type scopeJSON struct {
+	Name                   string      `json:"name"`
+	Version                string      `json:"version"`
+	Attributes             any         `json:"attributes"`
+	DroppedAttributesCount json.Number `json:"dropped_attributes_count"`
+	SchemaURL              string      `json:"schema_url"`
+}
+
+// copyTo stores the scope and returns its schema URL
+func (s *scopeJSON) copyTo(dst pcommon.InstrumentationScope) (string, error) {
+	if s == nil {
+		return "", nil
+	}
+	dst.SetName(s.Name)
+	dst.SetVersion(s.Version)
+	if err := putAttributes(dst.Attributes(), s.Attributes); err != nil {
+		return "", fmt.Errorf("scope attributes: %w", err)
+	}
+	dropped, err := uint32Of(s.DroppedAttributesCount)
+	if err != nil {
+		return "", fmt.Errorf("scope dropped_attributes_count: %w", err)
+	}
+	dst.SetDroppedAttributesCount(dropped)
+	return s.SchemaURL, nil
+}
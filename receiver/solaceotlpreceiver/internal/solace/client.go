@@ -1,6 +1,7 @@
 package solace
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,6 +12,7 @@ import (
 
 	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
 	"solace.dev/go/messaging"
+	"solace.dev/go/messaging/pkg/solace"
 	"solace.dev/go/messaging/pkg/solace/config"
 	"solace.dev/go/messaging/pkg/solace/resource"
 )
@@ -138,8 +140,13 @@ func (c *Client) StartMessageReceiver() {
 			default:
 				msg, err := c.queueConsumer.ReceiveMessage(1 * time.Second)
 				if err != nil {
-					if err.Error() != "receiver has been terminated, no messages to receive" &&
-						err.Error() != "timed out waiting for message on call to Receive" {
+					var illegalState *solace.IllegalStateError
+					if errors.As(err, &illegalState) {
+						// a terminated consumer never delivers again; stop instead of spinning
+						c.logger.Warn("Queue consumer terminated; stopping message receiver", zap.Error(err))
+						return
+					}
+					if err.Error() != "timed out waiting for message on call to Receive" {
 						c.logger.Error("Error receiving message", zap.Error(err))
 					}
 					continue
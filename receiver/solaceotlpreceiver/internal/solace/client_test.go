@@ -0,0 +1,84 @@
+package solace
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"solace.dev/go/messaging/pkg/solace/message"
+	"solace.dev/go/messaging/pkg/solace/resource"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/solacetest"
+)
+
+// payloadRecorder counts how often each payload reached the listener
+type payloadRecorder struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (r *payloadRecorder) OnMessage(msg message.InboundMessage) {
+	payload, _ := msg.GetPayloadAsBytes()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counts[string(payload)]++
+}
+
+func TestClient_StopsOnInjectedTermination(t *testing.T) {
+	const (
+		published      = 20
+		terminateAfter = 10
+	)
+	broker := solacetest.NewBroker()
+	broker.CreateQueue("telemetry", "otel/>")
+	for i := 0; i < published; i++ {
+		broker.Publish("otel/logs", []byte(fmt.Sprintf("message-%d", i)))
+	}
+
+	service := solacetest.WithFaults(broker.NewMessagingService(), solacetest.Faults{
+		MaxLatency:     time.Millisecond,
+		DuplicateRate:  0.3,
+		TerminateAfter: terminateAfter,
+		Seed:           1,
+	})
+	require.NoError(t, service.Connect())
+	receiver, err := service.CreatePersistentMessageReceiverBuilder().
+		WithMessageAutoAcknowledgement().
+		Build(resource.QueueDurableExclusive("telemetry"))
+	require.NoError(t, err)
+	require.NoError(t, receiver.Start())
+
+	client := NewClient(zap.NewNop(), &solaceconfig.Config{Queue: "telemetry"})
+	client.queueConsumer = NewPersistentMessageReceiverAdapter(receiver)
+	recorder := &payloadRecorder{counts: map[string]int{}}
+	client.SetMessageListener(recorder)
+	client.StartMessageReceiver()
+
+	stopped := make(chan struct{})
+	go func() {
+		client.wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(10 * time.Second):
+		t.Fatal("message receiver kept running after the consumer terminated")
+	}
+
+	counts := service.Injector().Counts()
+	assert.Equal(t, 1, counts.Terminations)
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	assert.Len(t, recorder.counts, terminateAfter)
+	deliveries := 0
+	for _, n := range recorder.counts {
+		deliveries += n
+	}
+	assert.Equal(t, terminateAfter+counts.Duplicates, deliveries)
+	assert.Equal(t, published-terminateAfter, broker.Pending("telemetry"))
+}
@@ -153,6 +153,16 @@ func (m *SolaceInboundMessage) IsRedelivered() bool {
 	return m.msg.IsRedelivered()
 }
 
+// GetCacheRequestID returns the cache request ID of a cached message
+func (m *SolaceInboundMessage) GetCacheRequestID() (message.CacheRequestID, bool) {
+	return m.msg.GetCacheRequestID()
+}
+
+// GetCacheStatus returns whether the message is live or from a cache
+func (m *SolaceInboundMessage) GetCacheStatus() message.CacheStatus {
+	return m.msg.GetCacheStatus()
+}
+
 // String returns a string representation of the message
 func (m *SolaceInboundMessage) String() string {
 	return "SolaceInboundMessage"
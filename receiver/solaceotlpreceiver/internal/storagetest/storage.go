@@ -0,0 +1,97 @@
+// Package storagetest provides an in-memory storage extension for tests.
+package storagetest
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/xextension/storage"
+)
+
+// Storage is an in-memory storage extension and its own client. All clients
+// share one map, so that a restarted receiver finds what an earlier one stored.
+type Storage struct {
+	component.StartFunc
+	component.ShutdownFunc
+	mu     sync.Mutex
+	values map[string][]byte
+	err    error
+	closed int
+}
+
+// New returns an empty Storage
+func New() *Storage {
+	return &Storage{values: map[string][]byte{}}
+}
+
+// GetClient returns s itself
+func (s *Storage) GetClient(context.Context, component.Kind, component.ID, string) (storage.Client, error) {
+	return s, nil
+}
+
+// Get returns the value of key, or nil if it is not set
+func (s *Storage) Get(ctx context.Context, key string) ([]byte, error) {
+	op := storage.GetOperation(key)
+	return op.Value, s.Batch(ctx, op)
+}
+
+// Set stores value under key
+func (s *Storage) Set(ctx context.Context, key string, value []byte) error {
+	return s.Batch(ctx, storage.SetOperation(key, value))
+}
+
+// Delete removes key
+func (s *Storage) Delete(ctx context.Context, key string) error {
+	return s.Batch(ctx, storage.DeleteOperation(key))
+}
+
+// Batch runs ops in order, or fails with the error set by SetErr
+func (s *Storage) Batch(_ context.Context, ops ...*storage.Operation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	for _, op := range ops {
+		switch op.Type {
+		case storage.Get:
+			op.Value = s.values[op.Key]
+		case storage.Set:
+			s.values[op.Key] = op.Value
+		case storage.Delete:
+			delete(s.values, op.Key)
+		}
+	}
+	return nil
+}
+
+// Close counts the closed clients
+func (s *Storage) Close(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed++
+	return nil
+}
+
+// Value returns the value of key and whether it is set
+func (s *Storage) Value(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// SetErr makes every later operation fail with err; nil makes them succeed again
+func (s *Storage) SetErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// Closed returns the number of times a client was closed
+func (s *Storage) Closed() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
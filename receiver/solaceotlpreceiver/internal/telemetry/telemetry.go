@@ -0,0 +1,165 @@
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// ScopeName is the instrumentation scope of the receiver's own metrics
+const ScopeName = "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver"
+
+const prefix = "otelcol_receiver_solaceotlp_"
+
+// Telemetry holds the metrics the receiver reports about itself
+type Telemetry struct {
+	connectAttempts metric.Int64Counter
+	connected       metric.Int64Gauge
+	drained         metric.Int64Counter
+	released        metric.Int64Counter
+	unsettled       metric.Int64Counter
+	legacyOTLP      metric.Int64Counter
+	timestamps      metric.Int64Counter
+	duplicates      metric.Int64Counter
+	buffered        metric.Int64Gauge
+	bufferDropped   metric.Int64Counter
+	filtered        metric.Int64Counter
+	shed            metric.Int64Counter
+}
+
+// New creates the receiver metrics from the collector telemetry settings
+func New(settings component.TelemetrySettings) (*Telemetry, error) {
+	provider := settings.MeterProvider
+	if provider == nil {
+		provider = noop.NewMeterProvider()
+	}
+	meter := provider.Meter(ScopeName)
+
+	t := &Telemetry{}
+	var err error
+	if t.connectAttempts, err = meter.Int64Counter(prefix+"connect_attempts",
+		metric.WithDescription("Number of attempts to connect to the Solace broker"),
+		metric.WithUnit("{attempts}")); err != nil {
+		return nil, err
+	}
+	if t.connected, err = meter.Int64Gauge(prefix+"connected",
+		metric.WithDescription("Whether the receiver is connected to the Solace broker (1) or not (0)"),
+		metric.WithUnit("1")); err != nil {
+		return nil, err
+	}
+	if t.drained, err = meter.Int64Counter(prefix+"shutdown_drained_messages",
+		metric.WithDescription("Number of in-flight messages completed during shutdown"),
+		metric.WithUnit("{messages}")); err != nil {
+		return nil, err
+	}
+	if t.released, err = meter.Int64Counter(prefix+"shutdown_released_messages",
+		metric.WithDescription("Number of messages released back to the broker during shutdown"),
+		metric.WithUnit("{messages}")); err != nil {
+		return nil, err
+	}
+	if t.unsettled, err = meter.Int64Counter(prefix+"shutdown_unsettled_messages",
+		metric.WithDescription("Number of in-flight messages left unsettled when the shutdown deadline passed"),
+		metric.WithUnit("{messages}")); err != nil {
+		return nil, err
+	}
+	if t.legacyOTLP, err = meter.Int64Counter(prefix+"legacy_otlp_messages",
+		metric.WithDescription("Number of messages in a deprecated OTLP version that were translated"),
+		metric.WithUnit("{messages}")); err != nil {
+		return nil, err
+	}
+	if t.timestamps, err = meter.Int64Counter(prefix+"timestamp_corrections",
+		metric.WithDescription("Number of timestamps corrected by the timestamp policy"),
+		metric.WithUnit("{timestamps}")); err != nil {
+		return nil, err
+	}
+	if t.duplicates, err = meter.Int64Counter(prefix+"duplicate_messages",
+		metric.WithDescription("Number of redelivered messages dropped because they were already consumed"),
+		metric.WithUnit("{messages}")); err != nil {
+		return nil, err
+	}
+	if t.buffered, err = meter.Int64Gauge(prefix+"buffer_messages",
+		metric.WithDescription("Number of messages held in the write-ahead buffer"),
+		metric.WithUnit("{messages}")); err != nil {
+		return nil, err
+	}
+	if t.bufferDropped, err = meter.Int64Counter(prefix+"buffer_dropped_messages",
+		metric.WithDescription("Number of buffered messages dropped because the pipeline rejected them permanently"),
+		metric.WithUnit("{messages}")); err != nil {
+		return nil, err
+	}
+	if t.filtered, err = meter.Int64Counter(prefix+"filtered_messages",
+		metric.WithDescription("Number of messages dropped because they did not match the client-side selector of their queue"),
+		metric.WithUnit("{messages}")); err != nil {
+		return nil, err
+	}
+	if t.shed, err = meter.Int64Counter(prefix+"shed_messages",
+		metric.WithDescription("Number of messages shed under back-pressure by priority band"),
+		metric.WithUnit("{messages}")); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// RecordConnectAttempt records the outcome of one connection attempt
+func (t *Telemetry) RecordConnectAttempt(ctx context.Context, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "failure"
+	}
+	t.connectAttempts.Add(ctx, 1, metric.WithAttributes(attribute.String("outcome", outcome)))
+}
+
+// RecordConnected records the current connection state
+func (t *Telemetry) RecordConnected(ctx context.Context, connected bool) {
+	var value int64
+	if connected {
+		value = 1
+	}
+	t.connected.Record(ctx, value)
+}
+
+// RecordShutdown records how many messages were drained, released and left
+// unsettled on shutdown
+func (t *Telemetry) RecordShutdown(ctx context.Context, drained, released, unsettled int64) {
+	t.drained.Add(ctx, drained)
+	t.released.Add(ctx, released)
+	t.unsettled.Add(ctx, unsettled)
+}
+
+// RecordLegacyOTLP records a message of the given signal in a deprecated OTLP version
+func (t *Telemetry) RecordLegacyOTLP(ctx context.Context, signal, version string) {
+	t.legacyOTLP.Add(ctx, 1, metric.WithAttributes(attribute.String("signal", signal), attribute.String("version", version)))
+}
+
+// RecordTimestampCorrections records n timestamp corrections of a kind
+func (t *Telemetry) RecordTimestampCorrections(ctx context.Context, signal, correction string, n int64) {
+	t.timestamps.Add(ctx, n, metric.WithAttributes(attribute.String("signal", signal), attribute.String("correction", correction)))
+}
+
+// RecordDuplicate records a duplicate message dropped from queue
+func (t *Telemetry) RecordDuplicate(ctx context.Context, queue string) {
+	t.duplicates.Add(ctx, 1, metric.WithAttributes(attribute.String("queue", queue)))
+}
+
+// RecordBuffered records the number of messages in the write-ahead buffer
+func (t *Telemetry) RecordBuffered(ctx context.Context, n int) {
+	t.buffered.Record(ctx, int64(n))
+}
+
+// RecordBufferDropped records a buffered message of signal dropped by the pipeline
+func (t *Telemetry) RecordBufferDropped(ctx context.Context, signal string) {
+	t.bufferDropped.Add(ctx, 1, metric.WithAttributes(attribute.String("signal", signal)))
+}
+
+// RecordFiltered records a message of queue dropped by its client-side selector
+func (t *Telemetry) RecordFiltered(ctx context.Context, queue string) {
+	t.filtered.Add(ctx, 1, metric.WithAttributes(attribute.String("queue", queue)))
+}
+
+// RecordShed records a message of a priority band shed with action
+func (t *Telemetry) RecordShed(ctx context.Context, band, action string) {
+	t.shed.Add(ctx, 1, metric.WithAttributes(attribute.String("band", band), attribute.String("action", action)))
+}
@@ -0,0 +1,115 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// newTestTelemetry returns Telemetry whose metrics are collected by reader
+func newTestTelemetry(t *testing.T) (*Telemetry, *sdkmetric.ManualReader) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { require.NoError(t, provider.Shutdown(context.Background())) })
+	tel, err := New(component.TelemetrySettings{MeterProvider: provider})
+	require.NoError(t, err)
+	return tel, reader
+}
+
+// collect returns the metrics of reader by name
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Metrics {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	metrics := map[string]metricdata.Metrics{}
+	for _, sm := range rm.ScopeMetrics {
+		assert.Equal(t, ScopeName, sm.Scope.Name)
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m
+		}
+	}
+	return metrics
+}
+
+func TestTelemetry_Units(t *testing.T) {
+	ctx := context.Background()
+	tel, reader := newTestTelemetry(t)
+	tel.RecordConnectAttempt(ctx, nil)
+	tel.RecordConnected(ctx, true)
+	tel.RecordShutdown(ctx, 1, 1, 1)
+	tel.RecordLegacyOTLP(ctx, "traces", "v0.15")
+	tel.RecordTimestampCorrections(ctx, "logs", "filled", 1)
+	tel.RecordDuplicate(ctx, "q")
+	tel.RecordBuffered(ctx, 1)
+	tel.RecordBufferDropped(ctx, "logs")
+	tel.RecordFiltered(ctx, "q")
+	tel.RecordShed(ctx, "0-3", "drop")
+
+	units := map[string]string{}
+	for name, m := range collect(t, reader) {
+		units[name] = m.Unit
+	}
+	assert.Equal(t, map[string]string{
+		prefix + "connect_attempts":            "{attempts}",
+		prefix + "connected":                   "1",
+		prefix + "shutdown_drained_messages":   "{messages}",
+		prefix + "shutdown_released_messages":  "{messages}",
+		prefix + "shutdown_unsettled_messages": "{messages}",
+		prefix + "legacy_otlp_messages":        "{messages}",
+		prefix + "timestamp_corrections":       "{timestamps}",
+		prefix + "duplicate_messages":          "{messages}",
+		prefix + "buffer_messages":             "{messages}",
+		prefix + "buffer_dropped_messages":     "{messages}",
+		prefix + "filtered_messages":           "{messages}",
+		prefix + "shed_messages":               "{messages}",
+	}, units)
+}
+
+func TestTelemetry_Buffer(t *testing.T) {
+	ctx := context.Background()
+	tel, reader := newTestTelemetry(t)
+	tel.RecordBuffered(ctx, 3)
+	tel.RecordBuffered(ctx, 2)
+	tel.RecordBufferDropped(ctx, "logs")
+	tel.RecordBufferDropped(ctx, "logs")
+	tel.RecordBufferDropped(ctx, "traces")
+
+	metrics := collect(t, reader)
+	buffered := metrics[prefix+"buffer_messages"].Data.(metricdata.Gauge[int64]).DataPoints
+	require.Len(t, buffered, 1)
+	assert.Equal(t, int64(2), buffered[0].Value, "the gauge holds the last value")
+
+	dropped := map[string]int64{}
+	for _, dp := range metrics[prefix+"buffer_dropped_messages"].Data.(metricdata.Sum[int64]).DataPoints {
+		signal, _ := dp.Attributes.Value(attribute.Key("signal"))
+		dropped[signal.AsString()] = dp.Value
+	}
+	assert.Equal(t, map[string]int64{"logs": 2, "traces": 1}, dropped)
+}
+
+func TestTelemetry_ConnectAttempts(t *testing.T) {
+	ctx := context.Background()
+	tel, reader := newTestTelemetry(t)
+	tel.RecordConnectAttempt(ctx, errors.New("unreachable"))
+	tel.RecordConnectAttempt(ctx, errors.New("unreachable"))
+	tel.RecordConnectAttempt(ctx, nil)
+
+	attempts := map[string]int64{}
+	for _, dp := range collect(t, reader)[prefix+"connect_attempts"].Data.(metricdata.Sum[int64]).DataPoints {
+		outcome, _ := dp.Attributes.Value(attribute.Key("outcome"))
+		attempts[outcome.AsString()] = dp.Value
+	}
+	assert.Equal(t, map[string]int64{"failure": 2, "success": 1}, attempts)
+}
+
+func TestNew_WithoutMeterProvider(t *testing.T) {
+	tel, err := New(component.TelemetrySettings{})
+	require.NoError(t, err)
+	tel.RecordBuffered(context.Background(), 1)
+}
@@ -0,0 +1,148 @@
+// Package testdata generates random telemetry and checks protobuf round trips
+// for decoder tests.
+package testdata
+
+import (
+	"fmt"
+	"math/rand"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// RandomLogs returns logs with at least one log record
+func RandomLogs(rnd *rand.Rand) plog.Logs {
+	logs := plog.NewLogs()
+	for i := 0; i < 1+rnd.Intn(3); i++ {
+		rl := logs.ResourceLogs().AppendEmpty()
+		randomAttributes(rnd, rl.Resource().Attributes())
+		sl := rl.ScopeLogs().AppendEmpty()
+		sl.Scope().SetName(randomString(rnd))
+		sl.Scope().SetVersion(randomString(rnd))
+		for j := 0; j < 1+rnd.Intn(4); j++ {
+			lr := sl.LogRecords().AppendEmpty()
+			lr.SetTimestamp(pcommon.Timestamp(rnd.Int63()))
+			lr.SetObservedTimestamp(pcommon.Timestamp(rnd.Int63()))
+			lr.SetSeverityNumber(plog.SeverityNumber(1 + rnd.Intn(24)))
+			lr.SetSeverityText(randomString(rnd))
+			lr.Body().SetStr(randomString(rnd))
+			lr.SetTraceID(RandomTraceID(rnd))
+			lr.SetSpanID(RandomSpanID(rnd))
+			randomAttributes(rnd, lr.Attributes())
+		}
+	}
+	return logs
+}
+
+// RandomTraces returns traces with at least one span
+func RandomTraces(rnd *rand.Rand) ptrace.Traces {
+	traces := ptrace.NewTraces()
+	for i := 0; i < 1+rnd.Intn(3); i++ {
+		rs := traces.ResourceSpans().AppendEmpty()
+		randomAttributes(rnd, rs.Resource().Attributes())
+		ss := rs.ScopeSpans().AppendEmpty()
+		ss.Scope().SetName(randomString(rnd))
+		for j := 0; j < 1+rnd.Intn(4); j++ {
+			span := ss.Spans().AppendEmpty()
+			span.SetTraceID(RandomTraceID(rnd))
+			span.SetSpanID(RandomSpanID(rnd))
+			if rnd.Intn(2) == 0 {
+				span.SetParentSpanID(RandomSpanID(rnd))
+			}
+			span.SetName(randomString(rnd))
+			span.SetKind(ptrace.SpanKind(rnd.Intn(6)))
+			start := rnd.Int63n(1 << 62)
+			span.SetStartTimestamp(pcommon.Timestamp(start))
+			span.SetEndTimestamp(pcommon.Timestamp(start + rnd.Int63n(1<<32)))
+			span.Status().SetCode(ptrace.StatusCode(rnd.Intn(3)))
+			span.Status().SetMessage(randomString(rnd))
+			randomAttributes(rnd, span.Attributes())
+		}
+	}
+	return traces
+}
+
+// RandomMetrics returns metrics with at least one gauge, sum or histogram
+func RandomMetrics(rnd *rand.Rand) pmetric.Metrics {
+	metrics := pmetric.NewMetrics()
+	for i := 0; i < 1+rnd.Intn(3); i++ {
+		rm := metrics.ResourceMetrics().AppendEmpty()
+		randomAttributes(rnd, rm.Resource().Attributes())
+		sm := rm.ScopeMetrics().AppendEmpty()
+		sm.Scope().SetName(randomString(rnd))
+		for j := 0; j < 1+rnd.Intn(4); j++ {
+			metric := sm.Metrics().AppendEmpty()
+			metric.SetName(randomString(rnd))
+			metric.SetDescription(randomString(rnd))
+			metric.SetUnit(randomString(rnd))
+			var dp pmetric.NumberDataPoint
+			switch rnd.Intn(3) {
+			case 0:
+				dp = metric.SetEmptyGauge().DataPoints().AppendEmpty()
+			case 1:
+				sum := metric.SetEmptySum()
+				sum.SetIsMonotonic(rnd.Intn(2) == 0)
+				sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dp = sum.DataPoints().AppendEmpty()
+			default:
+				hdp := metric.SetEmptyHistogram().DataPoints().AppendEmpty()
+				hdp.SetTimestamp(pcommon.Timestamp(rnd.Int63()))
+				hdp.SetCount(uint64(rnd.Intn(100)))
+				hdp.ExplicitBounds().FromRaw([]float64{10, 100})
+				hdp.BucketCounts().FromRaw([]uint64{hdp.Count(), 0, 0})
+				randomAttributes(rnd, hdp.Attributes())
+				continue
+			}
+			dp.SetTimestamp(pcommon.Timestamp(rnd.Int63()))
+			dp.SetDoubleValue(rnd.NormFloat64())
+			randomAttributes(rnd, dp.Attributes())
+		}
+	}
+	return metrics
+}
+
+// RandomTraceID returns a non-empty trace ID
+func RandomTraceID(rnd *rand.Rand) pcommon.TraceID {
+	var id pcommon.TraceID
+	rnd.Read(id[:])
+	id[0] |= 1
+	return id
+}
+
+// RandomSpanID returns a non-empty span ID
+func RandomSpanID(rnd *rand.Rand) pcommon.SpanID {
+	var id pcommon.SpanID
+	rnd.Read(id[:])
+	id[0] |= 1
+	return id
+}
+
+// randomAttributes puts a few attributes of every scalar type into attrs
+func randomAttributes(rnd *rand.Rand, attrs pcommon.Map) {
+	for i := 0; i < rnd.Intn(5); i++ {
+		key := fmt.Sprintf("attr.%d", i)
+		switch rnd.Intn(4) {
+		case 0:
+			attrs.PutStr(key, randomString(rnd))
+		case 1:
+			attrs.PutInt(key, rnd.Int63()-rnd.Int63())
+		case 2:
+			attrs.PutDouble(key, rnd.NormFloat64())
+		default:
+			attrs.PutBool(key, rnd.Intn(2) == 0)
+		}
+	}
+}
+
+// randomString returns a short string that may contain non-ASCII runes
+func randomString(rnd *rand.Rand) string {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789 _-./äöü€✓"
+	runes := []rune(alphabet)
+	s := make([]rune, rnd.Intn(16))
+	for i := range s {
+		s[i] = runes[rnd.Intn(len(runes))]
+	}
+	return string(s)
+}
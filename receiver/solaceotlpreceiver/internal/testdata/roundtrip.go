@@ -0,0 +1,33 @@
+package testdata
+
+import (
+	"encoding/base64"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ProtoPayloads returns proto as it is and base64-encoded, the two protobuf
+// payloads the signal receivers decode
+func ProtoPayloads(proto []byte) [][]byte {
+	return [][]byte{proto, []byte(base64.StdEncoding.EncodeToString(proto))}
+}
+
+// RoundTrip marshals n random values to protobuf and checks that handle
+// returns each of them unchanged from every payload of ProtoPayloads
+func RoundTrip[T any](t testing.TB, seed int64, n int, random func(*rand.Rand) T, marshal func(T) ([]byte, error), handle func(payload []byte) []T) {
+	t.Helper()
+	rnd := rand.New(rand.NewSource(seed))
+	for i := 0; i < n; i++ {
+		want := random(rnd)
+		proto, err := marshal(want)
+		require.NoError(t, err)
+		for _, payload := range ProtoPayloads(proto) {
+			got := handle(payload)
+			require.Len(t, got, 1)
+			assert.Equal(t, want, got[0])
+		}
+	}
+}
@@ -0,0 +1,216 @@
+// Package timestamps corrects the timestamps of received telemetry: it fills
+// missing timestamps, rescales timestamps sent in another unit than
+// nanoseconds and clamps or flags timestamps too far from the receive time.
+package timestamps
+
+import (
+	"math"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// Correction is a kind of timestamp correction
+type Correction string
+
+const (
+	Filled           Correction = "filled"
+	FilledObserved   Correction = "filled_observed"
+	UnitSeconds      Correction = "unit_seconds"
+	UnitMilliseconds Correction = "unit_milliseconds"
+	UnitMicroseconds Correction = "unit_microseconds"
+	ClampedFuture    Correction = "clamped_future"
+	ClampedPast      Correction = "clamped_past"
+	FlaggedFuture    Correction = "flagged_future"
+	FlaggedPast      Correction = "flagged_past"
+)
+
+// FlagAttribute marks log records and spans with a timestamp out of range;
+// its value is "future" or "past"
+const FlagAttribute = "solaceotlp.timestamp.out_of_range"
+
+// Unit bounds: current epoch timestamps are about 1.7e9 seconds, 1.7e12
+// milliseconds, 1.7e15 microseconds or 1.7e18 nanoseconds. Each bound is the
+// first value that would overflow when rescaled to nanoseconds, in the year 2554.
+const (
+	maxSeconds      = math.MaxUint64/pcommon.Timestamp(time.Second) + 1
+	maxMilliseconds = math.MaxUint64/pcommon.Timestamp(time.Millisecond) + 1
+	maxMicroseconds = math.MaxUint64/pcommon.Timestamp(time.Microsecond) + 1
+)
+
+// Policy corrects the timestamps of one message
+type Policy struct {
+	Fill      pcommon.Timestamp // replaces missing timestamps
+	Received  pcommon.Timestamp // receive time, replaces missing observed timestamps
+	MaxFuture time.Duration     // allowed time after Received; 0 disables the check
+	MaxPast   time.Duration     // allowed time before Received; 0 disables the check
+	Clamp     bool              // clamp timestamps out of range instead of flagging them
+
+	// Corrections counts the corrections made
+	Corrections map[Correction]int
+}
+
+// Logs corrects the timestamp and observed timestamp of every log record
+func (p *Policy) Logs(logs plog.Logs) {
+	for i := 0; i < logs.ResourceLogs().Len(); i++ {
+		scopeLogs := logs.ResourceLogs().At(i).ScopeLogs()
+		for j := 0; j < scopeLogs.Len(); j++ {
+			records := scopeLogs.At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				record := records.At(k)
+				ts, flag := p.correct(record.Timestamp(), true)
+				record.SetTimestamp(ts)
+				p.flag(record.Attributes(), flag)
+				if record.ObservedTimestamp() == 0 {
+					record.SetObservedTimestamp(p.Received)
+					p.count(FilledObserved)
+				}
+			}
+		}
+	}
+}
+
+// Traces corrects the start, end and event timestamps of every span
+func (p *Policy) Traces(traces ptrace.Traces) {
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		scopeSpans := traces.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < scopeSpans.Len(); j++ {
+			spans := scopeSpans.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				start, startFlag := p.correct(span.StartTimestamp(), true)
+				end, endFlag := p.correct(span.EndTimestamp(), true)
+				span.SetStartTimestamp(start)
+				span.SetEndTimestamp(max(start, end))
+				p.flag(span.Attributes(), firstFlag(startFlag, endFlag))
+				events := span.Events()
+				for e := 0; e < events.Len(); e++ {
+					ts, _ := p.correct(events.At(e).Timestamp(), false)
+					events.At(e).SetTimestamp(ts)
+				}
+			}
+		}
+	}
+}
+
+// Metrics corrects the timestamps of every data point. Data points out of
+// range are only counted when flagged, as an attribute would start a new series.
+func (p *Policy) Metrics(metrics pmetric.Metrics) {
+	type point interface {
+		Timestamp() pcommon.Timestamp
+		SetTimestamp(pcommon.Timestamp)
+		StartTimestamp() pcommon.Timestamp
+		SetStartTimestamp(pcommon.Timestamp)
+	}
+	correct := func(dp point) {
+		ts, _ := p.correct(dp.Timestamp(), true)
+		dp.SetTimestamp(ts)
+		if dp.StartTimestamp() != 0 {
+			start, _ := p.correct(dp.StartTimestamp(), false)
+			dp.SetStartTimestamp(min(start, ts))
+		}
+	}
+	for i := 0; i < metrics.ResourceMetrics().Len(); i++ {
+		scopeMetrics := metrics.ResourceMetrics().At(i).ScopeMetrics()
+		for j := 0; j < scopeMetrics.Len(); j++ {
+			ms := scopeMetrics.At(j).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				m := ms.At(k)
+				switch m.Type() {
+				case pmetric.MetricTypeGauge:
+					for l := 0; l < m.Gauge().DataPoints().Len(); l++ {
+						correct(m.Gauge().DataPoints().At(l))
+					}
+				case pmetric.MetricTypeSum:
+					for l := 0; l < m.Sum().DataPoints().Len(); l++ {
+						correct(m.Sum().DataPoints().At(l))
+					}
+				case pmetric.MetricTypeHistogram:
+					for l := 0; l < m.Histogram().DataPoints().Len(); l++ {
+						correct(m.Histogram().DataPoints().At(l))
+					}
+				case pmetric.MetricTypeExponentialHistogram:
+					for l := 0; l < m.ExponentialHistogram().DataPoints().Len(); l++ {
+						correct(m.ExponentialHistogram().DataPoints().At(l))
+					}
+				case pmetric.MetricTypeSummary:
+					for l := 0; l < m.Summary().DataPoints().Len(); l++ {
+						correct(m.Summary().DataPoints().At(l))
+					}
+				}
+			}
+		}
+	}
+}
+
+// correct rescales ts to nanoseconds and checks its range. A missing
+// timestamp is filled if fill is set and left unset otherwise. It returns
+// the corrected timestamp and the flag value if it is out of range.
+func (p *Policy) correct(ts pcommon.Timestamp, fill bool) (pcommon.Timestamp, string) {
+	if ts == 0 {
+		if !fill || p.Fill == 0 {
+			return 0, ""
+		}
+		p.count(Filled)
+		return p.Fill, ""
+	}
+	switch {
+	case ts < maxSeconds:
+		ts *= pcommon.Timestamp(time.Second)
+		p.count(UnitSeconds)
+	case ts < maxMilliseconds:
+		ts *= pcommon.Timestamp(time.Millisecond)
+		p.count(UnitMilliseconds)
+	case ts < maxMicroseconds:
+		ts *= pcommon.Timestamp(time.Microsecond)
+		p.count(UnitMicroseconds)
+	}
+	if p.MaxFuture > 0 {
+		if limit := p.Received + pcommon.Timestamp(p.MaxFuture); ts > limit {
+			if p.Clamp {
+				p.count(ClampedFuture)
+				return limit, ""
+			}
+			p.count(FlaggedFuture)
+			return ts, "future"
+		}
+	}
+	if p.MaxPast > 0 && p.Received > pcommon.Timestamp(p.MaxPast) {
+		if limit := p.Received - pcommon.Timestamp(p.MaxPast); ts < limit {
+			if p.Clamp {
+				p.count(ClampedPast)
+				return limit, ""
+			}
+			p.count(FlaggedPast)
+			return ts, "past"
+		}
+	}
+	return ts, ""
+}
+
+// flag marks attributes with an out of range flag
+func (p *Policy) flag(attributes pcommon.Map, flag string) {
+	if flag != "" {
+		attributes.PutStr(FlagAttribute, flag)
+	}
+}
+
+func (p *Policy) count(c Correction) {
+	if p.Corrections == nil {
+		p.Corrections = map[Correction]int{}
+	}
+	p.Corrections[c]++
+}
+
+// firstFlag returns the first set flag
+func firstFlag(flags ...string) string {
+	for _, flag := range flags {
+		if flag != "" {
+			return flag
+		}
+	}
+	return ""
+}
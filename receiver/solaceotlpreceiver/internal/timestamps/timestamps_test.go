@@ -0,0 +1,161 @@
+package timestamps
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+var (
+	received = time.Date(2025, 6, 2, 9, 30, 0, 0, time.UTC)
+	sent     = received.Add(-time.Second)
+)
+
+func newPolicy(clamp bool) *Policy {
+	return &Policy{
+		Fill:      pcommon.NewTimestampFromTime(sent),
+		Received:  pcommon.NewTimestampFromTime(received),
+		MaxFuture: time.Minute,
+		MaxPast:   time.Hour,
+		Clamp:     clamp,
+	}
+}
+
+// logWithTimestamp returns logs with one record of timestamp ts
+func logWithTimestamp(ts pcommon.Timestamp) (plog.Logs, plog.LogRecord) {
+	logs := plog.NewLogs()
+	record := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	record.SetTimestamp(ts)
+	return logs, record
+}
+
+func TestPolicy_Units(t *testing.T) {
+	at := received.Add(-time.Minute)
+	for name, tc := range map[string]struct {
+		ts   pcommon.Timestamp
+		want Correction
+	}{
+		"seconds":      {pcommon.Timestamp(at.Unix()), UnitSeconds},
+		"milliseconds": {pcommon.Timestamp(at.UnixMilli()), UnitMilliseconds},
+		"microseconds": {pcommon.Timestamp(at.UnixMicro()), UnitMicroseconds},
+	} {
+		t.Run(name, func(t *testing.T) {
+			policy := newPolicy(false)
+			logs, record := logWithTimestamp(tc.ts)
+			policy.Logs(logs)
+			assert.Equal(t, at, record.Timestamp().AsTime())
+			assert.Equal(t, 1, policy.Corrections[tc.want])
+		})
+	}
+
+	policy := newPolicy(false)
+	logs, record := logWithTimestamp(pcommon.NewTimestampFromTime(at))
+	record.SetObservedTimestamp(pcommon.NewTimestampFromTime(at))
+	policy.Logs(logs)
+	assert.Equal(t, at, record.Timestamp().AsTime())
+	assert.Empty(t, policy.Corrections, "nanoseconds in range are kept")
+}
+
+func TestPolicy_UnitBounds(t *testing.T) {
+	// The largest value of a unit is in the year 2554, the smallest in 1970
+	for name, tc := range map[string]struct {
+		ts   pcommon.Timestamp
+		want Correction
+		unit time.Duration
+		flag string
+	}{
+		"largest seconds":       {maxSeconds - 1, UnitSeconds, time.Second, "future"},
+		"smallest milliseconds": {maxSeconds, UnitMilliseconds, time.Millisecond, "past"},
+		"largest milliseconds":  {maxMilliseconds - 1, UnitMilliseconds, time.Millisecond, "future"},
+		"smallest microseconds": {maxMilliseconds, UnitMicroseconds, time.Microsecond, "past"},
+		"largest microseconds":  {maxMicroseconds - 1, UnitMicroseconds, time.Microsecond, "future"},
+		"smallest nanoseconds":  {maxMicroseconds, "", time.Nanosecond, "past"},
+		"largest nanoseconds":   {math.MaxUint64, "", time.Nanosecond, "future"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			policy := newPolicy(false)
+			logs, record := logWithTimestamp(tc.ts)
+			policy.Logs(logs)
+			assert.Equal(t, tc.ts, record.Timestamp()/pcommon.Timestamp(tc.unit), "rescaling does not wrap")
+			assert.Equal(t, map[string]any{FlagAttribute: tc.flag}, record.Attributes().AsRaw())
+			if tc.want != "" {
+				assert.Equal(t, 1, policy.Corrections[tc.want])
+			}
+		})
+	}
+}
+
+func TestPolicy_FillsMissingTimestamps(t *testing.T) {
+	policy := newPolicy(false)
+	logs, record := logWithTimestamp(0)
+	policy.Logs(logs)
+	assert.Equal(t, sent, record.Timestamp().AsTime())
+	assert.Equal(t, received, record.ObservedTimestamp().AsTime())
+	assert.Equal(t, map[Correction]int{Filled: 1, FilledObserved: 1}, policy.Corrections)
+}
+
+func TestPolicy_OutOfRange(t *testing.T) {
+	future := pcommon.NewTimestampFromTime(received.Add(time.Hour))
+	past := pcommon.NewTimestampFromTime(received.Add(-2 * time.Hour))
+
+	policy := newPolicy(false)
+	logs, record := logWithTimestamp(future)
+	policy.Logs(logs)
+	assert.Equal(t, future, record.Timestamp(), "flagged timestamps are kept")
+	assert.Equal(t, map[string]any{FlagAttribute: "future"}, record.Attributes().AsRaw())
+	logs, record = logWithTimestamp(past)
+	policy.Logs(logs)
+	assert.Equal(t, map[string]any{FlagAttribute: "past"}, record.Attributes().AsRaw())
+	assert.Equal(t, 1, policy.Corrections[FlaggedFuture])
+	assert.Equal(t, 1, policy.Corrections[FlaggedPast])
+
+	policy = newPolicy(true)
+	logs, record = logWithTimestamp(future)
+	policy.Logs(logs)
+	assert.Equal(t, received.Add(time.Minute), record.Timestamp().AsTime())
+	assert.Zero(t, record.Attributes().Len())
+	logs, record = logWithTimestamp(past)
+	policy.Logs(logs)
+	assert.Equal(t, received.Add(-time.Hour), record.Timestamp().AsTime())
+	assert.Equal(t, 1, policy.Corrections[ClampedFuture])
+	assert.Equal(t, 1, policy.Corrections[ClampedPast])
+}
+
+func TestPolicy_Traces(t *testing.T) {
+	start := received.Add(-time.Minute)
+	traces := ptrace.NewTraces()
+	span := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetStartTimestamp(pcommon.Timestamp(start.UnixMilli()))
+	span.Events().AppendEmpty().SetTimestamp(pcommon.Timestamp(start.UnixMilli()))
+	span.Events().AppendEmpty()
+
+	policy := newPolicy(false)
+	policy.Traces(traces)
+	assert.Equal(t, start, span.StartTimestamp().AsTime())
+	assert.Equal(t, sent, span.EndTimestamp().AsTime(), "a missing end is filled")
+	assert.Equal(t, start, span.Events().At(0).Timestamp().AsTime())
+	assert.Zero(t, span.Events().At(1).Timestamp(), "missing event timestamps stay unset")
+}
+
+func TestPolicy_Metrics(t *testing.T) {
+	metrics := pmetric.NewMetrics()
+	points := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptySum().DataPoints()
+	withStart := points.AppendEmpty()
+	withStart.SetStartTimestamp(pcommon.Timestamp(received.Add(-time.Minute).Unix()))
+	withStart.SetTimestamp(pcommon.Timestamp(received.UnixMilli()))
+	points.AppendEmpty()
+
+	policy := newPolicy(false)
+	policy.Metrics(metrics)
+	assert.Equal(t, received.Add(-time.Minute), withStart.StartTimestamp().AsTime())
+	assert.Equal(t, received, withStart.Timestamp().AsTime())
+	assert.Equal(t, sent, points.At(1).Timestamp().AsTime())
+	assert.Zero(t, points.At(1).StartTimestamp())
+	assert.Equal(t, map[Correction]int{UnitSeconds: 1, UnitMilliseconds: 1, Filled: 1}, policy.Corrections)
+}
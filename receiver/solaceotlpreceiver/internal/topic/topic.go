@@ -0,0 +1,104 @@
+// Package topic reads resource attributes from Solace topic levels.
+package topic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Template describes the levels of a topic such as
+// "otel/{deployment.environment}/{team}/{service.name}/*". A level in braces
+// captures the topic level as attribute, "*" matches any level, a trailing
+// ">" matches the remaining levels and any other level must match literally.
+type Template struct {
+	levels []templateLevel
+	rest   bool // the template ends with ">"
+}
+
+type templateLevel struct {
+	literal string
+	name    string // attribute name of a captured level
+	any     bool
+}
+
+// ParseTemplate parses a topic template
+func ParseTemplate(s string) (Template, error) {
+	if s == "" {
+		return Template{}, fmt.Errorf("topic template must not be empty")
+	}
+	var t Template
+	levels := strings.Split(s, "/")
+	names := map[string]bool{}
+	for i, level := range levels {
+		switch {
+		case level == ">" && i == len(levels)-1:
+			t.rest = true
+		case level == "*":
+			t.levels = append(t.levels, templateLevel{any: true})
+		case strings.HasPrefix(level, "{") && strings.HasSuffix(level, "}"):
+			name := strings.TrimSpace(level[1 : len(level)-1])
+			if name == "" {
+				return Template{}, fmt.Errorf("level %d of topic template %q has no attribute name", i+1, s)
+			}
+			if names[name] {
+				return Template{}, fmt.Errorf("attribute %q is captured twice in topic template %q", name, s)
+			}
+			names[name] = true
+			t.levels = append(t.levels, templateLevel{name: name})
+		case level == "" || strings.ContainsAny(level, "{}*>"):
+			return Template{}, fmt.Errorf("invalid level %q in topic template %q", level, s)
+		default:
+			t.levels = append(t.levels, templateLevel{literal: level})
+		}
+	}
+	return t, nil
+}
+
+// Extract returns the attributes captured from topic. It reports false if
+// the topic does not match the template.
+func (t Template) Extract(topic string) (map[string]string, bool) {
+	levels := strings.Split(topic, "/")
+	if len(levels) < len(t.levels) || (!t.rest && len(levels) != len(t.levels)) || (t.rest && len(levels) == len(t.levels)) {
+		return nil, false
+	}
+	attributes := make(map[string]string, len(t.levels))
+	for i, level := range t.levels {
+		switch {
+		case level.any:
+		case level.name != "":
+			if levels[i] == "" {
+				return nil, false
+			}
+			attributes[level.name] = levels[i]
+		case level.literal != levels[i]:
+			return nil, false
+		}
+	}
+	return attributes, true
+}
+
+// Matches reports whether topic matches a Solace topic subscription. A level
+// of "*" matches exactly one level, a level ending in "*" matches a level with
+// that prefix, and a trailing ">" matches one or more levels.
+func Matches(subscription, topic string) bool {
+	subLevels := strings.Split(subscription, "/")
+	topicLevels := strings.Split(topic, "/")
+	for i, level := range subLevels {
+		if level == ">" && i == len(subLevels)-1 {
+			return len(topicLevels) > i
+		}
+		if i >= len(topicLevels) {
+			return false
+		}
+		switch {
+		case level == "*":
+		case strings.HasSuffix(level, "*"):
+			if !strings.HasPrefix(topicLevels[i], strings.TrimSuffix(level, "*")) {
+				return false
+			}
+		case level != topicLevels[i]:
+			return false
+		}
+	}
+	return len(subLevels) == len(topicLevels)
+}
@@ -0,0 +1,65 @@
+package topic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate_Extract(t *testing.T) {
+	template, err := ParseTemplate("otel/{deployment.environment}/{team}/{service.name}/*")
+	require.NoError(t, err)
+
+	attributes, ok := template.Extract("otel/prod/payments/checkout/logs")
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{"deployment.environment": "prod", "team": "payments", "service.name": "checkout"}, attributes)
+
+	for _, topic := range []string{
+		"otel/prod/payments/checkout",
+		"otel/prod/payments/checkout/logs/extra",
+		"metrics/prod/payments/checkout/logs",
+		"otel//payments/checkout/logs",
+	} {
+		_, ok := template.Extract(topic)
+		assert.False(t, ok, topic)
+	}
+}
+
+func TestTemplate_ExtractRest(t *testing.T) {
+	template, err := ParseTemplate("otel/{env}/>")
+	require.NoError(t, err)
+
+	attributes, ok := template.Extract("otel/dev/a/b/c")
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{"env": "dev"}, attributes)
+	_, ok = template.Extract("otel/dev")
+	assert.False(t, ok, "a trailing > matches at least one level")
+}
+
+func TestParseTemplate_Errors(t *testing.T) {
+	for _, template := range []string{"", "otel/{}", "otel/{a}/{a}", "otel//{a}", "otel/>/{a}", "otel/a*", "otel/{a"} {
+		_, err := ParseTemplate(template)
+		assert.Error(t, err, template)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	for _, tc := range []struct {
+		subscription, topic string
+		want                bool
+	}{
+		{"otel/prod/>", "otel/prod/payments/logs", true},
+		{"otel/prod/>", "otel/prod", false},
+		{"otel/*/payments/*", "otel/dev/payments/logs", true},
+		{"otel/pr*/payments/logs", "otel/prod/payments/logs", true},
+		{"otel/pr*/payments/logs", "otel/dev/payments/logs", false},
+		{"otel/prod", "otel/prod/logs", false},
+		{"otel/traces", "otel/traces", true},
+		{"otel/traces", "otel/logs", false},
+		{"otel/*", "otel/traces", true},
+		{"otel/*", "otel/traces/prod", false},
+	} {
+		assert.Equal(t, tc.want, Matches(tc.subscription, tc.topic), "%s %s", tc.subscription, tc.topic)
+	}
+}
@@ -0,0 +1,46 @@
+package traces
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	basereceiver "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/receiver"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/testdata"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/solacetest"
+)
+
+func newTestReceiver(next consumer.Traces) *Receiver {
+	settings := receivertest.NewNopSettings(component.MustNewType("solaceotlp"))
+	return NewReceiver(settings, basereceiver.NewBaseReceiver(settings, &solaceconfig.Config{}), next)
+}
+
+// FuzzHandleMessage feeds arbitrary payloads through the raw protobuf,
+// base64 protobuf and JSON paths
+func FuzzHandleMessage(f *testing.F) {
+	proto, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(testdata.RandomTraces(rand.New(rand.NewSource(1))))
+	require.NoError(f, err)
+	for _, payload := range testdata.ProtoPayloads(proto) {
+		f.Add(payload)
+	}
+
+	r := newTestReceiver(consumertest.NewNop())
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		r.HandleMessage(solacetest.NewMessage("otel/traces", payload))
+	})
+}
+
+func TestHandleMessage_RoundTrip(t *testing.T) {
+	testdata.RoundTrip(t, 30, 100, testdata.RandomTraces, (&ptrace.ProtoMarshaler{}).MarshalTraces, func(payload []byte) []ptrace.Traces {
+		sink := new(consumertest.TracesSink)
+		newTestReceiver(sink).HandleMessage(solacetest.NewMessage("otel/traces", payload))
+		return sink.AllTraces()
+	})
+}
@@ -1,7 +1,9 @@
 package util
 
 import (
+	"encoding/base64"
 	"encoding/hex"
+	"fmt"
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
 )
@@ -9,13 +11,53 @@ import (
 // HexStringToTraceID converts a hex string to a TraceID
 func HexStringToTraceID(s string) (pcommon.TraceID, error) {
 	var traceID pcommon.TraceID
-	_, err := hex.Decode(traceID[:], []byte(s))
+	err := decodeID(traceID[:], s)
 	return traceID, err
 }
 
 // HexStringToSpanID converts a hex string to a SpanID
 func HexStringToSpanID(s string) (pcommon.SpanID, error) {
 	var spanID pcommon.SpanID
-	_, err := hex.Decode(spanID[:], []byte(s))
+	err := decodeID(spanID[:], s)
 	return spanID, err
 }
+
+// decodeID decodes s into dst, rejecting input longer than dst
+func decodeID(dst []byte, s string) error {
+	if hex.DecodedLen(len(s)) > len(dst) {
+		return fmt.Errorf("hex ID %q exceeds %d bytes", s, len(dst))
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err
+}
+
+// ParseTraceID converts a hex or base64 string to a TraceID
+func ParseTraceID(s string) (pcommon.TraceID, error) {
+	var traceID pcommon.TraceID
+	err := parseID(traceID[:], s)
+	return traceID, err
+}
+
+// ParseSpanID converts a hex or base64 string to a SpanID
+func ParseSpanID(s string) (pcommon.SpanID, error) {
+	var spanID pcommon.SpanID
+	err := parseID(spanID[:], s)
+	return spanID, err
+}
+
+// parseID decodes s into dst as hex or, failing that, as base64 of exactly
+// len(dst) bytes. Base64 IDs of this length always end in '=' padding or
+// contain non-hex letters, so the two never overlap.
+func parseID(dst []byte, s string) error {
+	if err := decodeID(dst, s); err == nil {
+		return nil
+	}
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if raw, err := encoding.DecodeString(s); err == nil && len(raw) == len(dst) {
+			copy(dst, raw)
+			return nil
+		}
+	}
+	clear(dst)
+	return fmt.Errorf("ID %q is neither hex nor base64 of %d bytes", s, len(dst))
+}
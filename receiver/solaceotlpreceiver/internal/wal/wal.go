@@ -0,0 +1,136 @@
+// Package wal is a write-ahead log of records persisted in a collector storage
+// extension. Records are read back in the order they were appended.
+package wal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"go.opentelemetry.io/collector/extension/xextension/storage"
+)
+
+// Storage keys: the first record, the next record and one key per record
+const (
+	headKey      = "head"
+	tailKey      = "tail"
+	recordPrefix = "record/"
+)
+
+// ErrFull is returned by Append when the log holds its capacity of records
+var ErrFull = errors.New("write-ahead log is full")
+
+// Log is a FIFO of records persisted in a storage client. It is safe for
+// concurrent use.
+type Log struct {
+	mu       sync.Mutex
+	client   storage.Client
+	capacity int
+	head     uint64 // index of the first record
+	tail     uint64 // index of the next appended record
+	ready    chan struct{}
+}
+
+// Open opens the log stored in client. It holds at most capacity records.
+func Open(ctx context.Context, client storage.Client, capacity int) (*Log, error) {
+	ops := []*storage.Operation{storage.GetOperation(headKey), storage.GetOperation(tailKey)}
+	if err := client.Batch(ctx, ops...); err != nil {
+		return nil, fmt.Errorf("failed to open write-ahead log: %w", err)
+	}
+	l := &Log{client: client, capacity: capacity, ready: make(chan struct{}, 1)}
+	var err error
+	if l.head, err = parseIndex(ops[0].Value); err != nil {
+		return nil, fmt.Errorf("failed to open write-ahead log: head: %w", err)
+	}
+	if l.tail, err = parseIndex(ops[1].Value); err != nil {
+		return nil, fmt.Errorf("failed to open write-ahead log: tail: %w", err)
+	}
+	if l.tail < l.head {
+		return nil, fmt.Errorf("failed to open write-ahead log: tail %d is before head %d", l.tail, l.head)
+	}
+	if l.tail > l.head {
+		l.ready <- struct{}{}
+	}
+	return l, nil
+}
+
+// Len returns the number of records in the log
+func (l *Log) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.tail - l.head)
+}
+
+// Ready receives a value after records were appended to an empty log
+func (l *Log) Ready() <-chan struct{} {
+	return l.ready
+}
+
+// Append persists record at the end of the log
+func (l *Log) Append(ctx context.Context, record []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if int(l.tail-l.head) >= l.capacity {
+		return ErrFull
+	}
+	if err := l.client.Batch(ctx,
+		storage.SetOperation(recordKey(l.tail), record),
+		storage.SetOperation(tailKey, formatIndex(l.tail+1))); err != nil {
+		return fmt.Errorf("failed to append to write-ahead log: %w", err)
+	}
+	l.tail++
+	select {
+	case l.ready <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// First returns the first record. It reports false if the log is empty. A
+// record missing from the storage is returned as nil.
+func (l *Log) First(ctx context.Context) ([]byte, bool, error) {
+	l.mu.Lock()
+	head, empty := l.head, l.head == l.tail
+	l.mu.Unlock()
+	if empty {
+		return nil, false, nil
+	}
+	record, err := l.client.Get(ctx, recordKey(head))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read write-ahead log: %w", err)
+	}
+	return record, true, nil
+}
+
+// RemoveFirst removes the first record
+func (l *Log) RemoveFirst(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.head == l.tail {
+		return nil
+	}
+	if err := l.client.Batch(ctx,
+		storage.DeleteOperation(recordKey(l.head)),
+		storage.SetOperation(headKey, formatIndex(l.head+1))); err != nil {
+		return fmt.Errorf("failed to remove from write-ahead log: %w", err)
+	}
+	l.head++
+	return nil
+}
+
+func recordKey(index uint64) string {
+	return recordPrefix + strconv.FormatUint(index, 10)
+}
+
+func formatIndex(index uint64) []byte {
+	return []byte(strconv.FormatUint(index, 10))
+}
+
+func parseIndex(value []byte) (uint64, error) {
+	if value == nil {
+		return 0, nil
+	}
+	return strconv.ParseUint(string(value), 10, 64)
+}
@@ -0,0 +1,90 @@
+package wal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/storagetest"
+)
+
+// first returns the first record of l and fails if it is empty
+func first(t *testing.T, l *Log) string {
+	record, ok, err := l.First(context.Background())
+	require.NoError(t, err)
+	require.True(t, ok)
+	return string(record)
+}
+
+func TestLog_Order(t *testing.T) {
+	ctx := context.Background()
+	l, err := Open(ctx, storagetest.New(), 10)
+	require.NoError(t, err)
+	_, ok, err := l.First(ctx)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	require.NoError(t, l.RemoveFirst(ctx), "removing from an empty log is a no-op")
+
+	require.NoError(t, l.Append(ctx, []byte("a")))
+	require.NoError(t, l.Append(ctx, []byte("b")))
+	assert.Equal(t, 2, l.Len())
+	assert.Equal(t, "a", first(t, l))
+	assert.Equal(t, "a", first(t, l), "First does not remove")
+	require.NoError(t, l.RemoveFirst(ctx))
+	assert.Equal(t, "b", first(t, l))
+	require.NoError(t, l.RemoveFirst(ctx))
+	assert.Zero(t, l.Len())
+}
+
+func TestLog_Reopen(t *testing.T) {
+	ctx := context.Background()
+	client := storagetest.New()
+	l, err := Open(ctx, client, 10)
+	require.NoError(t, err)
+	for _, record := range []string{"a", "b", "c"} {
+		require.NoError(t, l.Append(ctx, []byte(record)))
+	}
+	require.NoError(t, l.RemoveFirst(ctx))
+
+	reopened, err := Open(ctx, client, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, reopened.Len())
+	assert.Equal(t, "b", first(t, reopened))
+	select {
+	case <-reopened.Ready():
+	default:
+		t.Fatal("a log opened with records is ready")
+	}
+	_, ok := client.Value("record/0")
+	assert.False(t, ok, "removed records are deleted")
+}
+
+func TestLog_Capacity(t *testing.T) {
+	ctx := context.Background()
+	l, err := Open(ctx, storagetest.New(), 1)
+	require.NoError(t, err)
+	require.NoError(t, l.Append(ctx, []byte("a")))
+	assert.ErrorIs(t, l.Append(ctx, []byte("b")), ErrFull)
+	require.NoError(t, l.RemoveFirst(ctx))
+	assert.NoError(t, l.Append(ctx, []byte("b")))
+}
+
+func TestOpen_InvalidIndices(t *testing.T) {
+	for name, values := range map[string]map[string]string{
+		"head":               {headKey: "x"},
+		"tail":               {tailKey: "-1"},
+		"tail precedes head": {headKey: "3", tailKey: "2"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			client := storagetest.New()
+			for key, value := range values {
+				require.NoError(t, client.Set(ctx, key, []byte(value)))
+			}
+			_, err := Open(ctx, client, 10)
+			assert.Error(t, err)
+		})
+	}
+}
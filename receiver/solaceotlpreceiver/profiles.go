@@ -0,0 +1,46 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/xconsumer"
+	"go.opentelemetry.io/collector/featuregate"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/xreceiver"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+)
+
+// profilesFeatureGate enables the experimental profiles signal. It is checked
+// when a profiles receiver is created, after the collector applied
+// --feature-gates.
+var profilesFeatureGate = featuregate.GlobalRegistry().MustRegister(
+	"receiver.solaceotlp.profiles",
+	featuregate.StageAlpha,
+	featuregate.WithRegisterDescription("When enabled, the solaceotlp receiver accepts OTLP profiles in profiles pipelines"),
+)
+
+// createProfilesReceiver creates a new profiles receiver
+func createProfilesReceiver(
+	_ context.Context,
+	settings receiver.Settings,
+	cfg component.Config,
+	consumer xconsumer.Profiles,
+) (xreceiver.Profiles, error) {
+	if !profilesFeatureGate.IsEnabled() {
+		return nil, fmt.Errorf("profiles require the %s feature gate", profilesFeatureGate.ID())
+	}
+	if consumer == nil {
+		return nil, fmt.Errorf("nil consumer")
+	}
+
+	conf := cfg.(*solaceconfig.Config)
+	receiver, err := receivers.get(settings, conf)
+	if err != nil {
+		return nil, err
+	}
+	receiver.profilesConsumer = consumer
+	return receiver, nil
+}
@@ -0,0 +1,134 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/xconsumer"
+	"go.opentelemetry.io/collector/featuregate"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.opentelemetry.io/collector/receiver/xreceiver"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+)
+
+// enableProfiles enables the profiles feature gate for the duration of a test
+func enableProfiles(t *testing.T) {
+	require.NoError(t, featuregate.GlobalRegistry().Set(profilesFeatureGate.ID(), true))
+	t.Cleanup(func() {
+		require.NoError(t, featuregate.GlobalRegistry().Set(profilesFeatureGate.ID(), false))
+	})
+}
+
+// newTestProfiles returns profiles with one CPU profile of two samples
+func newTestProfiles() pprofile.Profiles {
+	profiles := pprofile.NewProfiles()
+	resourceProfiles := profiles.ResourceProfiles().AppendEmpty()
+	resourceProfiles.Resource().Attributes().PutStr("service.name", "api")
+	profile := resourceProfiles.ScopeProfiles().AppendEmpty().Profiles().AppendEmpty()
+	profile.StringTable().Append("", "cpu", "nanoseconds")
+	profile.SetProfileID(pprofile.ProfileID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	profile.SetTime(1748856600000000000)
+	profile.SetDuration(pcommon.Timestamp(10 * time.Second))
+	sampleType := profile.SampleType().AppendEmpty()
+	sampleType.SetTypeStrindex(1)
+	sampleType.SetUnitStrindex(2)
+	profile.Sample().AppendEmpty().Value().Append(1_000_000)
+	profile.Sample().AppendEmpty().Value().Append(2_000_000)
+	return profiles
+}
+
+func TestNewFactory_ProfilesGate(t *testing.T) {
+	factory := NewFactory().(xreceiver.Factory)
+	assert.Equal(t, component.StabilityLevelDevelopment, factory.ProfilesStability())
+	_, err := factory.CreateProfiles(context.Background(), receivertest.NewNopSettings(typeStr),
+		factory.CreateDefaultConfig(), consumertest.NewNop())
+	assert.ErrorContains(t, err, "receiver.solaceotlp.profiles", "profiles are not created without the feature gate")
+
+	// The collector applies --feature-gates after the factories are built
+	enableProfiles(t)
+	r, err := factory.CreateProfiles(context.Background(), receivertest.NewNopSettings(typeStr),
+		factory.CreateDefaultConfig(), consumertest.NewNop())
+	require.NoError(t, err)
+	assert.NotNil(t, r)
+	_, err = factory.CreateProfiles(context.Background(), receivertest.NewNopSettings(typeStr),
+		factory.CreateDefaultConfig(), nil)
+	assert.Error(t, err)
+}
+
+func TestHandleMessage_Profiles(t *testing.T) {
+	enableProfiles(t)
+	proto, err := (&pprofile.ProtoMarshaler{}).MarshalProfiles(newTestProfiles())
+	require.NoError(t, err)
+	otlpJSON, err := (&pprofile.JSONMarshaler{}).MarshalProfiles(newTestProfiles())
+	require.NoError(t, err)
+
+	for name, payload := range map[string][]byte{"protobuf": proto, "OTLP/JSON": otlpJSON} {
+		t.Run(name, func(t *testing.T) {
+			broker := newTestBroker()
+			sink := new(consumertest.ProfilesSink)
+			factory := NewFactory().(xreceiver.Factory)
+			r, err := factory.CreateProfiles(context.Background(), receivertest.NewNopSettings(typeStr),
+				newTestConfig(solaceconfig.InitialConnectBlock), sink)
+			require.NoError(t, err)
+			r.(*sharedReceiver).messagingService = broker.NewMessagingService()
+			require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+			broker.Publish("otel/profiles", payload)
+			require.Eventually(t, func() bool { return broker.Acked(testQueue) == 1 }, time.Second, time.Millisecond)
+			require.Len(t, sink.AllProfiles(), 1)
+			got := sink.AllProfiles()[0]
+			assert.Equal(t, 1, got.ResourceProfiles().At(0).ScopeProfiles().At(0).Profiles().Len())
+			assert.Equal(t, 2, got.SampleCount())
+			assert.Equal(t, "api", got.ResourceProfiles().At(0).Resource().Attributes().AsRaw()["service.name"])
+		})
+	}
+}
+
+func TestHandleMessage_ProfilesSettlement(t *testing.T) {
+	payload, err := (&pprofile.ProtoMarshaler{}).MarshalProfiles(newTestProfiles())
+	require.NoError(t, err)
+
+	for name, consumeErr := range map[string]error{
+		"no pipeline":     nil,
+		"permanent error": consumererror.NewPermanent(errors.New("invalid")),
+		"transient error": errors.New("busy"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			broker := newTestBroker()
+			r, err := NewReceiver(receivertest.NewNopSettings(typeStr), newTestConfig(solaceconfig.InitialConnectBlock),
+				consumertest.NewNop(), consumertest.NewNop(), consumertest.NewNop(), broker.NewMessagingService())
+			require.NoError(t, err)
+			var calls atomic.Int64
+			if consumeErr != nil {
+				r.profilesConsumer, err = xconsumer.NewProfiles(func(context.Context, pprofile.Profiles) error {
+					calls.Add(1)
+					return consumeErr
+				})
+				require.NoError(t, err)
+			}
+			require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+			broker.Publish("otel/profiles", payload)
+			if consumeErr == nil || consumererror.IsPermanent(consumeErr) {
+				require.Eventually(t, func() bool { return len(broker.Rejected(testQueue)) == 1 }, time.Second, time.Millisecond)
+				return
+			}
+			require.Eventually(t, func() bool { return calls.Load() > 1 }, time.Second, time.Millisecond, "failed messages are redelivered")
+			assert.Empty(t, broker.Rejected(testQueue))
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package solaceotlpreceiver
+
+import (
+	"fmt"
+	"slices"
+
+	"go.opentelemetry.io/collector/pipeline"
+	"go.opentelemetry.io/collector/pipeline/xpipeline"
+	"go.uber.org/zap"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/selector"
+)
+
+// queueBinding is a queue the receiver consumes and the signals expected on it
+type queueBinding struct {
+	name     string
+	signals  []pipeline.Signal // signals that list the queue; nil for the shared queue
+	strict   bool              // reject messages of other signals
+	consumer interface{}       // the persistent receiver of the queue, once connected
+
+	brokerSelector string             // selector the broker evaluates for the queue consumer
+	clientSelector *selector.Selector // selector the receiver evaluates before decoding
+}
+
+// carries reports whether signal is expected on the queue
+func (q *queueBinding) carries(signal pipeline.Signal) bool {
+	return len(q.signals) == 0 || slices.Contains(q.signals, signal)
+}
+
+// bindQueues returns the queues of the served signals. Signals without own
+// queues share the configured queue.
+func (r *Receiver) bindQueues() []*queueBinding {
+	var bindings []*queueBinding
+	byName := map[string]*queueBinding{}
+	bind := func(name string, signal pipeline.Signal, strict bool) {
+		binding, ok := byName[name]
+		if !ok {
+			binding = &queueBinding{name: name}
+			byName[name] = binding
+			bindings = append(bindings, binding)
+		}
+		if signal != (pipeline.Signal{}) {
+			binding.signals = append(binding.signals, signal)
+			binding.strict = binding.strict || strict
+		}
+	}
+	shared := false
+	for _, signal := range []pipeline.Signal{pipeline.SignalLogs, pipeline.SignalTraces, pipeline.SignalMetrics, xpipeline.SignalProfiles} {
+		if !r.serves(signal) {
+			continue
+		}
+		cfg := r.signalConfig(signal)
+		if cfg == nil || len(cfg.Queues) == 0 {
+			shared = true
+			continue
+		}
+		for _, name := range cfg.Queues {
+			bind(name, signal, cfg.Strict)
+		}
+	}
+	if shared {
+		if binding, ok := byName[r.config.Queue]; ok {
+			// A signal queue that is also the shared queue accepts every signal
+			binding.signals, binding.strict = nil, false
+		} else {
+			bind(r.config.Queue, pipeline.Signal{}, false)
+		}
+	}
+	r.applySelectors(bindings)
+	return bindings
+}
+
+// serves reports whether the receiver has a consumer for signal
+func (r *Receiver) serves(signal pipeline.Signal) bool {
+	switch signal {
+	case pipeline.SignalLogs:
+		return r.logsConsumer != nil
+	case pipeline.SignalTraces:
+		return r.tracesConsumer != nil
+	case pipeline.SignalMetrics:
+		return r.metricsConsumer != nil
+	case xpipeline.SignalProfiles:
+		return r.profilesConsumer != nil
+	}
+	return false
+}
+
+// signalConfig returns the settings of signal; profiles have none
+func (r *Receiver) signalConfig(signal pipeline.Signal) *solaceconfig.SignalConfig {
+	switch signal {
+	case pipeline.SignalLogs:
+		return &r.config.Logs
+	case pipeline.SignalTraces:
+		return &r.config.Traces
+	case pipeline.SignalMetrics:
+		return &r.config.Metrics
+	}
+	return nil
+}
+
+// checkExpected checks a decoded message against its queue and the expected
+// format of its signal. Mismatches only fail under strict settings.
+func (r *Receiver) checkExpected(q *queueBinding, data decoded) error {
+	if !q.carries(data.signal) {
+		if q.strict {
+			return fmt.Errorf("unexpected %s on queue %q", data.signal, q.name)
+		}
+		r.logger.Warn("Message of another signal on queue",
+			zap.String("queue", q.name), zap.String("signal", data.signal.String()))
+	}
+	cfg := r.signalConfig(data.signal)
+	if cfg == nil || cfg.Format == "" || cfg.Format == data.format {
+		return nil
+	}
+	if cfg.Strict {
+		return fmt.Errorf("%s payload in format %q, expected %q", data.signal, data.format, cfg.Format)
+	}
+	r.logger.Warn("Payload in unexpected format",
+		zap.String("signal", data.signal.String()),
+		zap.String("format", data.format),
+		zap.String("expected", cfg.Format))
+	return nil
+}
@@ -0,0 +1,186 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/solacetest"
+)
+
+const (
+	testLogsPayload   = `{"body": "hello"}`
+	testTracesPayload = `{"name": "span", "trace_id": "4bf92f3577b34da6a3ce929d0e0e4736", "span_id": "00f067aa0ba902b7"}`
+)
+
+// newSignalQueuesBroker returns a broker with a queue per signal
+func newSignalQueuesBroker() *solacetest.Broker {
+	broker := newTestBroker()
+	broker.CreateQueue("logs")
+	broker.CreateQueue("traces")
+	return broker
+}
+
+// startReceiver starts r and shuts it down at the end of the test
+func startReceiver(t *testing.T, r component.Component) {
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+}
+
+func TestFactory_SharesReceiverPerConfig(t *testing.T) {
+	broker := newTestBroker()
+	factory := NewFactory()
+	cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+	logsSink := new(consumertest.LogsSink)
+	tracesSink := new(consumertest.TracesSink)
+	logs, err := factory.CreateLogs(context.Background(), receivertest.NewNopSettings(typeStr), cfg, logsSink)
+	require.NoError(t, err)
+	traces, err := factory.CreateTraces(context.Background(), receivertest.NewNopSettings(typeStr), cfg, tracesSink)
+	require.NoError(t, err)
+	require.Same(t, logs, traces, "pipelines of one configuration share the receiver")
+	logs.(*sharedReceiver).messagingService = broker.NewMessagingService()
+
+	startReceiver(t, logs)
+	require.NoError(t, traces.Start(context.Background(), componenttest.NewNopHost()), "a second Start is a no-op")
+	broker.Publish("otel/logs", []byte(testLogsPayload))
+	broker.Publish("otel/traces", []byte(testTracesPayload))
+	require.Eventually(t, func() bool { return broker.Acked(testQueue) == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, 1, logsSink.LogRecordCount())
+	assert.Equal(t, 1, tracesSink.SpanCount())
+
+	other, err := factory.CreateLogs(context.Background(), receivertest.NewNopSettings(typeStr),
+		newTestConfig(solaceconfig.InitialConnectBlock), consumertest.NewNop())
+	require.NoError(t, err)
+	assert.NotSame(t, logs, other)
+}
+
+func TestHandleMessage_SignalQueues(t *testing.T) {
+	broker := newSignalQueuesBroker()
+	cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+	cfg.Logs.Queues = []string{"logs"}
+	cfg.Traces.Queues = []string{"traces"}
+	sink := new(consumertest.LogsSink)
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, sink, nil, nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	startReceiver(t, r)
+
+	require.NoError(t, broker.PublishToQueue("logs", []byte(testLogsPayload)))
+	require.NoError(t, broker.PublishToQueue("traces", []byte(testTracesPayload)))
+	require.NoError(t, broker.PublishToQueue(testQueue, []byte(testLogsPayload)))
+	require.Eventually(t, func() bool { return broker.Acked("logs") == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, 1, sink.LogRecordCount())
+	assert.Equal(t, 1, broker.Pending("traces"), "a logs pipeline does not consume the traces queue")
+	assert.Equal(t, 1, broker.Pending(testQueue), "signals with own queues do not consume the shared queue")
+}
+
+func TestHandleMessage_OtherSignalOnQueue(t *testing.T) {
+	for name, tc := range map[string]struct {
+		strict       bool
+		serveTraces  bool
+		wantRejected bool
+	}{
+		"lenient":                 {serveTraces: true},
+		"lenient without traces":  {wantRejected: true},
+		"strict":                  {strict: true, serveTraces: true, wantRejected: true},
+		"strict without consumer": {strict: true, wantRejected: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			broker := newSignalQueuesBroker()
+			cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+			cfg.Logs.Queues = []string{"logs"}
+			cfg.Logs.Strict = tc.strict
+			tracesSink := new(consumertest.TracesSink)
+			r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, consumertest.NewNop(), nil, nil, broker.NewMessagingService())
+			require.NoError(t, err)
+			if tc.serveTraces {
+				r.tracesConsumer = tracesSink
+			}
+			startReceiver(t, r)
+
+			require.NoError(t, broker.PublishToQueue("logs", []byte(testTracesPayload)))
+			if tc.wantRejected {
+				require.Eventually(t, func() bool { return len(broker.Rejected("logs")) == 1 }, time.Second, time.Millisecond)
+				assert.Zero(t, tracesSink.SpanCount())
+				return
+			}
+			require.Eventually(t, func() bool { return broker.Acked("logs") == 1 }, time.Second, time.Millisecond)
+			assert.Equal(t, 1, tracesSink.SpanCount())
+		})
+	}
+}
+
+func TestHandleMessage_RejectsSignalWithoutConsumer(t *testing.T) {
+	broker := newTestBroker()
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), newTestConfig(solaceconfig.InitialConnectBlock),
+		nil, nil, consumertest.NewNop(), broker.NewMessagingService())
+	require.NoError(t, err)
+	startReceiver(t, r)
+
+	broker.Publish("otel/logs", []byte(testLogsPayload))
+	broker.Publish("otel/traces", []byte(testTracesPayload))
+	require.Eventually(t, func() bool { return len(broker.Rejected(testQueue)) == 2 }, time.Second, time.Millisecond)
+}
+
+func TestHandleMessage_ExpectedFormat(t *testing.T) {
+	logs := plog.NewLogs()
+	logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("proto")
+	proto, err := (&plog.ProtoMarshaler{}).MarshalLogs(logs)
+	require.NoError(t, err)
+
+	for _, strict := range []bool{false, true} {
+		broker := newTestBroker()
+		cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+		cfg.Logs.Format = solaceconfig.FormatJSON
+		cfg.Logs.Strict = strict
+		sink := new(consumertest.LogsSink)
+		r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, sink, nil, nil, broker.NewMessagingService())
+		require.NoError(t, err)
+		startReceiver(t, r)
+
+		broker.Publish("otel/logs", []byte(testLogsPayload))
+		broker.Publish("otel/logs", proto)
+		if strict {
+			require.Eventually(t, func() bool {
+				return broker.Acked(testQueue) == 1 && len(broker.Rejected(testQueue)) == 1
+			}, time.Second, time.Millisecond)
+			assert.Equal(t, 1, sink.LogRecordCount())
+			continue
+		}
+		require.Eventually(t, func() bool { return broker.Acked(testQueue) == 2 }, time.Second, time.Millisecond)
+		assert.Equal(t, 2, sink.LogRecordCount())
+	}
+}
+
+func TestConfigValidate_Signals(t *testing.T) {
+	extension := component.MustNewID("text_encoding")
+	for name, tc := range map[string]struct {
+		signal  solaceconfig.SignalConfig
+		wantErr bool
+	}{
+		"queues":              {signal: solaceconfig.SignalConfig{Queues: []string{"a", "b"}, Format: solaceconfig.FormatProto, Strict: true}},
+		"empty queue":         {signal: solaceconfig.SignalConfig{Queues: []string{""}}, wantErr: true},
+		"duplicate queue":     {signal: solaceconfig.SignalConfig{Queues: []string{"a", "a"}}, wantErr: true},
+		"unknown format":      {signal: solaceconfig.SignalConfig{Format: "xml"}, wantErr: true},
+		"format and encoding": {signal: solaceconfig.SignalConfig{Format: solaceconfig.FormatJSON, Encoding: &extension}, wantErr: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+			cfg.Traces = tc.signal
+			err := cfg.Validate()
+			if tc.wantErr {
+				assert.ErrorContains(t, err, "traces: ")
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -2,22 +2,27 @@ package solaceotlpreceiver
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/hex"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
+	"net"
+	"net/http"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
 	"go.opentelemetry.io/collector/consumer"
-	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/xconsumer"
+	"go.opentelemetry.io/collector/extension/xextension/storage"
 	"go.opentelemetry.io/collector/pdata/plog"
-	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
-	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.opentelemetry.io/collector/pipeline"
+	"go.opentelemetry.io/collector/pipeline/xpipeline"
 	"go.opentelemetry.io/collector/receiver"
 	"go.uber.org/zap"
 	"solace.dev/go/messaging"
@@ -27,36 +32,86 @@ import (
 	"solace.dev/go/messaging/pkg/solace/resource"
 
 	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
-	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/mocks"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/brokerspan"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/dedup"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/shedding"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/telemetry"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/topic"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/wal"
 )
 
-// Receiver implements the Receiver for Logs and Traces
+// Receiver implements the Receiver for Logs, Traces, Metrics and Profiles
 type Receiver struct {
-	logsConsumer     consumer.Logs
-	tracesConsumer   consumer.Traces
-	settings         receiver.Settings
-	config           *solaceconfig.Config
-	logger           *zap.Logger
-	wg               sync.WaitGroup
-	messagingService interface{} // can be real SDK or mock
-	QueueConsumer    interface{} // stores the used QueueConsumer
+	logsConsumer       consumer.Logs
+	tracesConsumer     consumer.Traces
+	metricsConsumer    consumer.Metrics
+	profilesConsumer   xconsumer.Profiles // set by createProfilesReceiver
+	settings           receiver.Settings
+	config             *solaceconfig.Config
+	logger             *zap.Logger
+	wg                 sync.WaitGroup
+	messagingService   interface{}     // can be real SDK or solacetest fake
+	queues             []*queueBinding // consumed queues, bound on Start
+	queuesMu           sync.Mutex      // guards the consumers of queues and the connection against replays
+	replayMu           sync.Mutex      // serializes replays
+	telemetry          *telemetry.Telemetry
+	serviceConnected   bool                // whether the messaging service is connected
+	cancelConnect      context.CancelFunc  // stops background connect attempts
+	connectWg          sync.WaitGroup      // tracks the background connect loop
+	intakeMu           sync.RWMutex        // orders message intake against Shutdown
+	stopping           bool                // set once Shutdown stopped intake
+	inFlight           atomic.Int64        // messages currently being decoded or consumed
+	released           atomic.Int64        // messages released back to the broker
+	logsUnmarshaler    plog.Unmarshaler    // logs encoding extension, if configured
+	tracesUnmarshaler  ptrace.Unmarshaler  // traces encoding extension, if configured
+	metricsUnmarshaler pmetric.Unmarshaler // metrics encoding extension, if configured
+	topicTemplate      *topic.Template     // levels of the topic to add as resource attributes, if configured
+	dedupWindow        *dedup.Window       // keys of consumed messages, if deduplication is enabled
+	dedupClient        storage.Client      // persists the deduplication window, if configured
+	buffer             *wal.Log            // write-ahead buffer, if enabled
+	bufferClient       storage.Client      // holds the write-ahead buffer
+	cancelFeed         context.CancelFunc  // stops feeding the buffer to the pipeline
+	feedWg             sync.WaitGroup      // tracks the buffer feed loop
+	replayServer       *http.Server        // serves runtime replay requests, if configured
+	replayAddr         net.Addr            // address of the replay endpoint
+	shedder            *shedding.Shedder   // sheds low-priority messages under back-pressure, if enabled
 }
 
-// NewReceiver creates a new Receiver for Logs and Traces
+// defaultGracePeriod bounds queue consumer termination when Shutdown has no deadline
+const defaultGracePeriod = 10 * time.Second
+
+// NewReceiver creates a new Receiver for Logs, Traces and Metrics
 func NewReceiver(
 	settings receiver.Settings,
 	config *solaceconfig.Config,
 	logsConsumer consumer.Logs,
 	tracesConsumer consumer.Traces,
+	metricsConsumer consumer.Metrics,
 	opts ...interface{},
 ) (*Receiver, error) {
 	randNum := rand.Intn(1000000)
+	tel, err := telemetry.New(settings.TelemetrySettings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create receiver telemetry: %w", err)
+	}
 	receiver := &Receiver{
-		logsConsumer:   logsConsumer,
-		tracesConsumer: tracesConsumer,
-		settings:       settings,
-		config:         config,
-		logger:         settings.TelemetrySettings.Logger,
+		logsConsumer:    logsConsumer,
+		tracesConsumer:  tracesConsumer,
+		metricsConsumer: metricsConsumer,
+		settings:        settings,
+		config:          config,
+		logger:          settings.TelemetrySettings.Logger,
+		telemetry:       tel,
+	}
+	if template := config.ResourceAttributes.TopicTemplate; template != "" {
+		parsed, err := topic.ParseTemplate(template)
+		if err != nil {
+			return nil, err
+		}
+		receiver.topicTemplate = &parsed
+	}
+	if config.LoadShedding.Enabled {
+		receiver.shedder = newShedder(config.LoadShedding)
 	}
 	receiver.logger.Info("NewReceiver instance created",
 		zap.Time("created_at", time.Now()),
@@ -71,11 +126,107 @@ func NewReceiver(
 
 // Start starts the Receiver
 func (r *Receiver) Start(ctx context.Context, host component.Host) error {
+	if err := r.loadEncodings(host); err != nil {
+		return err
+	}
+	if err := r.loadDedup(ctx, host); err != nil {
+		return err
+	}
+	if err := r.openBuffer(ctx, host); err != nil {
+		return err
+	}
+	r.queues = r.bindQueues()
+	queues := make([]string, len(r.queues))
+	for i, q := range r.queues {
+		queues[i] = q.name
+	}
+	if err := r.startReplayEndpoint(ctx, host); err != nil {
+		return err
+	}
 	r.logger.Info("Starting Solace OTLP receiver",
 		zap.String("host", r.config.Host),
-		zap.String("queue", r.config.Queue))
+		zap.Strings("queues", queues),
+		zap.String("initial_connect", r.config.InitialConnect))
+
+	if r.config.InitialConnect == solaceconfig.InitialConnectBackground {
+		connectCtx, cancel := context.WithCancel(context.Background())
+		r.cancelConnect = cancel
+		r.connectWg.Add(1)
+		go func() {
+			defer r.connectWg.Done()
+			r.connectWithRetry(connectCtx, host)
+		}()
+		r.logger.Info("Solace OTLP receiver started; connecting in the background")
+		return nil
+	}
 
-	// MessagingService initialize (SDK or Mock)
+	err := r.connect()
+	r.telemetry.RecordConnectAttempt(ctx, err)
+	if err != nil {
+		return err
+	}
+	r.telemetry.RecordConnected(ctx, true)
+	r.logger.Info("Solace OTLP receiver started successfully!")
+	return nil
+}
+
+// connectWithRetry connects to the broker until it succeeds or ctx is cancelled
+func (r *Receiver) connectWithRetry(ctx context.Context, host component.Host) {
+	retry := r.config.ConnectRetry
+	interval := retry.InitialInterval
+	for attempt := 1; ; attempt++ {
+		err := r.connect()
+		if ctx.Err() != nil {
+			// Shutdown may have returned already, so tear down what the attempt connected
+			if err := r.disconnect(defaultGracePeriod); err != nil {
+				r.logger.Warn("Failed to disconnect after background connect was cancelled", zap.Error(err))
+			}
+			r.logger.Info("Background connect cancelled", zap.Int("attempts", attempt))
+			return
+		}
+		r.telemetry.RecordConnectAttempt(ctx, err)
+		if err == nil {
+			r.telemetry.RecordConnected(ctx, true)
+			componentstatus.ReportStatus(host, componentstatus.NewEvent(componentstatus.StatusOK))
+			r.logger.Info("Solace OTLP receiver connected", zap.Int("attempt", attempt))
+			return
+		}
+		r.telemetry.RecordConnected(ctx, false)
+		componentstatus.ReportStatus(host, componentstatus.NewRecoverableErrorEvent(err))
+		r.logger.Warn("Failed to connect to Solace, retrying",
+			zap.Int("attempt", attempt),
+			zap.Duration("retry_in", interval),
+			zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Background connect cancelled", zap.Int("attempts", attempt))
+			return
+		case <-time.After(interval):
+		}
+		interval = nextInterval(interval, retry)
+	}
+}
+
+// nextInterval grows the backoff interval up to the configured maximum
+func nextInterval(current time.Duration, retry solaceconfig.ConnectRetryConfig) time.Duration {
+	multiplier := retry.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+	next := time.Duration(float64(current) * multiplier)
+	if retry.MaxInterval > 0 && next > retry.MaxInterval {
+		next = retry.MaxInterval
+	}
+	return next
+}
+
+// connect builds the messaging service, connects it and starts a consumer per
+// queue. Queues already consumed by an earlier attempt are kept.
+func (r *Receiver) connect() error {
+	r.queuesMu.Lock()
+	defer r.queuesMu.Unlock()
+	// MessagingService initialize (SDK unless injected)
 	if r.messagingService == nil {
 		ms, err := messaging.NewMessagingServiceBuilder().
 			FromConfigurationProvider(config.ServicePropertyMap{
@@ -95,287 +246,346 @@ func (r *Receiver) Start(ctx context.Context, host component.Host) error {
 		r.messagingService = ms
 	}
 
-	var err error
-	type queueConsumerBuilderIface interface {
-		WithMessageAutoAcknowledgement() queueConsumerBuilderIface
-		WithMessageListener(func(message.InboundMessage)) queueConsumerBuilderIface
-		WithClientName(string) queueConsumerBuilderIface
-		Build(resource.Queue) (interface{ Start() error }, error)
-	}
-
 	switch ms := r.messagingService.(type) {
-	case interface {
-		Connect() error
-		CreateQueueConsumerBuilder() interface{}
-	}:
-		r.logger.Info("Using generic MessagingService interface")
-		err = ms.Connect()
-		if err != nil {
-			return fmt.Errorf("failed to connect to Solace: %w", err)
-		}
-		builderIface := ms.CreateQueueConsumerBuilder()
-		builder, ok := builderIface.(queueConsumerBuilderIface)
-		if !ok {
-			return fmt.Errorf("queue consumer builder does not implement required interface")
-		}
-		queueConsumer, err := builder.
-			WithMessageListener(r.HandleMessage).
-			WithClientName("otlp-receiver").
-			Build(*resource.QueueDurableExclusive(r.config.Queue))
-		if err != nil {
-			return fmt.Errorf("failed to create queue consumer: %w", err)
-		}
-		r.QueueConsumer = queueConsumer
-		err = queueConsumer.Start()
-		if err != nil {
-			return fmt.Errorf("failed to start queue consumer: %w", err)
-		}
-
-	case mocks.MessagingService:
-		r.logger.Info("Using Mock MessagingService")
-		err = ms.Connect()
-		if err != nil {
-			return fmt.Errorf("failed to connect to Solace (mock): %w", err)
-		}
-		queueConsumerBuilder := ms.CreateQueueConsumerBuilder()
-		queueConsumer, err := queueConsumerBuilder.
-			WithMessageListener(r.HandleMessage).
-			WithClientName("otlp-receiver-mock").
-			Build(*resource.QueueDurableExclusive(r.config.Queue))
-		if err != nil {
-			return fmt.Errorf("failed to create queue consumer (mock): %w", err)
-		}
-		if starter, ok := queueConsumer.(interface{ Start() error }); ok {
-			err = starter.Start()
-			if err != nil {
-				return fmt.Errorf("failed to start queue consumer (mock): %w", err)
-			}
-		}
-		r.QueueConsumer = queueConsumer
-
 	case solace.MessagingService:
 		r.logger.Info("Using real Solace SDK MessagingService")
-		err = ms.Connect()
-		if err != nil {
-			return fmt.Errorf("failed to connect to Solace (SDK): %w", err)
-		}
-		builder := ms.CreatePersistentMessageReceiverBuilder()
-		receiver, err := builder.Build(resource.QueueDurableExclusive(r.config.Queue))
-		if err != nil {
-			return fmt.Errorf("failed to build persistent message receiver (SDK): %w", err)
-		}
-		err = receiver.Start()
-		if err != nil {
-			return fmt.Errorf("failed to start persistent message receiver (SDK): %w", err)
+		if err := r.connectService(ms.Connect); err != nil {
+			return fmt.Errorf("failed to connect to Solace: %w", err)
 		}
-		if regErr := receiver.ReceiveAsync(r.HandleMessage); regErr != nil {
-			return fmt.Errorf("failed to register message handler: %w", regErr)
+		for _, q := range r.queues {
+			if q.consumer != nil {
+				continue
+			}
+			if err := r.consumeQueue(ms, q, r.startReplay(q.name)); err != nil {
+				return err
+			}
 		}
-		r.QueueConsumer = receiver
 
 	default:
 		return fmt.Errorf("unsupported messagingService type")
 	}
+	return nil
+}
 
-	r.logger.Info("Solace OTLP receiver started successfully!")
+// consumeQueue starts the persistent receiver of a queue, replaying its
+// messages if strategy is set
+func (r *Receiver) consumeQueue(ms solace.MessagingService, q *queueBinding, strategy *config.ReplayStrategy) error {
+	builder := ms.CreatePersistentMessageReceiverBuilder().
+		WithRequiredMessageOutcomeSupport(config.PersistentReceiverFailedOutcome, config.PersistentReceiverRejectedOutcome)
+	if r.config.AckMode == solaceconfig.AckModeAuto {
+		builder = builder.WithMessageAutoAcknowledgement()
+	}
+	if strategy != nil {
+		builder = builder.WithMessageReplay(*strategy)
+	}
+	if q.brokerSelector != "" {
+		builder = builder.WithMessageSelector(q.brokerSelector)
+	}
+	receiver, err := builder.Build(resource.QueueDurableExclusive(q.name))
+	if err != nil {
+		return fmt.Errorf("failed to build persistent message receiver (SDK) for queue %q: %w", q.name, err)
+	}
+	err = receiver.Start()
+	if err != nil {
+		return fmt.Errorf("failed to start persistent message receiver (SDK) for queue %q: %w", q.name, err)
+	}
+	q.consumer = receiver
+	if regErr := receiver.ReceiveAsync(func(msg message.InboundMessage) { r.handleMessage(q, msg) }); regErr != nil {
+		return fmt.Errorf("failed to register message handler: %w", regErr)
+	}
 	return nil
 }
 
-// Shutdown ends the Receiver
+// Shutdown ends the Receiver. It stops intake, waits for in-flight messages
+// within the deadline of ctx, releases undelivered messages back to the broker
+// and then disconnects.
 func (r *Receiver) Shutdown(ctx context.Context) error {
 	r.logger.Info("Shutting down Solace OTLP receiver")
-	if r.QueueConsumer != nil {
-		if terminator, ok := r.QueueConsumer.(interface{ Terminate(uint) error }); ok {
-			_ = terminator.Terminate(10)
-		}
+	replayErr := r.stopReplayEndpoint(ctx)
+	connectErr := r.stopConnect(ctx)
+
+	inFlight := r.stopIntake()
+	// A connect attempt still running holds queuesMu; connectWithRetry
+	// disconnects once it returns
+	if connectErr == nil {
+		r.pauseConsumers()
 	}
-	if r.messagingService != nil {
-		if disconnector, ok := r.messagingService.(interface{ Disconnect() error }); ok {
-			_ = disconnector.Disconnect()
-		}
+	drainErr := r.drain(ctx)
+	remaining := r.inFlight.Load()
+
+	var disconnectErr error
+	if connectErr == nil {
+		disconnectErr = r.disconnect(gracePeriod(ctx))
 	}
-	return nil
+	r.telemetry.RecordConnected(ctx, false)
+	bufferErr := r.closeBuffer(ctx)
+	dedupErr := r.closeDedup(ctx)
+
+	// Messages still in flight stay unsettled and are redelivered by the broker
+	released := r.released.Load()
+	drained := inFlight - remaining
+	r.telemetry.RecordShutdown(ctx, drained, released, remaining)
+	r.logger.Info("Solace OTLP receiver drained",
+		zap.Int64("drained", drained),
+		zap.Int64("released", released),
+		zap.Int64("unsettled", remaining))
+
+	return errors.Join(replayErr, connectErr, drainErr, disconnectErr, bufferErr, dedupErr)
 }
 
-// HandleMessage processes an incoming message
-func (r *Receiver) HandleMessage(msg message.InboundMessage) {
-	r.logger.Debug("HandleMessage called")
-	r.wg.Add(1)
-	defer r.wg.Done()
-
-	// Try first as base64-encoded OTLP Log to parse
-	payloadStr, ok := msg.GetPayloadAsString()
-	if !ok {
-		r.logger.Error("Failed to get message payload")
-		return
+// stopConnect cancels the background connect and waits for it until ctx is done
+func (r *Receiver) stopConnect(ctx context.Context) error {
+	if r.cancelConnect == nil {
+		return nil
+	}
+	r.cancelConnect()
+	done := make(chan struct{})
+	go func() {
+		r.connectWg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("background connect did not stop: %w", ctx.Err())
 	}
+}
 
-	// Try base64-decoding
-	payload, err := base64.StdEncoding.DecodeString(payloadStr)
-	if err == nil {
-		// Try to parse as OTLP Log
-		otlpLogs := plogotlp.NewExportRequest()
-		if err := otlpLogs.UnmarshalProto(payload); err == nil {
-			if err := r.logsConsumer.ConsumeLogs(context.Background(), otlpLogs.Logs()); err != nil {
-				r.logger.Error("Failed to consume logs", zap.Error(err))
-				return
+// pauseConsumers stops the queue consumers from delivering further messages
+func (r *Receiver) pauseConsumers() {
+	r.queuesMu.Lock()
+	defer r.queuesMu.Unlock()
+	for _, q := range r.queues {
+		if pauser, ok := q.consumer.(interface{ Pause() error }); ok {
+			if err := pauser.Pause(); err != nil {
+				r.logger.Warn("Failed to pause queue consumer", zap.String("queue", q.name), zap.Error(err))
 			}
-			acknowledgeMessage(r, msg)
-			return
 		}
+	}
+}
 
-		// Try to parse as OTLP Trace
-		otlpTraces := ptraceotlp.NewExportRequest()
-		if err := otlpTraces.UnmarshalProto(payload); err == nil {
-			if err := r.tracesConsumer.ConsumeTraces(context.Background(), otlpTraces.Traces()); err != nil {
-				r.logger.Error("Failed to consume traces", zap.Error(err))
-				return
-			}
-			acknowledgeMessage(r, msg)
-			return
-		}
+// stopIntake makes HandleMessage release new messages and returns the number in flight
+func (r *Receiver) stopIntake() int64 {
+	r.intakeMu.Lock()
+	defer r.intakeMu.Unlock()
+	r.stopping = true
+	return r.inFlight.Load()
+}
+
+// drain waits for in-flight messages until ctx is done
+func (r *Receiver) drain(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("in-flight messages not drained: %w", ctx.Err())
 	}
+}
 
-	// Try to parse as JSON Log
-	var logData struct {
-		TimeUnixNano         int64  `json:"time_unix_nano"`
-		ObservedTimeUnixNano int64  `json:"observed_time_unix_nano"`
-		SeverityNumber       int32  `json:"severity_number"`
-		SeverityText         string `json:"severity_text"`
-		Body                 string `json:"body"`
-		Attributes           []struct {
-			Key   string      `json:"key"`
-			Value interface{} `json:"value"`
-		} `json:"attributes"`
-		TraceID   string `json:"trace_id"`
-		SpanID    string `json:"span_id"`
-		EventName string `json:"event_name,omitempty"`
-	}
-
-	if err := json.Unmarshal([]byte(payloadStr), &logData); err == nil {
-		// Create OTLP log
-		otlpLogs := plogotlp.NewExportRequest()
-		logs := otlpLogs.Logs()
-		resourceLogs := logs.ResourceLogs().AppendEmpty()
-		scopeLogs := resourceLogs.ScopeLogs().AppendEmpty()
-		logRecord := scopeLogs.LogRecords().AppendEmpty()
-
-		// Set log data
-		logRecord.SetTimestamp(pcommon.Timestamp(logData.TimeUnixNano))
-		logRecord.SetObservedTimestamp(pcommon.Timestamp(logData.ObservedTimeUnixNano))
-		logRecord.SetSeverityNumber(plog.SeverityNumber(logData.SeverityNumber))
-		logRecord.SetSeverityText(logData.SeverityText)
-		logRecord.Body().SetStr(logData.Body)
-
-		// Set attributes
-		for _, attr := range logData.Attributes {
-			switch v := attr.Value.(type) {
-			case string:
-				logRecord.Attributes().PutStr(attr.Key, v)
-			case float64:
-				logRecord.Attributes().PutDouble(attr.Key, v)
-			case bool:
-				logRecord.Attributes().PutBool(attr.Key, v)
-			case int:
-				logRecord.Attributes().PutInt(attr.Key, int64(v))
-			}
-		}
+// beginMessage registers an in-flight message unless intake has stopped
+func (r *Receiver) beginMessage() bool {
+	r.intakeMu.RLock()
+	defer r.intakeMu.RUnlock()
+	if r.stopping {
+		return false
+	}
+	r.wg.Add(1)
+	r.inFlight.Add(1)
+	return true
+}
+
+// endMessage marks an in-flight message as done
+func (r *Receiver) endMessage() {
+	r.inFlight.Add(-1)
+	r.wg.Done()
+}
+
+// gracePeriod returns how long the queue consumer may take to terminate
+func gracePeriod(ctx context.Context) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return max(time.Until(deadline), 0)
+	}
+	return defaultGracePeriod
+}
+
+// connectService connects the messaging service unless an earlier attempt already did
+func (r *Receiver) connectService(connect func() error) error {
+	if r.serviceConnected {
+		return nil
+	}
+	if err := connect(); err != nil {
+		return err
+	}
+	r.serviceConnected = true
+	return nil
+}
 
-		// Set trace context if available
-		if logData.TraceID != "" {
-			traceID, err := hexStringToTraceID(logData.TraceID)
-			if err == nil {
-				logRecord.SetTraceID(traceID)
+// disconnect terminates the queue consumer and disconnects the messaging service
+func (r *Receiver) disconnect(grace time.Duration) error {
+	r.queuesMu.Lock()
+	defer r.queuesMu.Unlock()
+	var errs []error
+	for _, q := range r.queues {
+		if terminator, ok := q.consumer.(interface{ Terminate(time.Duration) error }); ok {
+			if err := terminator.Terminate(grace); err != nil {
+				errs = append(errs, fmt.Errorf("failed to terminate consumer of queue %q: %w", q.name, err))
 			}
 		}
-		if logData.SpanID != "" {
-			spanID, err := hexStringToSpanID(logData.SpanID)
-			if err == nil {
-				logRecord.SetSpanID(spanID)
+	}
+	if r.messagingService != nil && r.serviceConnected {
+		if disconnector, ok := r.messagingService.(interface{ Disconnect() error }); ok {
+			if err := disconnector.Disconnect(); err != nil {
+				errs = append(errs, fmt.Errorf("failed to disconnect messaging service: %w", err))
 			}
 		}
+		r.serviceConnected = false
+	}
+	return errors.Join(errs...)
+}
 
-		if err := r.logsConsumer.ConsumeLogs(context.Background(), logs); err != nil {
-			r.logger.Error("Failed to consume logs", zap.Error(err))
-			return
-		}
-		acknowledgeMessage(r, msg)
-		return
+// HandleMessage processes an incoming message of the first queue
+func (r *Receiver) HandleMessage(msg message.InboundMessage) {
+	q := &queueBinding{name: r.config.Queue}
+	if len(r.queues) > 0 {
+		q = r.queues[0]
 	}
+	r.handleMessage(q, msg)
+}
 
-	// If no Log, try to parse as JSON Trace
-	var traceData struct {
-		TraceID      string `json:"trace_id"`
-		SpanID       string `json:"span_id"`
-		ParentSpanID string `json:"parent_span_id"`
-		Name         string `json:"name"`
-		Kind         int    `json:"kind"`
-		StartTime    int64  `json:"start_time"`
-		EndTime      int64  `json:"end_time"`
-		Status       struct {
-			Code    int    `json:"code"`
-			Message string `json:"message"`
-		} `json:"status"`
+// handleMessage processes an incoming message of queue q
+func (r *Receiver) handleMessage(q *queueBinding, msg message.InboundMessage) {
+	r.logger.Debug("HandleMessage called", zap.String("queue", q.name))
+	if !r.beginMessage() {
+		r.releaseMessage(q, msg)
+		return
 	}
+	defer r.endMessage()
+	receivedAt := time.Now()
 
-	if err := json.Unmarshal([]byte(payloadStr), &traceData); err != nil {
-		r.logger.Error("Failed to unmarshal trace data", zap.Error(err))
+	if !r.selects(q, msg) {
+		r.logger.Debug("Dropping message not matching the selector", zap.String("queue", q.name))
+		r.telemetry.RecordFiltered(context.Background(), q.name)
+		acknowledgeMessage(r, q, msg)
+		return
+	}
+	if r.shed(q, msg) {
 		return
 	}
 
-	// Create OTLP trace
-	otlpTraces := ptraceotlp.NewExportRequest()
-	traces := otlpTraces.Traces()
-	resourceSpans := traces.ResourceSpans().AppendEmpty()
-	scopeSpans := resourceSpans.ScopeSpans().AppendEmpty()
-	span := scopeSpans.Spans().AppendEmpty()
+	dedupKey, dedupable := r.dedupKey(q, msg)
+	if dedupable && r.isDuplicate(msg, dedupKey) {
+		r.logger.Debug("Dropping duplicate message", zap.String("key", dedupKey))
+		r.telemetry.RecordDuplicate(context.Background(), q.name)
+		acknowledgeMessage(r, q, msg)
+		return
+	}
 
-	// Convert IDs
-	traceID, err := hexStringToTraceID(traceData.TraceID)
-	if err != nil {
-		r.logger.Error("Failed to convert trace ID", zap.Error(err))
+	payload, ok := messagePayload(msg)
+	if !ok {
+		r.logger.Error("Failed to get message payload")
+		r.settleFailure(q, msg, consumererror.NewPermanent(errors.New("message has no payload")))
 		return
 	}
-	spanID, err := hexStringToSpanID(traceData.SpanID)
+	data, err := r.decode(q, payload)
 	if err != nil {
-		r.logger.Error("Failed to convert span ID", zap.Error(err))
+		r.logger.Error("Failed to decode message payload", zap.Error(err))
+		r.settleFailure(q, msg, consumererror.NewPermanent(err))
 		return
 	}
-	parentSpanID, err := hexStringToSpanID(traceData.ParentSpanID)
-	if err != nil {
-		r.logger.Error("Failed to convert parent span ID", zap.Error(err))
+	r.logger.Debug("Decoded message payload", zap.String("format", data.format), zap.String("signal", data.signal.String()))
+	if err := r.checkExpected(q, data); err != nil {
+		r.logger.Error("Rejecting unexpected message", zap.Error(err))
+		r.settleFailure(q, msg, consumererror.NewPermanent(err))
 		return
 	}
+	if !r.serves(data.signal) {
+		r.logger.Error("Received a signal the receiver serves no pipeline for", zap.String("signal", data.signal.String()))
+		r.settleFailure(q, msg, consumererror.NewPermanent(fmt.Errorf("no %s pipeline", data.signal)))
+		return
+	}
+	overwrite := r.config.ResourceAttributes.Precedence == solaceconfig.PrecedenceTopic
+	putResourceAttributes(data, r.resourceAttributes(q, msg), overwrite)
+	r.correctTimestamps(msg, receivedAt, data)
+	for _, version := range data.legacy {
+		r.logger.Debug("Translated deprecated OTLP message", zap.String("version", string(version)))
+		r.telemetry.RecordLegacyOTLP(context.Background(), data.signal.String(), string(version))
+	}
 
-	// Set span data
-	span.SetTraceID(traceID)
-	span.SetSpanID(spanID)
-	span.SetParentSpanID(parentSpanID)
-	span.SetName(traceData.Name)
-	span.SetKind(ptrace.SpanKind(traceData.Kind))
-	span.SetStartTimestamp(pcommon.Timestamp(traceData.StartTime))
-	span.SetEndTimestamp(pcommon.Timestamp(traceData.EndTime))
-	span.Status().SetCode(ptrace.StatusCode(traceData.Status.Code))
-	span.Status().SetMessage(traceData.Status.Message)
-
-	if err := r.tracesConsumer.ConsumeTraces(context.Background(), traces); err != nil {
-		r.logger.Error("Failed to consume traces", zap.Error(err))
+	if data.signal == pipeline.SignalTraces {
+		r.appendBrokerSpan(data.traces, msg, receivedAt)
+	}
+	if r.buffer != nil {
+		err = r.bufferData(data)
+	} else {
+		err = r.consume(context.Background(), data)
+	}
+	if err != nil {
+		r.logger.Error("Failed to consume message", zap.String("signal", data.signal.String()), zap.Error(err))
+		r.noteRefusal(err)
+		r.settleFailure(q, msg, err)
 		return
 	}
-	acknowledgeMessage(r, msg)
+	if data.signal == pipeline.SignalLogs {
+		r.emitBrokerSpan(msg, receivedAt, data.logs)
+	}
+	if dedupable {
+		r.rememberMessage(dedupKey)
+	}
+	acknowledgeMessage(r, q, msg)
 }
 
-// Helper functions
-func hexStringToTraceID(s string) (pcommon.TraceID, error) {
-	var traceID pcommon.TraceID
-	_, err := hex.Decode(traceID[:], []byte(s))
-	return traceID, err
+// consume sends decoded data to the consumer of its signal
+func (r *Receiver) consume(ctx context.Context, data decoded) error {
+	switch data.signal {
+	case pipeline.SignalLogs:
+		return r.logsConsumer.ConsumeLogs(ctx, data.logs)
+	case pipeline.SignalTraces:
+		return r.tracesConsumer.ConsumeTraces(ctx, data.traces)
+	case pipeline.SignalMetrics:
+		return r.metricsConsumer.ConsumeMetrics(ctx, data.metrics)
+	case xpipeline.SignalProfiles:
+		return r.profilesConsumer.ConsumeProfiles(ctx, data.profiles)
+	}
+	return nil
 }
 
-func hexStringToSpanID(s string) (pcommon.SpanID, error) {
-	var spanID pcommon.SpanID
-	_, err := hex.Decode(spanID[:], []byte(s))
-	return spanID, err
+// appendBrokerSpan adds the synthesized broker-hop span to decoded traces
+func (r *Receiver) appendBrokerSpan(traces ptrace.Traces, msg message.InboundMessage, receivedAt time.Time) {
+	if !r.config.BrokerSpans.Enabled {
+		return
+	}
+	parent, ok := brokerspan.ParentFromProperties(msg)
+	if !ok {
+		parent, _ = brokerspan.ParentFromTraces(traces)
+	}
+	if !brokerspan.Append(traces, msg, receivedAt, parent) {
+		r.logger.Debug("Message has no sender timestamp; broker span skipped")
+	}
+}
+
+// emitBrokerSpan sends the synthesized broker-hop span of a log message to the traces consumer
+func (r *Receiver) emitBrokerSpan(msg message.InboundMessage, receivedAt time.Time, logs plog.Logs) {
+	if !r.config.BrokerSpans.Enabled || r.tracesConsumer == nil {
+		return
+	}
+	parent, ok := brokerspan.ParentFromProperties(msg)
+	if !ok {
+		parent, _ = brokerspan.ParentFromLogs(logs)
+	}
+	traces := ptrace.NewTraces()
+	if !brokerspan.Append(traces, msg, receivedAt, parent) {
+		r.logger.Debug("Message has no sender timestamp; broker span skipped")
+		return
+	}
+	if err := r.tracesConsumer.ConsumeTraces(context.Background(), traces); err != nil {
+		r.logger.Error("Failed to consume broker span", zap.Error(err))
+	}
 }
 
 func getTrustStorePath() string {
@@ -385,10 +595,51 @@ func getTrustStorePath() string {
 	return "truststore"
 }
 
-func acknowledgeMessage(r *Receiver, msg message.InboundMessage) {
+// settleFailure settles a message that could not be decoded or consumed. A
+// permanent error rejects it; any other error makes the broker redeliver it.
+func (r *Receiver) settleFailure(q *queueBinding, msg message.InboundMessage, err error) {
+	if r.config.AckMode == solaceconfig.AckModeAuto {
+		r.logger.Debug("Message was acknowledged on delivery and is dropped")
+		return
+	}
+	outcome := config.PersistentReceiverFailedOutcome
+	if consumererror.IsPermanent(err) {
+		outcome = config.PersistentReceiverRejectedOutcome
+	}
+	settler, ok := q.consumer.(interface {
+		Settle(message.InboundMessage, config.MessageSettlementOutcome) error
+	})
+	if !ok {
+		r.logger.Debug("Message left unsettled; the broker redelivers it after a reconnect")
+		return
+	}
+	if err := settler.Settle(msg, outcome); err != nil {
+		r.logger.Warn("Failed to settle message", zap.String("outcome", string(outcome)), zap.Error(err))
+	}
+}
+
+// releaseMessage settles msg as FAILED so that the broker redelivers it
+func (r *Receiver) releaseMessage(q *queueBinding, msg message.InboundMessage) {
+	r.released.Add(1)
+	settler, ok := q.consumer.(interface {
+		Settle(message.InboundMessage, config.MessageSettlementOutcome) error
+	})
+	if !ok {
+		r.logger.Debug("Message left unsettled; the broker redelivers it")
+		return
+	}
+	if err := settler.Settle(msg, config.PersistentReceiverFailedOutcome); err != nil {
+		r.logger.Warn("Failed to release message", zap.Error(err))
+	}
+}
+
+func acknowledgeMessage(r *Receiver, q *queueBinding, msg message.InboundMessage) {
 	r.logger.Debug("acknowledgeMessage called")
-	r.logger.Debug("Trying to acknowledge message", zap.String("queueConsumerType", fmt.Sprintf("%T", r.QueueConsumer)))
-	if receiver, ok := r.QueueConsumer.(interface {
+	if r.config.AckMode == solaceconfig.AckModeAuto {
+		return
+	}
+	r.logger.Debug("Trying to acknowledge message", zap.String("queueConsumerType", fmt.Sprintf("%T", q.consumer)))
+	if receiver, ok := q.consumer.(interface {
 		Ack(message.InboundMessage) error
 	}); ok {
 		err := receiver.Ack(msg)
@@ -398,6 +649,6 @@ func acknowledgeMessage(r *Receiver, msg message.InboundMessage) {
 			r.logger.Debug("Message acknowledged successfully")
 		}
 	} else {
-		r.logger.Warn("QueueConsumer does not implement Ack interface; message not acknowledged", zap.String("actualType", fmt.Sprintf("%T", r.QueueConsumer)))
+		r.logger.Warn("QueueConsumer does not implement Ack interface; message not acknowledged", zap.String("actualType", fmt.Sprintf("%T", q.consumer)))
 	}
 }
@@ -0,0 +1,265 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/solacetest"
+)
+
+const testQueue = "telemetry"
+
+func newTestConfig(initialConnect string) *solaceconfig.Config {
+	cfg := createDefaultConfig().(*solaceconfig.Config)
+	cfg.Queue = testQueue
+	cfg.InitialConnect = initialConnect
+	cfg.ConnectRetry.InitialInterval = time.Millisecond
+	cfg.ConnectRetry.MaxInterval = 5 * time.Millisecond
+	return cfg
+}
+
+func newTestBroker() *solacetest.Broker {
+	broker := solacetest.NewBroker()
+	broker.CreateQueue(testQueue, "otel/>")
+	return broker
+}
+
+func TestStart_BlockFailsOnUnreachableBroker(t *testing.T) {
+	broker := newTestBroker()
+	broker.SetUnreachable(true)
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), newTestConfig(solaceconfig.InitialConnectBlock),
+		consumertest.NewNop(), consumertest.NewNop(), nil, broker.NewMessagingService())
+	require.NoError(t, err)
+
+	assert.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
+}
+
+func TestStart_BackgroundConnectsWithRetries(t *testing.T) {
+	broker := newTestBroker()
+	broker.SetUnreachable(true)
+	service := broker.NewMessagingService()
+	sink := new(consumertest.LogsSink)
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), newTestConfig(solaceconfig.InitialConnectBackground),
+		sink, consumertest.NewNop(), nil, service)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool { return service.ConnectAttempts() >= 3 }, time.Second, time.Millisecond)
+	assert.False(t, service.IsConnected())
+
+	broker.SetUnreachable(false)
+	broker.Publish("otel/logs", []byte(`{"body":"hello"}`))
+	assert.Eventually(t, func() bool { return sink.LogRecordCount() == 1 }, time.Second, time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
+
+func TestShutdown_CancelsBackgroundConnect(t *testing.T) {
+	broker := newTestBroker()
+	broker.SetUnreachable(true)
+	service := broker.NewMessagingService()
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), newTestConfig(solaceconfig.InitialConnectBackground),
+		consumertest.NewNop(), consumertest.NewNop(), nil, service)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool { return service.ConnectAttempts() > 2 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, r.Shutdown(ctx))
+
+	attempts := service.ConnectAttempts()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, attempts, service.ConnectAttempts())
+	assert.False(t, service.IsConnected())
+}
+
+// blockingService holds Connect until release is closed
+type blockingService struct {
+	*solacetest.MessagingService
+	connecting chan struct{}
+	release    chan struct{}
+}
+
+func (s *blockingService) Connect() error {
+	close(s.connecting)
+	<-s.release
+	return s.MessagingService.Connect()
+}
+
+func TestShutdown_TearsDownLateBackgroundConnect(t *testing.T) {
+	broker := newTestBroker()
+	service := &blockingService{
+		MessagingService: broker.NewMessagingService(),
+		connecting:       make(chan struct{}),
+		release:          make(chan struct{}),
+	}
+	sink := new(consumertest.LogsSink)
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), newTestConfig(solaceconfig.InitialConnectBackground),
+		sink, consumertest.NewNop(), nil, service)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	<-service.connecting
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorContains(t, r.Shutdown(ctx), "background connect did not stop")
+	assert.True(t, r.isStopping())
+
+	close(service.release)
+	r.connectWg.Wait()
+	assert.False(t, service.IsConnected())
+	broker.Publish("otel/logs", []byte(`{"body":"after shutdown"}`))
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 0, sink.LogRecordCount())
+	assert.Equal(t, 1, broker.Pending(testQueue))
+}
+
+func TestShutdown_DrainsInFlightMessages(t *testing.T) {
+	gate := make(chan struct{})
+	sink := new(consumertest.LogsSink)
+	logs, err := consumer.NewLogs(func(ctx context.Context, ld plog.Logs) error {
+		<-gate
+		return sink.ConsumeLogs(ctx, ld)
+	})
+	require.NoError(t, err)
+
+	broker := newTestBroker()
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), newTestConfig(solaceconfig.InitialConnectBlock),
+		logs, consumertest.NewNop(), nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	broker.Publish("otel/logs", []byte(`{"body":"in flight"}`))
+	broker.Publish("otel/logs", []byte(`{"body":"queued"}`))
+	require.Eventually(t, func() bool { return r.inFlight.Load() == 1 }, time.Second, time.Millisecond)
+
+	shutdownErr := make(chan error, 1)
+	go func() {
+		shutdownErr <- r.Shutdown(context.Background())
+	}()
+	require.Eventually(t, func() bool {
+		r.intakeMu.RLock()
+		defer r.intakeMu.RUnlock()
+		return r.stopping
+	}, time.Second, time.Millisecond)
+
+	close(gate)
+	require.NoError(t, <-shutdownErr)
+	assert.Equal(t, 1, sink.LogRecordCount())
+	assert.Equal(t, 1, broker.Acked(testQueue))
+	assert.Equal(t, 1, broker.Pending(testQueue), "queued message stays on the broker")
+
+	r.HandleMessage(solacetest.NewMessage("otel/logs", []byte(`{"body":"late"}`)))
+	assert.Equal(t, int64(1), r.released.Load())
+	assert.Equal(t, 1, sink.LogRecordCount())
+}
+
+func TestShutdown_ReportsUnsettledMessages(t *testing.T) {
+	gate := make(chan struct{})
+	t.Cleanup(func() { close(gate) })
+	logs, err := consumer.NewLogs(func(context.Context, plog.Logs) error {
+		<-gate
+		return nil
+	})
+	require.NoError(t, err)
+
+	tel := componenttest.NewTelemetry()
+	t.Cleanup(func() { require.NoError(t, tel.Shutdown(context.Background())) })
+	settings := receivertest.NewNopSettings(typeStr)
+	settings.TelemetrySettings = tel.NewTelemetrySettings()
+	broker := newTestBroker()
+	r, err := NewReceiver(settings, newTestConfig(solaceconfig.InitialConnectBlock), logs, nil, nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	broker.Publish("otel/logs", []byte(`{"body":"in flight"}`))
+	require.Eventually(t, func() bool { return r.inFlight.Load() == 1 }, time.Second, time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.Error(t, r.Shutdown(ctx))
+
+	for name, want := range map[string]int64{
+		"otelcol_receiver_solaceotlp_shutdown_drained_messages":   0,
+		"otelcol_receiver_solaceotlp_shutdown_released_messages":  0,
+		"otelcol_receiver_solaceotlp_shutdown_unsettled_messages": 1,
+	} {
+		got, err := tel.GetMetric(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, got.Data.(metricdata.Sum[int64]).DataPoints[0].Value, name)
+	}
+}
+
+func TestHandleMessage_Metrics(t *testing.T) {
+	broker := newTestBroker()
+	sink := new(consumertest.MetricsSink)
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), newTestConfig(solaceconfig.InitialConnectBlock),
+		nil, nil, sink, broker.NewMessagingService())
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+	broker.Publish("otel/metrics", []byte(`{"name": "tank.level", "type": "gauge", "data_points": [{"value": 42.5}]}`))
+	broker.Publish("otel/metrics", []byte(`{"name": "tank.level", "type": "gauge", "data_points": []}`))
+	require.Eventually(t, func() bool {
+		return broker.Acked(testQueue) == 1 && len(broker.Rejected(testQueue)) == 1
+	}, time.Second, time.Millisecond)
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, 42.5, sink.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0).DoubleValue())
+}
+
+func TestHandleMessage_RejectsMetricsWithoutPipeline(t *testing.T) {
+	broker := newTestBroker()
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), newTestConfig(solaceconfig.InitialConnectBlock),
+		consumertest.NewNop(), consumertest.NewNop(), nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+	broker.Publish("otel/metrics", []byte(`{"name": "tank.level", "type": "gauge", "data_points": [{"value": 1}]}`))
+	require.Eventually(t, func() bool { return len(broker.Rejected(testQueue)) == 1 }, time.Second, time.Millisecond)
+}
+
+func TestHandleMessage_LegacyOTLP(t *testing.T) {
+	// An OTLP v0.15 traces request with instrumentation_library_spans (field 1000)
+	span := protowire.AppendString(protowire.AppendTag(nil, 5, protowire.BytesType), "legacy")
+	library := protowire.AppendBytes(protowire.AppendTag(nil, 2, protowire.BytesType), span)
+	resource := protowire.AppendBytes(protowire.AppendTag(nil, 1000, protowire.BytesType), library)
+	payload := protowire.AppendBytes(protowire.AppendTag(nil, 1, protowire.BytesType), resource)
+
+	tel := componenttest.NewTelemetry()
+	t.Cleanup(func() { require.NoError(t, tel.Shutdown(context.Background())) })
+	settings := receivertest.NewNopSettings(typeStr)
+	settings.TelemetrySettings = tel.NewTelemetrySettings()
+	broker := newTestBroker()
+	sink := new(consumertest.TracesSink)
+	r, err := NewReceiver(settings, newTestConfig(solaceconfig.InitialConnectBlock), nil, sink, nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+	broker.Publish("otel/traces", payload)
+	require.Eventually(t, func() bool { return broker.Acked(testQueue) == 1 }, time.Second, time.Millisecond)
+	require.Len(t, sink.AllTraces(), 1)
+	assert.Equal(t, "legacy", sink.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+
+	got, err := tel.GetMetric("otelcol_receiver_solaceotlp_legacy_otlp_messages")
+	require.NoError(t, err)
+	sum := got.Data.(metricdata.Sum[int64])
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+	assert.Equal(t, attribute.NewSet(attribute.String("signal", "traces"), attribute.String("version", "v0.15-v0.18")), sum.DataPoints[0].Attributes)
+}
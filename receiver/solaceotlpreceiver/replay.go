@@ -0,0 +1,195 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+	"solace.dev/go/messaging/pkg/solace"
+	"solace.dev/go/messaging/pkg/solace/config"
+
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/replay"
+)
+
+// replayGracePeriod bounds the termination of a queue consumer before its replay
+const replayGracePeriod = 10 * time.Second
+
+var (
+	errInvalidReplay = errors.New("invalid replay")
+	errUnknownQueue  = errors.New("unknown queue")
+	errNotConnected  = errors.New("not connected to the broker")
+)
+
+// startReplay returns the configured replay strategy of a queue on start
+func (r *Receiver) startReplay(name string) *config.ReplayStrategy {
+	cfg := r.config.Replay
+	if cfg.From == "" || (len(cfg.Queues) > 0 && !slices.Contains(cfg.Queues, name)) {
+		return nil
+	}
+	strategy, err := replay.ParseStrategy(cfg.From)
+	if err != nil {
+		// Validate rejects such configurations
+		r.logger.Error("Ignoring invalid replay", zap.Error(err))
+		return nil
+	}
+	r.logger.Warn("Replaying queue on start", zap.String("queue", name), zap.String("from", cfg.From))
+	return &strategy
+}
+
+// Replay restarts the consumers of queues with a replay of their messages
+// from the broker's replay log. All consumed queues are replayed if queues
+// is empty. It returns the replayed queues.
+func (r *Receiver) Replay(from string, queues []string) ([]string, error) {
+	strategy, err := replay.ParseStrategy(from)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidReplay, err)
+	}
+	r.replayMu.Lock()
+	defer r.replayMu.Unlock()
+	targets, err := r.replayTargets(queues)
+	if err != nil {
+		return nil, err
+	}
+
+	var replayed []string
+	for _, q := range targets {
+		// Terminating waits for messages in flight, so Shutdown is not held up by it
+		if terminator, ok := q.consumer.(interface{ Terminate(time.Duration) error }); ok {
+			if err := terminator.Terminate(replayGracePeriod); err != nil {
+				r.logger.Warn("Failed to terminate queue consumer before replay", zap.String("queue", q.name), zap.Error(err))
+			}
+		}
+		// Messages of the terminated consumer still in flight settle with it
+		if err := r.replayQueue(q, &strategy); err != nil {
+			return replayed, err
+		}
+		r.logger.Info("Replaying queue", zap.String("queue", q.name), zap.String("from", from))
+		replayed = append(replayed, q.name)
+	}
+	return replayed, nil
+}
+
+// replayTargets returns the bindings of queues, or of all consumed queues if
+// queues is empty
+func (r *Receiver) replayTargets(queues []string) ([]*queueBinding, error) {
+	r.queuesMu.Lock()
+	defer r.queuesMu.Unlock()
+	if _, ok := r.messagingService.(solace.MessagingService); !ok || !r.serviceConnected || r.isStopping() {
+		return nil, errNotConnected
+	}
+	for _, name := range queues {
+		if !slices.ContainsFunc(r.queues, func(q *queueBinding) bool { return q.name == name }) {
+			return nil, fmt.Errorf("%w %q", errUnknownQueue, name)
+		}
+	}
+	var targets []*queueBinding
+	for _, q := range r.queues {
+		if len(queues) == 0 || slices.Contains(queues, q.name) {
+			targets = append(targets, q)
+		}
+	}
+	return targets, nil
+}
+
+// replayQueue replaces the terminated consumer of q with one that replays
+// from strategy. If the replay fails to start, the queue is consumed without
+// replay again and the error is returned.
+func (r *Receiver) replayQueue(q *queueBinding, strategy *config.ReplayStrategy) error {
+	r.queuesMu.Lock()
+	defer r.queuesMu.Unlock()
+	ms, ok := r.messagingService.(solace.MessagingService)
+	i := slices.Index(r.queues, q)
+	if !ok || !r.serviceConnected || r.isStopping() || i < 0 {
+		return errNotConnected
+	}
+	next := &queueBinding{
+		name:           q.name,
+		signals:        q.signals,
+		strict:         q.strict,
+		brokerSelector: q.brokerSelector,
+		clientSelector: q.clientSelector,
+	}
+	r.queues[i] = next
+	err := r.consumeQueue(ms, next, strategy)
+	if err == nil {
+		return nil
+	}
+	r.logger.Error("Failed to replay queue; consuming it without replay", zap.String("queue", q.name), zap.Error(err))
+	if terminator, ok := next.consumer.(interface{ Terminate(time.Duration) error }); ok {
+		_ = terminator.Terminate(0)
+	}
+	if resumeErr := r.consumeQueue(ms, next, nil); resumeErr != nil {
+		return errors.Join(err, resumeErr)
+	}
+	return err
+}
+
+// isStopping reports whether Shutdown stopped intake
+func (r *Receiver) isStopping() bool {
+	r.intakeMu.RLock()
+	defer r.intakeMu.RUnlock()
+	return r.stopping
+}
+
+// startReplayEndpoint serves runtime replay requests on the configured HTTP server
+func (r *Receiver) startReplayEndpoint(ctx context.Context, host component.Host) error {
+	cfg := r.config.Replay.HTTP
+	if cfg == nil {
+		return nil
+	}
+	listener, err := cfg.ToListener(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to listen on replay endpoint %q: %w", cfg.Endpoint, err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /replay", r.handleReplay)
+	server, err := cfg.ToServer(ctx, host, r.settings.TelemetrySettings, mux)
+	if err != nil {
+		_ = listener.Close()
+		return fmt.Errorf("failed to create replay endpoint: %w", err)
+	}
+	r.replayServer, r.replayAddr = server, listener.Addr()
+	go func() {
+		if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			r.logger.Error("Replay endpoint failed", zap.Error(err))
+		}
+	}()
+	r.logger.Info("Serving replay requests", zap.Stringer("endpoint", r.replayAddr))
+	return nil
+}
+
+// stopReplayEndpoint stops serving replay requests
+func (r *Receiver) stopReplayEndpoint(ctx context.Context) error {
+	if r.replayServer == nil {
+		return nil
+	}
+	err := r.replayServer.Shutdown(ctx)
+	r.replayServer = nil
+	return err
+}
+
+// handleReplay replays the queues of the query parameter "queue", or all
+// queues, from the query parameter "from"
+func (r *Receiver) handleReplay(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	replayed, err := r.Replay(query.Get("from"), query["queue"])
+	switch {
+	case errors.Is(err, errInvalidReplay):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, errUnknownQueue):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, errNotConnected):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	case err != nil:
+		r.logger.Error("Replay failed", zap.Strings("replayed", replayed), zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		fmt.Fprintf(w, "replaying %s\n", strings.Join(replayed, ", "))
+	}
+}
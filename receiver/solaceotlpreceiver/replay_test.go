@@ -0,0 +1,265 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/solacetest"
+)
+
+// logBodies returns the body of the first log record of each batch in sink
+func logBodies(sink *consumertest.LogsSink) []string {
+	var bodies []string
+	for _, ld := range sink.AllLogs() {
+		bodies = append(bodies, ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+	}
+	return bodies
+}
+
+// consumeLogs consumes log messages with bodies from broker with a receiver
+// it shuts down afterwards and returns the time after the first one was spooled
+func consumeLogs(t *testing.T, broker *solacetest.Broker, bodies ...string) time.Time {
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), newTestConfig(solaceconfig.InitialConnectBlock),
+		consumertest.NewNop(), nil, nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+	var afterFirst time.Time
+	for i, body := range bodies {
+		broker.Publish("otel/logs", []byte(`{"body": "`+body+`"}`))
+		if i == 0 {
+			time.Sleep(time.Millisecond)
+			afterFirst = time.Now()
+			time.Sleep(time.Millisecond)
+		}
+	}
+	require.Eventually(t, func() bool { return broker.Acked(testQueue) == len(bodies) }, time.Second, time.Millisecond)
+	return afterFirst
+}
+
+func TestReplay_OnStart(t *testing.T) {
+	broker := newTestBroker()
+	afterFirst := consumeLogs(t, broker, "first", "second")
+
+	for from, want := range map[string][]string{
+		"all":                               {"first", "second"},
+		afterFirst.Format(time.RFC3339Nano): {"second"},
+	} {
+		cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+		cfg.Replay.From = from
+		sink := new(consumertest.LogsSink)
+		r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, sink, nil, nil, broker.NewMessagingService())
+		require.NoError(t, err)
+		require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+		require.Eventually(t, func() bool { return sink.LogRecordCount() == len(want) }, time.Second, time.Millisecond)
+		assert.Equal(t, want, logBodies(sink), from)
+		require.NoError(t, r.Shutdown(context.Background()))
+	}
+}
+
+func TestReplay_OnlyConfiguredQueues(t *testing.T) {
+	broker := newTestBroker()
+	consumeLogs(t, broker, "first")
+	cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+	cfg.Replay.From = "all"
+	cfg.Replay.Queues = []string{"other"}
+	sink := new(consumertest.LogsSink)
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, sink, nil, nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	startReceiver(t, r)
+	broker.Publish("otel/logs", []byte(testLogsPayload))
+	require.Eventually(t, func() bool { return broker.Acked(testQueue) == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, 1, sink.LogRecordCount())
+}
+
+func TestReplay_Endpoint(t *testing.T) {
+	broker := newTestBroker()
+	cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+	cfg.Replay.HTTP = &confighttp.ServerConfig{Endpoint: "localhost:0"}
+	sink := new(consumertest.LogsSink)
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, sink, nil, nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	startReceiver(t, r)
+	broker.Publish("otel/logs", []byte(`{"body": "first"}`))
+	broker.Publish("otel/logs", []byte(`{"body": "second"}`))
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 2 }, time.Second, time.Millisecond)
+
+	replay := func(method, query string) (int, string) {
+		req, err := http.NewRequestWithContext(context.Background(), method, "http://"+r.replayAddr.String()+"/replay?"+query, nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+	status, body := replay(http.MethodPost, "from=all&queue="+testQueue)
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, "replaying "+testQueue+"\n", body)
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 4 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"first", "second", "first", "second"}, logBodies(sink))
+
+	broker.Publish("otel/logs", []byte(`{"body": "third"}`))
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 5 }, time.Second, time.Millisecond,
+		"the replayed queue is consumed as before")
+
+	for query, want := range map[string]int{
+		"from=yesterday":         http.StatusBadRequest,
+		"from=all&queue=unknown": http.StatusNotFound,
+	} {
+		status, _ := replay(http.MethodPost, query)
+		assert.Equal(t, want, status, query)
+	}
+	status, _ = replay(http.MethodGet, "from=all")
+	assert.Equal(t, http.StatusMethodNotAllowed, status)
+}
+
+// tokenAuth accepts requests that carry its bearer token
+type tokenAuth struct {
+	component.StartFunc
+	component.ShutdownFunc
+	token string
+}
+
+func (a tokenAuth) Authenticate(ctx context.Context, headers map[string][]string) (context.Context, error) {
+	if http.Header(headers).Get("Authorization") != "Bearer "+a.token {
+		return ctx, errors.New("invalid token")
+	}
+	return ctx, nil
+}
+
+func TestReplay_EndpointAuth(t *testing.T) {
+	authID := component.MustNewID("bearertokenauth")
+	cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+	cfg.Replay.HTTP = &confighttp.ServerConfig{
+		Endpoint: "localhost:0",
+		Auth:     &confighttp.AuthConfig{Config: configauth.Config{AuthenticatorID: authID}},
+	}
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, consumertest.NewNop(), nil, nil, newTestBroker().NewMessagingService())
+	require.NoError(t, err)
+	host := extensionHost{extensions: map[component.ID]component.Component{authID: tokenAuth{token: "secret"}}}
+	require.NoError(t, r.Start(context.Background(), host))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+
+	for token, want := range map[string]int{
+		"":       http.StatusUnauthorized,
+		"wrong":  http.StatusUnauthorized,
+		"secret": http.StatusOK,
+	} {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "http://"+r.replayAddr.String()+"/replay?from=all", nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, want, resp.StatusCode, token)
+	}
+}
+
+func TestReplay_EndpointMissingAuthenticator(t *testing.T) {
+	cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+	cfg.Replay.HTTP = &confighttp.ServerConfig{
+		Endpoint: "localhost:0",
+		Auth:     &confighttp.AuthConfig{Config: configauth.Config{AuthenticatorID: component.MustNewID("missing")}},
+	}
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, consumertest.NewNop(), nil, nil, newTestBroker().NewMessagingService())
+	require.NoError(t, err)
+	assert.ErrorContains(t, r.Start(context.Background(), componenttest.NewNopHost()), "failed to create replay endpoint")
+	require.NoError(t, r.Shutdown(context.Background()))
+}
+
+func TestReplay_FailureResumesQueue(t *testing.T) {
+	broker := newTestBroker()
+	sink := new(consumertest.LogsSink)
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), newTestConfig(solaceconfig.InitialConnectBlock),
+		sink, nil, nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	startReceiver(t, r)
+	broker.Publish("otel/logs", []byte(`{"body": "first"}`))
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 1 }, time.Second, time.Millisecond)
+
+	// The broker has no message of this ID in its replay log
+	replayed, err := r.Replay("rmid1:1d2c3-5ce5e7f0c7a-00000000-00000002", nil)
+	require.Error(t, err)
+	assert.Empty(t, replayed)
+
+	broker.Publish("otel/logs", []byte(`{"body": "second"}`))
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 2 }, time.Second, time.Millisecond,
+		"the queue is consumed without replay after a failed replay")
+	assert.Equal(t, []string{"first", "second"}, logBodies(sink))
+}
+
+func TestReplay_NotConnected(t *testing.T) {
+	cfg := newTestConfig(solaceconfig.InitialConnectBackground)
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, consumertest.NewNop(), nil, nil, nil)
+	require.NoError(t, err)
+	_, err = r.Replay("all", nil)
+	assert.ErrorIs(t, err, errNotConnected)
+}
+
+func TestConfigValidate_Replay(t *testing.T) {
+	for name, tc := range map[string]struct {
+		replay  solaceconfig.ReplayConfig
+		wantErr bool
+	}{
+		"all":                 {replay: solaceconfig.ReplayConfig{From: "all", Queues: []string{"a"}}},
+		"time":                {replay: solaceconfig.ReplayConfig{From: "2025-06-02T09:30:00Z"}},
+		"id":                  {replay: solaceconfig.ReplayConfig{From: "rmid1:0f4a0-5ce5e7f0c7a-00000000-00000002"}},
+		"endpoint only":       {replay: solaceconfig.ReplayConfig{HTTP: &confighttp.ServerConfig{Endpoint: "localhost:13135"}}},
+		"invalid start":       {replay: solaceconfig.ReplayConfig{From: "yesterday"}, wantErr: true},
+		"queues without from": {replay: solaceconfig.ReplayConfig{Queues: []string{"a"}}, wantErr: true},
+		"empty queue":         {replay: solaceconfig.ReplayConfig{From: "all", Queues: []string{""}}, wantErr: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+			cfg.Replay = tc.replay
+			err := cfg.Validate()
+			if tc.wantErr {
+				assert.ErrorContains(t, err, "replay: ")
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestConfigUnmarshal_ReplayHTTP(t *testing.T) {
+	for name, tc := range map[string]struct {
+		replay       map[string]any
+		wantEndpoint string
+	}{
+		"unset":    {replay: map[string]any{"from": "all"}},
+		"default":  {replay: map[string]any{"http": nil}, wantEndpoint: solaceconfig.DefaultReplayEndpoint},
+		"endpoint": {replay: map[string]any{"http": map[string]any{"endpoint": "0.0.0.0:13135"}}, wantEndpoint: "0.0.0.0:13135"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*solaceconfig.Config)
+			require.NoError(t, confmap.NewFromStringMap(map[string]any{"replay": tc.replay}).Unmarshal(cfg))
+			if tc.wantEndpoint == "" {
+				assert.Nil(t, cfg.Replay.HTTP)
+				return
+			}
+			require.NotNil(t, cfg.Replay.HTTP)
+			assert.Equal(t, tc.wantEndpoint, cfg.Replay.HTTP.Endpoint)
+			assert.Nil(t, cfg.Replay.HTTP.TLSSetting)
+		})
+	}
+}
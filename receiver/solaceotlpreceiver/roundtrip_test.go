@@ -0,0 +1,189 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/testdata"
+)
+
+const roundTrips = 50
+
+// startRoundTripReceiver connects a receiver with both sinks to a fake broker
+func startRoundTripReceiver(t *testing.T) (*consumertest.LogsSink, *consumertest.TracesSink, func(topic string, payload []byte)) {
+	broker := newTestBroker()
+	logs, traces := new(consumertest.LogsSink), new(consumertest.TracesSink)
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), newTestConfig(solaceconfig.InitialConnectBlock),
+		logs, traces, nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, r.Shutdown(context.Background()))
+		assert.Equal(t, 0, broker.Pending(testQueue)+broker.Unacked(testQueue), "every message is acknowledged")
+	})
+	return logs, traces, func(topic string, payload []byte) {
+		require.Equal(t, 1, broker.Publish(topic, payload))
+	}
+}
+
+func TestRoundTrip_Base64ProtoLogs(t *testing.T) {
+	sink, _, publish := startRoundTripReceiver(t)
+	rnd := rand.New(rand.NewSource(30))
+	var want []plog.Logs
+	for i := 0; i < roundTrips; i++ {
+		logs := testdata.RandomLogs(rnd)
+		proto, err := (&plog.ProtoMarshaler{}).MarshalLogs(logs)
+		require.NoError(t, err)
+		publish("otel/logs", []byte(base64.StdEncoding.EncodeToString(proto)))
+		want = append(want, logs)
+	}
+	require.Eventually(t, func() bool { return len(sink.AllLogs()) == roundTrips }, 5*time.Second, time.Millisecond)
+	assert.Equal(t, want, sink.AllLogs())
+}
+
+func TestRoundTrip_Base64ProtoTraces(t *testing.T) {
+	_, sink, publish := startRoundTripReceiver(t)
+	rnd := rand.New(rand.NewSource(30))
+	var want []ptrace.Traces
+	for i := 0; i < roundTrips; i++ {
+		traces := testdata.RandomTraces(rnd)
+		proto, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(traces)
+		require.NoError(t, err)
+		publish("otel/traces", []byte(base64.StdEncoding.EncodeToString(proto)))
+		want = append(want, traces)
+	}
+	require.Eventually(t, func() bool { return len(sink.AllTraces()) == roundTrips }, 5*time.Second, time.Millisecond)
+	assert.Equal(t, want, sink.AllTraces())
+}
+
+func TestRoundTrip_JSONLogs(t *testing.T) {
+	sink, _, publish := startRoundTripReceiver(t)
+	rnd := rand.New(rand.NewSource(30))
+	var want []plog.Logs
+	for i := 0; i < roundTrips; i++ {
+		logs := plog.NewLogs()
+		resourceLogs := logs.ResourceLogs().AppendEmpty()
+		scopeLogs := resourceLogs.ScopeLogs().AppendEmpty()
+		random := testdata.RandomLogs(rnd).ResourceLogs().At(0)
+		random.Resource().CopyTo(resourceLogs.Resource())
+		random.ScopeLogs().At(0).Scope().CopyTo(scopeLogs.Scope())
+		record := scopeLogs.LogRecords().AppendEmpty()
+		random.ScopeLogs().At(0).LogRecords().At(0).CopyTo(record)
+		record.SetFlags(plog.DefaultLogRecordFlags.WithIsSampled(i%2 == 0))
+		record.SetEventName("order.placed")
+
+		// IDs alternate between hex and base64
+		rawTraceID, rawSpanID := record.TraceID(), record.SpanID()
+		traceID, spanID := rawTraceID.String(), rawSpanID.String()
+		if i%2 == 0 {
+			traceID = base64.StdEncoding.EncodeToString(rawTraceID[:])
+			spanID = base64.StdEncoding.EncodeToString(rawSpanID[:])
+		}
+		payload, err := json.Marshal(map[string]any{
+			"resource": map[string]any{"attributes": resourceLogs.Resource().Attributes().AsRaw()},
+			"scope": map[string]any{
+				"name":       scopeLogs.Scope().Name(),
+				"version":    scopeLogs.Scope().Version(),
+				"attributes": scopeLogs.Scope().Attributes().AsRaw(),
+			},
+			"time_unix_nano":          record.Timestamp(),
+			"observed_time_unix_nano": record.ObservedTimestamp(),
+			"severity_number":         record.SeverityNumber(),
+			"severity_text":           record.SeverityText(),
+			"body":                    record.Body().Str(),
+			"attributes":              record.Attributes().AsRaw(),
+			"flags":                   record.Flags(),
+			"event_name":              record.EventName(),
+			"trace_id":                traceID,
+			"span_id":                 spanID,
+		})
+		require.NoError(t, err)
+		publish("otel/logs", payload)
+		want = append(want, logs)
+	}
+	require.Eventually(t, func() bool { return len(sink.AllLogs()) == roundTrips }, 5*time.Second, time.Millisecond)
+	for i, logs := range sink.AllLogs() {
+		assert.Equal(t, want[i], logs, "logs %d", i)
+	}
+}
+
+func TestRoundTrip_JSONTraces(t *testing.T) {
+	_, sink, publish := startRoundTripReceiver(t)
+	rnd := rand.New(rand.NewSource(30))
+	var want []ptrace.Traces
+	for i := 0; i < roundTrips; i++ {
+		traces := testdata.RandomTraces(rnd)
+		var spans []any
+		for r := 0; r < traces.ResourceSpans().Len(); r++ {
+			resourceSpans := traces.ResourceSpans().At(r)
+			// spans of equal resources are grouped, so keep resources distinct
+			resourceSpans.Resource().Attributes().PutInt("resource.index", int64(r))
+			scope := resourceSpans.ScopeSpans().At(0).Scope()
+			for s := 0; s < resourceSpans.ScopeSpans().At(0).Spans().Len(); s++ {
+				span := resourceSpans.ScopeSpans().At(0).Spans().At(s)
+				span.TraceState().FromRaw("vendor=" + span.SpanID().String())
+				span.SetFlags(uint32(i % 2))
+				event := span.Events().AppendEmpty()
+				event.SetName("event")
+				event.SetTimestamp(span.StartTimestamp() + 1)
+				event.Attributes().PutInt("n", int64(s))
+				link := span.Links().AppendEmpty()
+				link.SetTraceID(testdata.RandomTraceID(rnd))
+				link.SetSpanID(testdata.RandomSpanID(rnd))
+				link.Attributes().PutStr("link", "follows")
+
+				// enums alternate between numbers and names
+				var kind, code any = span.Kind(), span.Status().Code()
+				if i%2 == 0 {
+					kind, code = span.Kind().String(), span.Status().Code().String()
+				}
+				spans = append(spans, map[string]any{
+					"resource":       map[string]any{"attributes": resourceSpans.Resource().Attributes().AsRaw()},
+					"scope":          map[string]any{"name": scope.Name()},
+					"trace_id":       span.TraceID().String(),
+					"span_id":        span.SpanID().String(),
+					"parent_span_id": span.ParentSpanID().String(),
+					"trace_state":    span.TraceState().AsRaw(),
+					"flags":          span.Flags(),
+					"name":           span.Name(),
+					"kind":           kind,
+					"start_time":     span.StartTimestamp(),
+					"end_time":       span.EndTimestamp(),
+					"attributes":     span.Attributes().AsRaw(),
+					"events": []any{map[string]any{
+						"name":       event.Name(),
+						"time":       event.Timestamp(),
+						"attributes": event.Attributes().AsRaw(),
+					}},
+					"links": []any{map[string]any{
+						"trace_id":   link.TraceID().String(),
+						"span_id":    link.SpanID().String(),
+						"attributes": link.Attributes().AsRaw(),
+					}},
+					"status": map[string]any{"code": code, "message": span.Status().Message()},
+				})
+			}
+		}
+		payload, err := json.Marshal(spans)
+		require.NoError(t, err)
+		publish("otel/traces", payload)
+		want = append(want, traces)
+	}
+	require.Eventually(t, func() bool { return len(sink.AllTraces()) == roundTrips }, 5*time.Second, time.Millisecond)
+	for i, traces := range sink.AllTraces() {
+		assert.Equal(t, want[i], traces, "traces %d", i)
+	}
+}
@@ -0,0 +1,60 @@
+package solaceotlpreceiver
+
+import (
+	"slices"
+
+	"go.uber.org/zap"
+	"solace.dev/go/messaging/pkg/solace/message"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/selector"
+)
+
+// applySelectors sets the configured message selectors of the bound queues
+func (r *Receiver) applySelectors(bindings []*queueBinding) {
+	for _, cfg := range r.config.Selectors {
+		i := slices.IndexFunc(bindings, func(q *queueBinding) bool { return q.name == cfg.Queue })
+		if i < 0 {
+			r.logger.Warn("Ignoring selector of a queue the receiver does not consume", zap.String("queue", cfg.Queue))
+			continue
+		}
+		q := bindings[i]
+		if cfg.Evaluate != solaceconfig.SelectorEvaluateClient {
+			q.brokerSelector = cfg.Expression
+			continue
+		}
+		sel, err := selector.Parse(cfg.Expression)
+		if err != nil {
+			// Validate rejects such configurations
+			r.logger.Error("Ignoring invalid selector", zap.String("queue", cfg.Queue), zap.Error(err))
+			continue
+		}
+		q.clientSelector = sel
+	}
+}
+
+// selects reports whether msg matches the client-side selector of queue q
+func (r *Receiver) selects(q *queueBinding, msg message.InboundMessage) bool {
+	if q.clientSelector == nil {
+		return true
+	}
+	return q.clientSelector.Matches(func(name string) (any, bool) {
+		return selectorValue(msg, name)
+	})
+}
+
+// selectorValue returns a header field or user property of msg. Like broker
+// selectors, header fields are referenced by their JMS names.
+func selectorValue(msg message.InboundMessage, name string) (any, bool) {
+	switch name {
+	case "JMSCorrelationID":
+		return msg.GetCorrelationID()
+	case "JMSMessageID":
+		return msg.GetApplicationMessageID()
+	case "JMSPriority":
+		return msg.GetPriority()
+	case "JMSType":
+		return msg.GetApplicationMessageType()
+	}
+	return msg.GetProperty(name)
+}
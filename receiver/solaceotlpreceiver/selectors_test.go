@@ -0,0 +1,114 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/solacetest"
+)
+
+func TestSelectors_Broker(t *testing.T) {
+	const expression = "env = 'prod'"
+	cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+	cfg.Selectors = []solaceconfig.SelectorConfig{{Queue: testQueue, Expression: expression}}
+	r, err := NewReceiver(receivertest.NewNopSettings(typeStr), cfg, consumertest.NewNop(), nil, nil, newTestBroker().NewMessagingService())
+	require.NoError(t, err)
+	startReceiver(t, r)
+
+	consumerSelector := func() string {
+		r.queuesMu.Lock()
+		defer r.queuesMu.Unlock()
+		return r.queues[0].consumer.(*solacetest.PersistentMessageReceiver).Selector()
+	}
+	assert.Equal(t, expression, consumerSelector())
+	assert.Nil(t, r.queues[0].clientSelector)
+
+	_, err = r.Replay("all", nil)
+	require.NoError(t, err)
+	assert.Equal(t, expression, consumerSelector(), "replays keep the selector")
+}
+
+func TestSelectors_Client(t *testing.T) {
+	tel := componenttest.NewTelemetry()
+	t.Cleanup(func() { require.NoError(t, tel.Shutdown(context.Background())) })
+	settings := receivertest.NewNopSettings(typeStr)
+	settings.TelemetrySettings = tel.NewTelemetrySettings()
+	cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+	cfg.Selectors = []solaceconfig.SelectorConfig{{
+		Queue:      testQueue,
+		Expression: "env = 'prod' AND (severity >= 13 OR JMSPriority > 5)",
+		Evaluate:   solaceconfig.SelectorEvaluateClient,
+	}}
+	broker := newTestBroker()
+	sink := new(consumertest.LogsSink)
+	r, err := NewReceiver(settings, cfg, sink, nil, nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	startReceiver(t, r)
+
+	broker.Publish("otel/logs", []byte(`{"body": "error"}`),
+		solacetest.WithProperty("env", "prod"), solacetest.WithProperty("severity", int32(17)))
+	broker.Publish("otel/logs", []byte(`{"body": "urgent"}`),
+		solacetest.WithProperty("env", "prod"), solacetest.WithPriority(9))
+	broker.Publish("otel/logs", []byte(`{"body": "info"}`),
+		solacetest.WithProperty("env", "prod"), solacetest.WithProperty("severity", int32(9)))
+	broker.Publish("otel/logs", []byte(`{"body": "dev"}`),
+		solacetest.WithProperty("env", "dev"), solacetest.WithProperty("severity", int32(17)))
+	broker.Publish("otel/logs", []byte(`not even a payload`))
+	require.Eventually(t, func() bool { return broker.Acked(testQueue) == 5 }, time.Second, time.Millisecond,
+		"messages that do not match are acknowledged before decoding")
+	assert.Equal(t, []string{"error", "urgent"}, logBodies(sink))
+
+	got, err := tel.GetMetric("otelcol_receiver_solaceotlp_filtered_messages")
+	require.NoError(t, err)
+	var filtered int64
+	for _, dp := range got.Data.(metricdata.Sum[int64]).DataPoints {
+		filtered += dp.Value
+	}
+	assert.Equal(t, int64(3), filtered)
+}
+
+func TestConfigValidate_Selectors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		selectors []solaceconfig.SelectorConfig
+		wantErr   string
+	}{
+		"broker": {selectors: []solaceconfig.SelectorConfig{{Queue: "q", Expression: "env = 'prod'"}}},
+		"client": {selectors: []solaceconfig.SelectorConfig{{Queue: "q", Expression: "env IN ('prod')", Evaluate: solaceconfig.SelectorEvaluateClient}}},
+		"no queue": {
+			selectors: []solaceconfig.SelectorConfig{{Expression: "env = 'prod'"}},
+			wantErr:   "queue must be set",
+		},
+		"two per queue": {
+			selectors: []solaceconfig.SelectorConfig{{Queue: "q", Expression: "a = 1"}, {Queue: "q", Expression: "b = 1"}},
+			wantErr:   "more than one selector",
+		},
+		"invalid expression": {
+			selectors: []solaceconfig.SelectorConfig{{Queue: "q", Expression: "env = "}},
+			wantErr:   "invalid expression",
+		},
+		"unknown evaluation": {
+			selectors: []solaceconfig.SelectorConfig{{Queue: "q", Expression: "a = 1", Evaluate: "both"}},
+			wantErr:   "evaluate must be",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+			cfg.Selectors = tc.selectors
+			err := cfg.Validate()
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
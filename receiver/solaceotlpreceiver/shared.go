@@ -0,0 +1,71 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/receiver"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+)
+
+// receivers holds the receiver of each configuration. The pipelines of one
+// configuration share it, so that it connects once and each message reaches
+// the consumer of its signal.
+var receivers = &sharedReceivers{byConfig: map[*solaceconfig.Config]*sharedReceiver{}}
+
+// sharedReceivers maps configurations to their shared receivers
+type sharedReceivers struct {
+	mu       sync.Mutex
+	byConfig map[*solaceconfig.Config]*sharedReceiver
+}
+
+// sharedReceiver is a Receiver started by its first pipeline and shut down by
+// its first Shutdown, like the shared components of the collector
+type sharedReceiver struct {
+	*Receiver
+	startOnce    sync.Once
+	startErr     error
+	shutdownOnce sync.Once
+	shutdownErr  error
+	remove       func()
+}
+
+// get returns the shared receiver of cfg and creates it on first use
+func (s *sharedReceivers) get(settings receiver.Settings, cfg *solaceconfig.Config) (*sharedReceiver, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if shared, ok := s.byConfig[cfg]; ok {
+		return shared, nil
+	}
+	r, err := NewReceiver(settings, cfg, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	shared := &sharedReceiver{Receiver: r}
+	shared.remove = func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.byConfig, cfg)
+	}
+	s.byConfig[cfg] = shared
+	return shared, nil
+}
+
+// Start starts the receiver once
+func (s *sharedReceiver) Start(ctx context.Context, host component.Host) error {
+	s.startOnce.Do(func() {
+		s.startErr = s.Receiver.Start(ctx, host)
+	})
+	return s.startErr
+}
+
+// Shutdown shuts the receiver down once and forgets it
+func (s *sharedReceiver) Shutdown(ctx context.Context) error {
+	s.shutdownOnce.Do(func() {
+		s.shutdownErr = s.Receiver.Shutdown(ctx)
+		s.remove()
+	})
+	return s.shutdownErr
+}
@@ -0,0 +1,71 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+	"solace.dev/go/messaging/pkg/solace/message"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/shedding"
+)
+
+// errShed is the settlement error of messages rejected by load shedding
+var errShed = errors.New("message shed under back-pressure")
+
+// newShedder creates the load shedder of the configured priority bands
+func newShedder(cfg solaceconfig.LoadSheddingConfig) *shedding.Shedder {
+	bands := make([]shedding.Band, len(cfg.Bands))
+	for i, band := range cfg.Bands {
+		bands[i] = shedding.Band{Name: band.Name, Min: band.Min}
+	}
+	return shedding.New(bands, cfg.Threshold, cfg.Cooldown)
+}
+
+// rank returns the priority or class of service of msg
+func (r *Receiver) rank(msg message.InboundMessage) int {
+	if r.config.LoadShedding.Key == solaceconfig.SheddingKeyClassOfService {
+		return msg.GetClassOfService()
+	}
+	if priority, ok := msg.GetPriority(); ok {
+		return priority
+	}
+	return r.config.LoadShedding.Default
+}
+
+// shed drops or rejects msg if its priority band is shed. It reports whether
+// msg was shed.
+func (r *Receiver) shed(q *queueBinding, msg message.InboundMessage) bool {
+	if r.shedder == nil {
+		return false
+	}
+	band, shed := r.shedder.Shed(r.rank(msg), time.Now())
+	if !shed {
+		return false
+	}
+	action := r.config.LoadShedding.Action
+	r.logger.Debug("Shedding message", zap.String("queue", q.name), zap.String("band", band), zap.String("action", action))
+	r.telemetry.RecordShed(context.Background(), band, action)
+	if action == solaceconfig.SheddingActionReject {
+		r.settleFailure(q, msg, errShed)
+	} else {
+		acknowledgeMessage(r, q, msg)
+	}
+	return true
+}
+
+// noteRefusal escalates load shedding if the pipeline refused a message
+// with a retryable error, which signals back-pressure
+func (r *Receiver) noteRefusal(err error) {
+	if r.shedder == nil || consumererror.IsPermanent(err) {
+		return
+	}
+	now := time.Now()
+	if r.shedder.Refused(now) {
+		r.logger.Warn("Pipeline refused a message; shedding one more priority band",
+			zap.Int("bands", r.shedder.Level(now)), zap.Error(err))
+	}
+}
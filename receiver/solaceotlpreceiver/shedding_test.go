@@ -0,0 +1,146 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/solacetest"
+)
+
+// shedCounts returns the value of the shed_messages metric by band and action
+func shedCounts(tel *componenttest.Telemetry) map[string]int64 {
+	counts := map[string]int64{}
+	got, err := tel.GetMetric("otelcol_receiver_solaceotlp_shed_messages")
+	if err != nil {
+		return counts
+	}
+	for _, dp := range got.Data.(metricdata.Sum[int64]).DataPoints {
+		band, _ := dp.Attributes.Value("band")
+		action, _ := dp.Attributes.Value("action")
+		counts[band.AsString()+"/"+action.AsString()] += dp.Value
+	}
+	return counts
+}
+
+// newSheddingTest returns receiver settings with test telemetry and a test
+// configuration with load shedding enabled
+func newSheddingTest(t *testing.T, action string, cooldown time.Duration) (*componenttest.Telemetry, *solaceconfig.Config) {
+	tel := componenttest.NewTelemetry()
+	t.Cleanup(func() { require.NoError(t, tel.Shutdown(context.Background())) })
+	cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+	cfg.LoadShedding.Enabled = true
+	cfg.LoadShedding.Action = action
+	cfg.LoadShedding.Cooldown = cooldown
+	return tel, cfg
+}
+
+func TestLoadShedding_DropsLowPriorityUnderBackPressure(t *testing.T) {
+	tel, cfg := newSheddingTest(t, solaceconfig.SheddingActionDrop, time.Hour)
+	settings := receivertest.NewNopSettings(typeStr)
+	settings.TelemetrySettings = tel.NewTelemetrySettings()
+	broker := newTestBroker()
+	logs := newFlakyLogs(t, assert.AnError)
+	r, err := NewReceiver(settings, cfg, logs, nil, nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	startReceiver(t, r)
+
+	broker.Publish("otel/logs", []byte(`{"body": "debug"}`), solacetest.WithPriority(2))
+	require.Eventually(t, func() bool { return broker.Acked(testQueue) == 1 }, time.Second, time.Millisecond,
+		"the redelivery of the refused message is shed")
+	logs.down.Store(false)
+
+	broker.Publish("otel/logs", []byte(`{"body": "info"}`), solacetest.WithPriority(5))
+	broker.Publish("otel/logs", []byte(`{"body": "trace"}`), solacetest.WithPriority(1))
+	broker.Publish("otel/logs", []byte(`{"body": "payment"}`), solacetest.WithPriority(9))
+	broker.Publish("otel/logs", []byte(`{"body": "default"}`))
+	require.Eventually(t, func() bool { return broker.Acked(testQueue) == 5 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"info", "payment", "default"}, logBodies(logs.sink), "only the lowest band is shed")
+	assert.Equal(t, map[string]int64{"low/drop": 2}, shedCounts(tel))
+}
+
+func TestLoadShedding_RejectRecovers(t *testing.T) {
+	tel, cfg := newSheddingTest(t, solaceconfig.SheddingActionReject, 50*time.Millisecond)
+	cfg.LoadShedding.Key = solaceconfig.SheddingKeyClassOfService
+	cfg.LoadShedding.Threshold = 2
+	cfg.LoadShedding.Bands = []solaceconfig.SheddingBandConfig{{Name: "cos1", Min: 0}, {Name: "cos2", Min: 1}}
+	settings := receivertest.NewNopSettings(typeStr)
+	settings.TelemetrySettings = tel.NewTelemetrySettings()
+	broker := newTestBroker()
+	logs := newFlakyLogs(t, assert.AnError)
+	r, err := NewReceiver(settings, cfg, logs, nil, nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	startReceiver(t, r)
+
+	broker.Publish("otel/logs", []byte(`{"body": "bulk"}`), solacetest.WithClassOfService(0))
+	require.Eventually(t, func() bool { return shedCounts(tel)["cos1/reject"] > 0 }, time.Second, time.Millisecond)
+	assert.Zero(t, broker.Acked(testQueue), "rejected messages stay on the broker")
+	logs.down.Store(false)
+
+	require.Eventually(t, func() bool { return broker.Acked(testQueue) == 1 }, time.Second, time.Millisecond,
+		"the message is consumed once shedding recovered")
+	assert.Equal(t, []string{"bulk"}, logBodies(logs.sink))
+}
+
+func TestConfigValidate_LoadShedding(t *testing.T) {
+	for name, tc := range map[string]struct {
+		modify  func(*solaceconfig.Config)
+		wantErr string
+	}{
+		"default":  {modify: func(*solaceconfig.Config) {}},
+		"disabled": {modify: func(c *solaceconfig.Config) { c.LoadShedding = solaceconfig.LoadSheddingConfig{} }},
+		"unknown key": {
+			modify:  func(c *solaceconfig.Config) { c.LoadShedding.Key = "size" },
+			wantErr: "key must be",
+		},
+		"unknown action": {
+			modify:  func(c *solaceconfig.Config) { c.LoadShedding.Action = "delay" },
+			wantErr: "action must be",
+		},
+		"no cooldown": {
+			modify:  func(c *solaceconfig.Config) { c.LoadShedding.Cooldown = 0 },
+			wantErr: "cooldown must be positive",
+		},
+		"no bands": {
+			modify:  func(c *solaceconfig.Config) { c.LoadShedding.Bands = nil },
+			wantErr: "bands must not be empty",
+		},
+		"unordered bands": {
+			modify: func(c *solaceconfig.Config) {
+				c.LoadShedding.Bands = []solaceconfig.SheddingBandConfig{{Name: "a", Min: 4}, {Name: "b", Min: 2}}
+			},
+			wantErr: "greater than the min of the previous band",
+		},
+		"band above threshold": {
+			modify:  func(c *solaceconfig.Config) { c.LoadShedding.Threshold = 4 },
+			wantErr: "threshold must be greater",
+		},
+		"reject with auto ack": {
+			modify: func(c *solaceconfig.Config) {
+				c.LoadShedding.Action = solaceconfig.SheddingActionReject
+				c.AckMode = solaceconfig.AckModeAuto
+			},
+			wantErr: "requires ack_mode",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+			cfg.LoadShedding.Enabled = true
+			tc.modify(cfg)
+			err := cfg.Validate()
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, "load_shedding: ")
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
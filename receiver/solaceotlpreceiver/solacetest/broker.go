@@ -0,0 +1,243 @@
+package solacetest
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"solace.dev/go/messaging/pkg/solace"
+
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/topic"
+)
+
+// Broker is an in-process fake of a Solace event broker. It spools messages
+// on queues, delivers them to persistent receivers created through
+// MessagingService and keeps a replay log per queue.
+type Broker struct {
+	mu          sync.Mutex
+	cond        *sync.Cond
+	queues      map[string]*queue
+	services    []*MessagingService
+	unreachable bool
+	nextRGMID   ReplicationGroupMessageID
+	nextSeq     int64
+}
+
+// queue is a durable queue with its topic subscriptions and bound flows
+type queue struct {
+	name          string
+	subscriptions []string
+	pending       []*Message
+	flows         []*PersistentMessageReceiver
+	log           []*Message
+	acked         int
+	rejected      []*Message
+}
+
+// NewBroker creates an empty broker
+func NewBroker() *Broker {
+	b := &Broker{queues: map[string]*queue{}}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// CreateQueue provisions a queue and adds topic subscriptions to it. Creating
+// an existing queue only adds the subscriptions.
+func (b *Broker) CreateQueue(name string, subscriptions ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	q := b.queueLocked(name)
+	q.subscriptions = append(q.subscriptions, subscriptions...)
+}
+
+// Publish spools a message on every queue with a matching topic subscription
+// and returns the number of queues it was spooled on.
+func (b *Broker) Publish(destination string, payload []byte, opts ...MessageOption) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	spooled := 0
+	for _, q := range b.queues {
+		for _, subscription := range q.subscriptions {
+			if topic.Matches(subscription, destination) {
+				b.spoolLocked(q, NewMessage(destination, payload, opts...))
+				spooled++
+				break
+			}
+		}
+	}
+	return spooled
+}
+
+// PublishToQueue spools a message directly on a queue
+func (b *Broker) PublishToQueue(name string, payload []byte, opts ...MessageOption) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	q, ok := b.queues[name]
+	if !ok {
+		return fmt.Errorf("queue %q does not exist", name)
+	}
+	b.spoolLocked(q, NewMessage(name, payload, opts...))
+	return nil
+}
+
+// Pending returns the number of messages on a queue waiting for delivery
+func (b *Broker) Pending(name string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if q, ok := b.queues[name]; ok {
+		return len(q.pending)
+	}
+	return 0
+}
+
+// Unacked returns the number of delivered messages not yet settled
+func (b *Broker) Unacked(name string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	q, ok := b.queues[name]
+	if !ok {
+		return 0
+	}
+	unacked := 0
+	for _, flow := range q.flows {
+		unacked += len(flow.unacked)
+	}
+	return unacked
+}
+
+// Acked returns the number of messages removed from a queue by acknowledgement
+func (b *Broker) Acked(name string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if q, ok := b.queues[name]; ok {
+		return q.acked
+	}
+	return 0
+}
+
+// Rejected returns the messages settled as REJECTED on a queue
+func (b *Broker) Rejected(name string) []*Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if q, ok := b.queues[name]; ok {
+		return append([]*Message(nil), q.rejected...)
+	}
+	return nil
+}
+
+// ClearReplayLog drops the replay log of a queue
+func (b *Broker) ClearReplayLog(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if q, ok := b.queues[name]; ok {
+		q.log = nil
+	}
+}
+
+// SetUnreachable makes Connect fail until it is reset
+func (b *Broker) SetUnreachable(unreachable bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.unreachable = unreachable
+}
+
+// Interrupt simulates a connection loss: every connected messaging service is
+// disconnected, its receivers are terminated and unsettled messages become
+// available for redelivery. Service interruption listeners receive cause.
+func (b *Broker) Interrupt(cause error) {
+	b.mu.Lock()
+	var interrupted []*MessagingService
+	var terminated []*PersistentMessageReceiver
+	for _, service := range b.services {
+		if !service.connected {
+			continue
+		}
+		service.connected = false
+		interrupted = append(interrupted, service)
+		for _, r := range service.receivers {
+			if r.state == receiverRunning {
+				r.unbindLocked()
+				terminated = append(terminated, r)
+			}
+		}
+	}
+	b.cond.Broadcast()
+	b.mu.Unlock()
+
+	event := newEvent("service interrupted", cause)
+	for _, r := range terminated {
+		r.notifyTermination(event)
+	}
+	for _, service := range interrupted {
+		service.notifyInterruption(event)
+	}
+}
+
+// NewMessagingService creates a messaging service connected to this broker
+func (b *Broker) NewMessagingService() *MessagingService {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	service := &MessagingService{
+		broker:                b,
+		interruptionListeners: map[uint64]solace.ServiceInterruptionListener{},
+	}
+	b.services = append(b.services, service)
+	return service
+}
+
+// queueLocked returns the named queue, creating it if needed
+func (b *Broker) queueLocked(name string) *queue {
+	q, ok := b.queues[name]
+	if !ok {
+		q = &queue{name: name}
+		b.queues[name] = q
+	}
+	return q
+}
+
+// spoolLocked stores a message on a queue and in its replay log
+func (b *Broker) spoolLocked(q *queue, m *Message) {
+	b.nextRGMID++
+	b.nextSeq++
+	m.rgmid = b.nextRGMID
+	m.sequenceNumber = b.nextSeq
+	m.timestamp = time.Now()
+	q.log = append(q.log, m.clone())
+	q.pending = append(q.pending, m)
+	b.cond.Broadcast()
+}
+
+// isActive reports whether flow may receive messages from q
+func (q *queue) isActive(flow *PersistentMessageReceiver) bool {
+	if !flow.exclusive {
+		return true
+	}
+	for _, f := range q.flows {
+		if f.exclusive {
+			return f == flow
+		}
+	}
+	return false
+}
+
+// requeue puts a message back at the head of the queue for redelivery
+func (q *queue) requeue(m *Message) {
+	m.redelivered = true
+	q.pending = append([]*Message{m}, q.pending...)
+}
+
+// serviceEvent implements solace.ServiceEvent and solace.TerminationEvent
+type serviceEvent struct {
+	timestamp time.Time
+	message   string
+	cause     error
+}
+
+func newEvent(message string, cause error) *serviceEvent {
+	return &serviceEvent{timestamp: time.Now(), message: message, cause: cause}
+}
+
+func (e *serviceEvent) GetTimestamp() time.Time { return e.timestamp }
+func (e *serviceEvent) GetBrokerURI() string    { return "tcp://solacetest" }
+func (e *serviceEvent) GetMessage() string      { return e.message }
+func (e *serviceEvent) GetCause() error         { return e.cause }
@@ -0,0 +1,220 @@
+package solacetest
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"solace.dev/go/messaging/pkg/solace"
+	"solace.dev/go/messaging/pkg/solace/config"
+	"solace.dev/go/messaging/pkg/solace/message"
+	"solace.dev/go/messaging/pkg/solace/resource"
+)
+
+// collector records the payloads delivered to a message handler
+type collector struct {
+	mu       sync.Mutex
+	messages []message.InboundMessage
+}
+
+func (c *collector) handle(msg message.InboundMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages = append(c.messages, msg)
+}
+
+func (c *collector) payloads() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var payloads []string
+	for _, m := range c.messages {
+		p, _ := m.GetPayloadAsString()
+		payloads = append(payloads, p)
+	}
+	return payloads
+}
+
+func (c *collector) last() message.InboundMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.messages[len(c.messages)-1]
+}
+
+func startReceiver(t *testing.T, service solace.MessagingService, queue string, handler solace.MessageHandler,
+	configure func(solace.PersistentMessageReceiverBuilder) solace.PersistentMessageReceiverBuilder) solace.PersistentMessageReceiver {
+	t.Helper()
+	builder := service.CreatePersistentMessageReceiverBuilder().
+		WithRequiredMessageOutcomeSupport(config.PersistentReceiverFailedOutcome, config.PersistentReceiverRejectedOutcome)
+	if configure != nil {
+		builder = configure(builder)
+	}
+	receiver, err := builder.Build(resource.QueueDurableExclusive(queue))
+	require.NoError(t, err)
+	require.NoError(t, receiver.Start())
+	require.NoError(t, receiver.ReceiveAsync(handler))
+	return receiver
+}
+
+func TestBroker_DeliversAndAcks(t *testing.T) {
+	broker := NewBroker()
+	broker.CreateQueue("q", "otel/>")
+	service := broker.NewMessagingService()
+	require.NoError(t, service.Connect())
+
+	assert.Equal(t, 1, broker.Publish("otel/traces", []byte("a"), WithApplicationMessageID("id-1")))
+	assert.Equal(t, 0, broker.Publish("other/traces", []byte("x")))
+
+	c := &collector{}
+	receiver := startReceiver(t, service, "q", c.handle, nil)
+	require.Eventually(t, func() bool { return len(c.payloads()) == 1 }, time.Second, time.Millisecond)
+
+	msg := c.last()
+	id, _ := msg.GetApplicationMessageID()
+	assert.Equal(t, "id-1", id)
+	assert.Equal(t, "otel/traces", msg.GetDestinationName())
+	assert.Equal(t, 1, broker.Unacked("q"))
+
+	require.NoError(t, receiver.Ack(msg))
+	assert.Equal(t, 1, broker.Acked("q"))
+	assert.Equal(t, 0, broker.Unacked("q"))
+	assert.Error(t, receiver.Ack(msg), "a message can be settled only once")
+}
+
+func TestBroker_SettleFailedRedelivers(t *testing.T) {
+	broker := NewBroker()
+	broker.CreateQueue("q")
+	service := broker.NewMessagingService()
+	require.NoError(t, service.Connect())
+	require.NoError(t, broker.PublishToQueue("q", []byte("a")))
+
+	c := &collector{}
+	receiver := startReceiver(t, service, "q", c.handle, nil)
+	require.Eventually(t, func() bool { return len(c.payloads()) == 1 }, time.Second, time.Millisecond)
+	assert.False(t, c.last().IsRedelivered())
+
+	require.NoError(t, receiver.Settle(c.last(), config.PersistentReceiverFailedOutcome))
+	require.Eventually(t, func() bool { return len(c.payloads()) == 2 }, time.Second, time.Millisecond)
+	assert.True(t, c.last().IsRedelivered())
+
+	require.NoError(t, receiver.Settle(c.last(), config.PersistentReceiverRejectedOutcome))
+	assert.Len(t, broker.Rejected("q"), 1)
+	assert.Equal(t, 0, broker.Pending("q"))
+}
+
+func TestBroker_TerminateRedeliversUnsettled(t *testing.T) {
+	broker := NewBroker()
+	broker.CreateQueue("q")
+	service := broker.NewMessagingService()
+	require.NoError(t, service.Connect())
+	require.NoError(t, broker.PublishToQueue("q", []byte("a")))
+	require.NoError(t, broker.PublishToQueue("q", []byte("b")))
+
+	first := &collector{}
+	receiver := startReceiver(t, service, "q", first.handle, nil)
+	require.Eventually(t, func() bool { return len(first.payloads()) == 2 }, time.Second, time.Millisecond)
+	require.NoError(t, receiver.Ack(first.messages[0]))
+	require.NoError(t, receiver.Terminate(time.Second))
+
+	second := &collector{}
+	startReceiver(t, service, "q", second.handle, nil)
+	require.Eventually(t, func() bool { return len(second.payloads()) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"b"}, second.payloads())
+	assert.True(t, second.last().IsRedelivered())
+}
+
+func TestBroker_ExclusiveQueueHasOneActiveFlow(t *testing.T) {
+	broker := NewBroker()
+	broker.CreateQueue("q")
+	service := broker.NewMessagingService()
+	require.NoError(t, service.Connect())
+
+	active, standby := &collector{}, &collector{}
+	startReceiver(t, service, "q", active.handle, func(b solace.PersistentMessageReceiverBuilder) solace.PersistentMessageReceiverBuilder {
+		return b.WithMessageAutoAcknowledgement()
+	})
+	startReceiver(t, service, "q", standby.handle, nil)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, broker.PublishToQueue("q", []byte("m")))
+	}
+	require.Eventually(t, func() bool { return broker.Acked("q") == 5 }, time.Second, time.Millisecond)
+	assert.Len(t, active.payloads(), 5)
+	assert.Empty(t, standby.payloads())
+}
+
+func TestBroker_InterruptNotifiesAndRedelivers(t *testing.T) {
+	broker := NewBroker()
+	broker.CreateQueue("q")
+	service := broker.NewMessagingService()
+	require.NoError(t, service.Connect())
+	require.NoError(t, broker.PublishToQueue("q", []byte("a")))
+
+	interrupted := make(chan error, 1)
+	service.AddServiceInterruptionListener(func(event solace.ServiceEvent) {
+		interrupted <- event.GetCause()
+	})
+	c := &collector{}
+	startReceiver(t, service, "q", c.handle, nil)
+	require.Eventually(t, func() bool { return len(c.payloads()) == 1 }, time.Second, time.Millisecond)
+
+	cause := errors.New("link down")
+	broker.Interrupt(cause)
+	assert.Equal(t, cause, <-interrupted)
+	assert.False(t, service.IsConnected())
+	assert.Equal(t, 1, broker.Pending("q"))
+
+	require.NoError(t, service.Connect())
+	again := &collector{}
+	startReceiver(t, service, "q", again.handle, nil)
+	require.Eventually(t, func() bool { return len(again.payloads()) == 1 }, time.Second, time.Millisecond)
+	assert.True(t, again.last().IsRedelivered())
+}
+
+func TestBroker_Replay(t *testing.T) {
+	broker := NewBroker()
+	broker.CreateQueue("q")
+	service := broker.NewMessagingService()
+	require.NoError(t, service.Connect())
+
+	for _, p := range []string{"a", "b", "c"} {
+		require.NoError(t, broker.PublishToQueue("q", []byte(p)))
+	}
+
+	all := &collector{}
+	receiver := startReceiver(t, service, "q", all.handle, func(b solace.PersistentMessageReceiverBuilder) solace.PersistentMessageReceiverBuilder {
+		return b.WithMessageAutoAcknowledgement()
+	})
+	require.Eventually(t, func() bool { return broker.Acked("q") == 3 }, time.Second, time.Millisecond)
+	second, ok := all.messages[1].GetReplicationGroupMessageID()
+	require.True(t, ok)
+	require.NoError(t, receiver.Terminate(time.Second))
+
+	replayed := &collector{}
+	receiver = startReceiver(t, service, "q", replayed.handle, func(b solace.PersistentMessageReceiverBuilder) solace.PersistentMessageReceiverBuilder {
+		return b.WithMessageAutoAcknowledgement().WithMessageReplay(config.ReplayStrategyReplicationGroupMessageID(second))
+	})
+	require.Eventually(t, func() bool { return len(replayed.payloads()) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"c"}, replayed.payloads())
+	require.NoError(t, receiver.Terminate(time.Second))
+
+	everything := &collector{}
+	startReceiver(t, service, "q", everything.handle, func(b solace.PersistentMessageReceiverBuilder) solace.PersistentMessageReceiverBuilder {
+		return b.WithMessageReplay(config.ReplayStrategyAllMessages())
+	})
+	require.Eventually(t, func() bool { return len(everything.payloads()) == 3 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"a", "b", "c"}, everything.payloads())
+}
+
+func TestBroker_UnreachableConnectFails(t *testing.T) {
+	broker := NewBroker()
+	broker.SetUnreachable(true)
+	service := broker.NewMessagingService()
+
+	err := service.Connect()
+	var unreachable *solace.ServiceUnreachableError
+	assert.ErrorAs(t, err, &unreachable)
+	assert.Equal(t, 1, service.ConnectAttempts())
+}
@@ -0,0 +1,12 @@
+// Package solacetest provides an in-process fake of a Solace event broker for
+// tests. Broker spools published messages on queues with topic subscriptions;
+// MessagingService and PersistentMessageReceiver implement the Solace Go API
+// interfaces used by the receiver, including acknowledgement, settlement with
+// FAILED and REJECTED outcomes, redelivery of unsettled messages, service
+// interruptions and message replay.
+//
+//	broker := solacetest.NewBroker()
+//	broker.CreateQueue("telemetry", "otel/>")
+//	service := broker.NewMessagingService()
+//	broker.Publish("otel/traces", payload)
+package solacetest
@@ -0,0 +1,324 @@
+package solacetest
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"solace.dev/go/messaging/pkg/solace"
+	"solace.dev/go/messaging/pkg/solace/config"
+	"solace.dev/go/messaging/pkg/solace/message"
+	"solace.dev/go/messaging/pkg/solace/resource"
+)
+
+// Faults configures the faults a FaultInjector injects. Rates are
+// probabilities between 0 and 1; zero values disable a fault.
+type Faults struct {
+	ConnectFailures  int           // Connect calls that fail before the first one succeeds
+	ConnectErrorRate float64       // Probability that any later Connect call fails
+	AckDropRate      float64       // Probability that an Ack or Settle is silently discarded
+	MaxLatency       time.Duration // Upper bound of a random delay before each delivery
+	DuplicateRate    float64       // Probability that a message is delivered twice
+	TerminateAfter   int           // Deliveries after which the receiver terminates unsolicited
+	Seed             int64         // Seed of the random source, for reproducible runs
+}
+
+// FaultCounts counts the faults a FaultInjector injected
+type FaultCounts struct {
+	ConnectErrors int
+	DroppedAcks   int
+	Duplicates    int
+	Terminations  int
+}
+
+// FaultInjector decides which faults to inject. It is safe for concurrent use.
+type FaultInjector struct {
+	mu         sync.Mutex
+	faults     Faults
+	rnd        *rand.Rand
+	connects   int
+	deliveries int
+	counts     FaultCounts
+}
+
+// NewFaultInjector creates a FaultInjector for faults
+func NewFaultInjector(faults Faults) *FaultInjector {
+	return &FaultInjector{faults: faults, rnd: rand.New(rand.NewSource(faults.Seed))}
+}
+
+// Counts returns the faults injected so far
+func (f *FaultInjector) Counts() FaultCounts {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.counts
+}
+
+// ConnectError returns an error if the current Connect call should fail
+func (f *FaultInjector) ConnectError() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.connects++
+	if f.connects <= f.faults.ConnectFailures || f.chanceLocked(f.faults.ConnectErrorRate) {
+		f.counts.ConnectErrors++
+		return solace.NewError(&solace.ServiceUnreachableError{}, "injected connect failure", nil)
+	}
+	return nil
+}
+
+// DropAck reports whether the current Ack or Settle should be discarded
+func (f *FaultInjector) DropAck() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.chanceLocked(f.faults.AckDropRate) {
+		f.counts.DroppedAcks++
+		return true
+	}
+	return false
+}
+
+// Delivery is called before each delivery. It sleeps for the injected latency
+// and reports whether the message should be delivered twice and whether the
+// receiver should terminate afterwards.
+func (f *FaultInjector) Delivery() (duplicate, terminate bool) {
+	f.mu.Lock()
+	var latency time.Duration
+	if f.faults.MaxLatency > 0 {
+		latency = time.Duration(f.rnd.Int63n(int64(f.faults.MaxLatency)))
+	}
+	f.deliveries++
+	duplicate = f.chanceLocked(f.faults.DuplicateRate)
+	if duplicate {
+		f.counts.Duplicates++
+	}
+	terminate = f.faults.TerminateAfter > 0 && f.deliveries == f.faults.TerminateAfter
+	if terminate {
+		f.counts.Terminations++
+	}
+	f.mu.Unlock()
+	time.Sleep(latency)
+	return duplicate, terminate
+}
+
+func (f *FaultInjector) chanceLocked(rate float64) bool {
+	return rate > 0 && f.rnd.Float64() < rate
+}
+
+// FaultyMessagingService decorates a solace.MessagingService, such as the one
+// of a Broker or of the Solace SDK, with the faults of a FaultInjector.
+type FaultyMessagingService struct {
+	solace.MessagingService
+	injector *FaultInjector
+}
+
+// WithFaults wraps service so that it injects faults
+func WithFaults(service solace.MessagingService, faults Faults) *FaultyMessagingService {
+	return &FaultyMessagingService{MessagingService: service, injector: NewFaultInjector(faults)}
+}
+
+// Injector returns the fault injector of the service
+func (s *FaultyMessagingService) Injector() *FaultInjector {
+	return s.injector
+}
+
+// Connect fails with an injected error or connects the wrapped service
+func (s *FaultyMessagingService) Connect() error {
+	if err := s.injector.ConnectError(); err != nil {
+		return err
+	}
+	return s.MessagingService.Connect()
+}
+
+// ConnectAsync connects asynchronously
+func (s *FaultyMessagingService) ConnectAsync() <-chan error {
+	result := make(chan error, 1)
+	go func() { result <- s.Connect() }()
+	return result
+}
+
+// ConnectAsyncWithCallback connects and calls callback with the result
+func (s *FaultyMessagingService) ConnectAsyncWithCallback(callback func(solace.MessagingService, error)) {
+	go func() { callback(s, s.Connect()) }()
+}
+
+// CreatePersistentMessageReceiverBuilder creates a builder for faulty queue receivers
+func (s *FaultyMessagingService) CreatePersistentMessageReceiverBuilder() solace.PersistentMessageReceiverBuilder {
+	return &faultyReceiverBuilder{
+		PersistentMessageReceiverBuilder: s.MessagingService.CreatePersistentMessageReceiverBuilder(),
+		injector:                         s.injector,
+	}
+}
+
+// faultyReceiverBuilder keeps the wrapper around every builder in a chain
+type faultyReceiverBuilder struct {
+	solace.PersistentMessageReceiverBuilder
+	injector *FaultInjector
+}
+
+func (b *faultyReceiverBuilder) wrap(inner solace.PersistentMessageReceiverBuilder) solace.PersistentMessageReceiverBuilder {
+	b.PersistentMessageReceiverBuilder = inner
+	return b
+}
+
+func (b *faultyReceiverBuilder) Build(q *resource.Queue) (solace.PersistentMessageReceiver, error) {
+	inner, err := b.PersistentMessageReceiverBuilder.Build(q)
+	if err != nil {
+		return nil, err
+	}
+	return &FaultyPersistentMessageReceiver{PersistentMessageReceiver: inner, injector: b.injector}, nil
+}
+
+func (b *faultyReceiverBuilder) WithActivationPassivationSupport(listener solace.ReceiverStateChangeListener) solace.PersistentMessageReceiverBuilder {
+	return b.wrap(b.PersistentMessageReceiverBuilder.WithActivationPassivationSupport(listener))
+}
+
+func (b *faultyReceiverBuilder) WithMessageAutoAcknowledgement() solace.PersistentMessageReceiverBuilder {
+	return b.wrap(b.PersistentMessageReceiverBuilder.WithMessageAutoAcknowledgement())
+}
+
+func (b *faultyReceiverBuilder) WithMessageClientAcknowledgement() solace.PersistentMessageReceiverBuilder {
+	return b.wrap(b.PersistentMessageReceiverBuilder.WithMessageClientAcknowledgement())
+}
+
+func (b *faultyReceiverBuilder) WithMessageSelector(selector string) solace.PersistentMessageReceiverBuilder {
+	return b.wrap(b.PersistentMessageReceiverBuilder.WithMessageSelector(selector))
+}
+
+func (b *faultyReceiverBuilder) WithMissingResourcesCreationStrategy(strategy config.MissingResourcesCreationStrategy) solace.PersistentMessageReceiverBuilder {
+	return b.wrap(b.PersistentMessageReceiverBuilder.WithMissingResourcesCreationStrategy(strategy))
+}
+
+func (b *faultyReceiverBuilder) WithMessageReplay(strategy config.ReplayStrategy) solace.PersistentMessageReceiverBuilder {
+	return b.wrap(b.PersistentMessageReceiverBuilder.WithMessageReplay(strategy))
+}
+
+func (b *faultyReceiverBuilder) WithSubscriptions(topics ...resource.Subscription) solace.PersistentMessageReceiverBuilder {
+	return b.wrap(b.PersistentMessageReceiverBuilder.WithSubscriptions(topics...))
+}
+
+func (b *faultyReceiverBuilder) WithRequiredMessageOutcomeSupport(outcomes ...config.MessageSettlementOutcome) solace.PersistentMessageReceiverBuilder {
+	return b.wrap(b.PersistentMessageReceiverBuilder.WithRequiredMessageOutcomeSupport(outcomes...))
+}
+
+func (b *faultyReceiverBuilder) FromConfigurationProvider(provider config.ReceiverPropertiesConfigurationProvider) solace.PersistentMessageReceiverBuilder {
+	return b.wrap(b.PersistentMessageReceiverBuilder.FromConfigurationProvider(provider))
+}
+
+// FaultyPersistentMessageReceiver decorates a solace.PersistentMessageReceiver
+// with dropped acknowledgements, delivery latency, duplicate deliveries and
+// unsolicited terminations.
+type FaultyPersistentMessageReceiver struct {
+	solace.PersistentMessageReceiver
+	injector         *FaultInjector
+	mu               sync.Mutex
+	listener         solace.TerminationNotificationListener
+	injecting        bool                   // suppresses the wrapped receiver's notification of an injected termination
+	terminated       bool                   // set once a termination was injected
+	pendingDuplicate message.InboundMessage // returned by the next ReceiveMessage
+}
+
+// SetTerminationNotificationListener registers a listener for unsolicited terminations
+func (r *FaultyPersistentMessageReceiver) SetTerminationNotificationListener(listener solace.TerminationNotificationListener) {
+	r.mu.Lock()
+	r.listener = listener
+	r.mu.Unlock()
+	r.PersistentMessageReceiver.SetTerminationNotificationListener(func(event solace.TerminationEvent) {
+		r.mu.Lock()
+		injecting := r.injecting
+		r.mu.Unlock()
+		if !injecting && listener != nil {
+			listener(event)
+		}
+	})
+}
+
+// ReceiveAsync delivers messages to callback with the injected delivery faults
+func (r *FaultyPersistentMessageReceiver) ReceiveAsync(callback solace.MessageHandler) error {
+	return r.PersistentMessageReceiver.ReceiveAsync(func(msg message.InboundMessage) {
+		if r.isTerminated() {
+			// the flow is gone; the message stays unsettled and is redelivered
+			return
+		}
+		duplicate, terminate := r.injector.Delivery()
+		callback(msg)
+		if duplicate {
+			callback(msg)
+		}
+		if terminate {
+			r.markTerminated()
+			go r.terminate()
+		}
+	})
+}
+
+// ReceiveMessage receives the next message with the injected delivery faults.
+// A duplicate is returned by the following call.
+func (r *FaultyPersistentMessageReceiver) ReceiveMessage(timeout time.Duration) (message.InboundMessage, error) {
+	r.mu.Lock()
+	pending := r.pendingDuplicate
+	r.pendingDuplicate = nil
+	r.mu.Unlock()
+	if pending != nil {
+		return pending, nil
+	}
+	if r.isTerminated() {
+		return nil, solace.NewError(&solace.IllegalStateError{}, "receiver has been terminated, no messages to receive", nil)
+	}
+	msg, err := r.PersistentMessageReceiver.ReceiveMessage(timeout)
+	if err != nil {
+		return nil, err
+	}
+	duplicate, terminate := r.injector.Delivery()
+	if duplicate {
+		r.mu.Lock()
+		r.pendingDuplicate = msg
+		r.mu.Unlock()
+	}
+	if terminate {
+		r.markTerminated()
+		go r.terminate()
+	}
+	return msg, nil
+}
+
+// Ack acknowledges a message unless the acknowledgement is dropped
+func (r *FaultyPersistentMessageReceiver) Ack(msg message.InboundMessage) error {
+	if r.injector.DropAck() {
+		return nil
+	}
+	return r.PersistentMessageReceiver.Ack(msg)
+}
+
+// Settle settles a message unless the settlement is dropped
+func (r *FaultyPersistentMessageReceiver) Settle(msg message.InboundMessage, outcome config.MessageSettlementOutcome) error {
+	if r.injector.DropAck() {
+		return nil
+	}
+	return r.PersistentMessageReceiver.Settle(msg, outcome)
+}
+
+func (r *FaultyPersistentMessageReceiver) markTerminated() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.terminated = true
+}
+
+func (r *FaultyPersistentMessageReceiver) isTerminated() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.terminated
+}
+
+// terminate terminates the wrapped receiver as if the broker had unbound the flow
+func (r *FaultyPersistentMessageReceiver) terminate() {
+	r.mu.Lock()
+	r.injecting = true
+	r.mu.Unlock()
+	err := r.PersistentMessageReceiver.Terminate(0)
+	r.mu.Lock()
+	r.injecting = false
+	listener := r.listener
+	r.mu.Unlock()
+	if listener != nil {
+		listener(newEvent("injected receiver termination", err))
+	}
+}
@@ -0,0 +1,316 @@
+package solacetest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"solace.dev/go/messaging/pkg/solace/message"
+	"solace.dev/go/messaging/pkg/solace/message/rgmid"
+	"solace.dev/go/messaging/pkg/solace/message/sdt"
+)
+
+// Message is an in-memory message.InboundMessage delivered by the fake broker
+type Message struct {
+	payload              []byte
+	destination          string
+	properties           sdt.Map
+	senderTimestamp      time.Time
+	timestamp            time.Time
+	expiration           time.Time
+	senderID             string
+	applicationMessageID string
+	applicationType      string
+	correlationID        string
+	httpContentType      string
+	httpContentEncoding  string
+	priority             int
+	hasPriority          bool
+	classOfService       int
+	sequenceNumber       int64
+	rgmid                ReplicationGroupMessageID
+	redelivered          bool
+	disposed             bool
+}
+
+// MessageOption sets a header field or property of a published message
+type MessageOption func(*Message)
+
+// WithProperty sets a user property
+func WithProperty(key string, value sdt.Data) MessageOption {
+	return func(m *Message) {
+		if m.properties == nil {
+			m.properties = sdt.Map{}
+		}
+		m.properties[key] = value
+	}
+}
+
+// WithSenderTimestamp sets the sender timestamp
+func WithSenderTimestamp(t time.Time) MessageOption {
+	return func(m *Message) { m.senderTimestamp = t }
+}
+
+// WithSenderID sets the sender ID
+func WithSenderID(id string) MessageOption {
+	return func(m *Message) { m.senderID = id }
+}
+
+// WithApplicationMessageID sets the application message ID
+func WithApplicationMessageID(id string) MessageOption {
+	return func(m *Message) { m.applicationMessageID = id }
+}
+
+// WithApplicationMessageType sets the application message type
+func WithApplicationMessageType(messageType string) MessageOption {
+	return func(m *Message) { m.applicationType = messageType }
+}
+
+// WithCorrelationID sets the correlation ID
+func WithCorrelationID(id string) MessageOption {
+	return func(m *Message) { m.correlationID = id }
+}
+
+// WithHTTPContentType sets the HTTP content type
+func WithHTTPContentType(contentType string) MessageOption {
+	return func(m *Message) { m.httpContentType = contentType }
+}
+
+// WithHTTPContentEncoding sets the HTTP content encoding
+func WithHTTPContentEncoding(encoding string) MessageOption {
+	return func(m *Message) { m.httpContentEncoding = encoding }
+}
+
+// WithPriority sets the message priority (0-255)
+func WithPriority(priority int) MessageOption {
+	return func(m *Message) {
+		m.priority = priority
+		m.hasPriority = true
+	}
+}
+
+// WithClassOfService sets the class of service (0-2)
+func WithClassOfService(cos int) MessageOption {
+	return func(m *Message) { m.classOfService = cos }
+}
+
+// WithExpiration sets the expiration time
+func WithExpiration(t time.Time) MessageOption {
+	return func(m *Message) { m.expiration = t }
+}
+
+// NewMessage creates a message that was not published through a broker, for
+// example to call a message handler directly.
+func NewMessage(destination string, payload []byte, opts ...MessageOption) *Message {
+	m := &Message{
+		payload:     payload,
+		destination: destination,
+		timestamp:   time.Now(),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// clone returns a copy for a new delivery
+func (m *Message) clone() *Message {
+	c := *m
+	if m.properties != nil {
+		c.properties = make(sdt.Map, len(m.properties))
+		for k, v := range m.properties {
+			c.properties[k] = v
+		}
+	}
+	c.disposed = false
+	return &c
+}
+
+// GetProperties returns the user properties
+func (m *Message) GetProperties() sdt.Map {
+	return m.properties
+}
+
+// GetProperty returns a user property
+func (m *Message) GetProperty(key string) (sdt.Data, bool) {
+	v, ok := m.properties[key]
+	return v, ok
+}
+
+// HasProperty checks if the message has a user property with the given name
+func (m *Message) HasProperty(key string) bool {
+	_, ok := m.properties[key]
+	return ok
+}
+
+// GetPayloadAsBytes returns the binary payload
+func (m *Message) GetPayloadAsBytes() ([]byte, bool) {
+	return m.payload, m.payload != nil
+}
+
+// GetPayloadAsString returns the payload as string
+func (m *Message) GetPayloadAsString() (string, bool) {
+	return string(m.payload), m.payload != nil
+}
+
+// GetPayloadAsMap is not supported for messages of the fake broker
+func (m *Message) GetPayloadAsMap() (sdt.Map, bool) {
+	return nil, false
+}
+
+// GetPayloadAsStream is not supported for messages of the fake broker
+func (m *Message) GetPayloadAsStream() (sdt.Stream, bool) {
+	return nil, false
+}
+
+// GetCorrelationID returns the correlation ID
+func (m *Message) GetCorrelationID() (string, bool) {
+	return m.correlationID, m.correlationID != ""
+}
+
+// GetExpiration returns the expiration time
+func (m *Message) GetExpiration() time.Time {
+	return m.expiration
+}
+
+// GetSequenceNumber returns the sequence number assigned by the broker
+func (m *Message) GetSequenceNumber() (int64, bool) {
+	return m.sequenceNumber, m.sequenceNumber != 0
+}
+
+// GetPriority returns the message priority
+func (m *Message) GetPriority() (int, bool) {
+	return m.priority, m.hasPriority
+}
+
+// GetHTTPContentType returns the HTTP content type
+func (m *Message) GetHTTPContentType() (string, bool) {
+	return m.httpContentType, m.httpContentType != ""
+}
+
+// GetHTTPContentEncoding returns the HTTP content encoding
+func (m *Message) GetHTTPContentEncoding() (string, bool) {
+	return m.httpContentEncoding, m.httpContentEncoding != ""
+}
+
+// GetApplicationMessageID returns the application message ID
+func (m *Message) GetApplicationMessageID() (string, bool) {
+	return m.applicationMessageID, m.applicationMessageID != ""
+}
+
+// GetApplicationMessageType returns the application message type
+func (m *Message) GetApplicationMessageType() (string, bool) {
+	return m.applicationType, m.applicationType != ""
+}
+
+// GetClassOfService returns the class of service
+func (m *Message) GetClassOfService() int {
+	return m.classOfService
+}
+
+// GetDestinationName returns the topic or queue the message was published to
+func (m *Message) GetDestinationName() string {
+	return m.destination
+}
+
+// GetTimeStamp returns the receive timestamp
+func (m *Message) GetTimeStamp() (time.Time, bool) {
+	return m.timestamp, !m.timestamp.IsZero()
+}
+
+// GetSenderTimestamp returns the sender timestamp
+func (m *Message) GetSenderTimestamp() (time.Time, bool) {
+	return m.senderTimestamp, !m.senderTimestamp.IsZero()
+}
+
+// GetSenderID returns the sender ID
+func (m *Message) GetSenderID() (string, bool) {
+	return m.senderID, m.senderID != ""
+}
+
+// GetReplicationGroupMessageID returns the ID the broker assigned when spooling
+func (m *Message) GetReplicationGroupMessageID() (rgmid.ReplicationGroupMessageID, bool) {
+	if m.rgmid == 0 {
+		return nil, false
+	}
+	return m.rgmid, true
+}
+
+// GetMessageDiscardNotification reports that no messages were discarded
+func (m *Message) GetMessageDiscardNotification() message.MessageDiscardNotification {
+	return discardNotification{}
+}
+
+// IsRedelivered checks if the message has been delivered before
+func (m *Message) IsRedelivered() bool {
+	return m.redelivered
+}
+
+// GetCacheRequestID returns false; the fake broker has no cache
+func (m *Message) GetCacheRequestID() (message.CacheRequestID, bool) {
+	return 0, false
+}
+
+// GetCacheStatus reports a live message
+func (m *Message) GetCacheStatus() message.CacheStatus {
+	return message.Live
+}
+
+// Dispose marks the message as disposed
+func (m *Message) Dispose() {
+	m.disposed = true
+}
+
+// IsDisposed checks if the message has been disposed
+func (m *Message) IsDisposed() bool {
+	return m.disposed
+}
+
+// String returns a short description of the message
+func (m *Message) String() string {
+	return fmt.Sprintf("solacetest.Message{destination: %q, rgmid: %s, redelivered: %t, payload: %d bytes}",
+		m.destination, m.rgmid, m.redelivered, len(m.payload))
+}
+
+type discardNotification struct{}
+
+func (discardNotification) HasBrokerDiscardIndication() bool   { return false }
+func (discardNotification) HasInternalDiscardIndication() bool { return false }
+
+// ReplicationGroupMessageID is the spool sequence number of a message on the fake broker
+type ReplicationGroupMessageID uint64
+
+const rgmidPrefix = "rmid1:0f4a0-5ce5e7f0c7a-00000000-"
+
+// String returns the ID in the textual form used by Solace brokers
+func (id ReplicationGroupMessageID) String() string {
+	return fmt.Sprintf("%s%08x", rgmidPrefix, uint64(id))
+}
+
+// Compare compares the ID with another one issued by the fake broker
+func (id ReplicationGroupMessageID) Compare(other rgmid.ReplicationGroupMessageID) (int, error) {
+	o, err := ParseReplicationGroupMessageID(other.String())
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case id < o:
+		return -1, nil
+	case id > o:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// ParseReplicationGroupMessageID parses the textual form of an ID issued by the fake broker
+func ParseReplicationGroupMessageID(s string) (ReplicationGroupMessageID, error) {
+	if !strings.HasPrefix(s, rgmidPrefix) {
+		return 0, fmt.Errorf("replication group message ID %q was not issued by this broker", s)
+	}
+	seq, err := strconv.ParseUint(strings.TrimPrefix(s, rgmidPrefix), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid replication group message ID %q: %w", s, err)
+	}
+	return ReplicationGroupMessageID(seq), nil
+}
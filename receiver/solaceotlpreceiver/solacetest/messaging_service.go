@@ -0,0 +1,138 @@
+package solacetest
+
+import (
+	"solace.dev/go/messaging/pkg/solace"
+)
+
+// MessagingService is a fake solace.MessagingService bound to a Broker.
+// It supports connecting, disconnecting, interruption listeners and
+// persistent message receivers; other features of the SDK interface panic.
+type MessagingService struct {
+	solace.MessagingService
+
+	broker                *Broker
+	connected             bool
+	connectAttempts       int
+	receivers             []*PersistentMessageReceiver
+	nextListenerID        uint64
+	interruptionListeners map[uint64]solace.ServiceInterruptionListener
+}
+
+// Connect connects to the broker unless it is unreachable
+func (s *MessagingService) Connect() error {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.connectAttempts++
+	if s.broker.unreachable {
+		return solace.NewError(&solace.ServiceUnreachableError{}, "broker unreachable", nil)
+	}
+	s.connected = true
+	return nil
+}
+
+// ConnectAsync connects to the broker asynchronously
+func (s *MessagingService) ConnectAsync() <-chan error {
+	result := make(chan error, 1)
+	go func() { result <- s.Connect() }()
+	return result
+}
+
+// ConnectAsyncWithCallback connects to the broker and calls callback with the result
+func (s *MessagingService) ConnectAsyncWithCallback(callback func(solace.MessagingService, error)) {
+	go func() { callback(s, s.Connect()) }()
+}
+
+// Disconnect terminates all receivers of the service and disconnects it
+func (s *MessagingService) Disconnect() error {
+	s.broker.mu.Lock()
+	receivers := append([]*PersistentMessageReceiver(nil), s.receivers...)
+	s.broker.mu.Unlock()
+	for _, r := range receivers {
+		_ = r.Terminate(0)
+	}
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.connected = false
+	return nil
+}
+
+// DisconnectAsync disconnects asynchronously
+func (s *MessagingService) DisconnectAsync() <-chan error {
+	result := make(chan error, 1)
+	go func() { result <- s.Disconnect() }()
+	return result
+}
+
+// DisconnectAsyncWithCallback disconnects and calls callback with the result
+func (s *MessagingService) DisconnectAsyncWithCallback(callback func(error)) {
+	go func() { callback(s.Disconnect()) }()
+}
+
+// IsConnected reports whether the service is connected
+func (s *MessagingService) IsConnected() bool {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	return s.connected
+}
+
+// ConnectAttempts returns how often Connect was called
+func (s *MessagingService) ConnectAttempts() int {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	return s.connectAttempts
+}
+
+// CreatePersistentMessageReceiverBuilder creates a builder for queue receivers
+func (s *MessagingService) CreatePersistentMessageReceiverBuilder() solace.PersistentMessageReceiverBuilder {
+	return &PersistentMessageReceiverBuilder{service: s}
+}
+
+// AddServiceInterruptionListener registers a listener for Broker.Interrupt
+func (s *MessagingService) AddServiceInterruptionListener(listener solace.ServiceInterruptionListener) uint64 {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.nextListenerID++
+	s.interruptionListeners[s.nextListenerID] = listener
+	return s.nextListenerID
+}
+
+// RemoveServiceInterruptionListener removes a listener
+func (s *MessagingService) RemoveServiceInterruptionListener(listenerID uint64) {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	delete(s.interruptionListeners, listenerID)
+}
+
+// AddReconnectionListener accepts a listener; the fake never reconnects on its own
+func (s *MessagingService) AddReconnectionListener(solace.ReconnectionListener) uint64 {
+	return 0
+}
+
+// AddReconnectionAttemptListener accepts a listener; the fake never reconnects on its own
+func (s *MessagingService) AddReconnectionAttemptListener(solace.ReconnectionAttemptListener) uint64 {
+	return 0
+}
+
+// RemoveReconnectionListener is a no-op
+func (s *MessagingService) RemoveReconnectionListener(uint64) {}
+
+// RemoveReconnectionAttemptListener is a no-op
+func (s *MessagingService) RemoveReconnectionAttemptListener(uint64) {}
+
+// GetApplicationID returns the client name of the fake service
+func (s *MessagingService) GetApplicationID() string {
+	return "solacetest"
+}
+
+// notifyInterruption calls the interruption listeners
+func (s *MessagingService) notifyInterruption(event solace.ServiceEvent) {
+	s.broker.mu.Lock()
+	listeners := make([]solace.ServiceInterruptionListener, 0, len(s.interruptionListeners))
+	for _, listener := range s.interruptionListeners {
+		listeners = append(listeners, listener)
+	}
+	s.broker.mu.Unlock()
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
@@ -0,0 +1,541 @@
+package solacetest
+
+import (
+	"sort"
+	"time"
+
+	"solace.dev/go/messaging/pkg/solace"
+	"solace.dev/go/messaging/pkg/solace/config"
+	"solace.dev/go/messaging/pkg/solace/message"
+	"solace.dev/go/messaging/pkg/solace/message/rgmid"
+	"solace.dev/go/messaging/pkg/solace/resource"
+)
+
+// PersistentMessageReceiverBuilder is a fake solace.PersistentMessageReceiverBuilder
+type PersistentMessageReceiverBuilder struct {
+	service         *MessagingService
+	autoAck         bool
+	selector        string
+	replay          *config.ReplayStrategy
+	subscriptions   []resource.Subscription
+	outcomes        []config.MessageSettlementOutcome
+	createQueue     bool
+	stateListener   solace.ReceiverStateChangeListener
+	configuredProps config.ReceiverPropertyMap
+}
+
+// Build creates a receiver bound to queue when it is started
+func (b *PersistentMessageReceiverBuilder) Build(q *resource.Queue) (solace.PersistentMessageReceiver, error) {
+	if q == nil {
+		return nil, solace.NewError(&solace.IllegalArgumentError{}, "queue must not be nil", nil)
+	}
+	r := &PersistentMessageReceiver{
+		service:       b.service,
+		queueName:     q.GetName(),
+		exclusive:     q.IsExclusivelyAccessible(),
+		autoAck:       b.autoAck,
+		selector:      b.selector,
+		replay:        b.replay,
+		subscriptions: append([]resource.Subscription(nil), b.subscriptions...),
+		outcomes:      map[config.MessageSettlementOutcome]bool{config.PersistentReceiverAcceptedOutcome: true},
+		createQueue:   b.createQueue,
+		unacked:       map[*Message]*Message{},
+	}
+	for _, outcome := range b.outcomes {
+		r.outcomes[outcome] = true
+	}
+	b.service.broker.mu.Lock()
+	b.service.receivers = append(b.service.receivers, r)
+	b.service.broker.mu.Unlock()
+	return r, nil
+}
+
+// WithActivationPassivationSupport stores the listener; the fake never passivates flows
+func (b *PersistentMessageReceiverBuilder) WithActivationPassivationSupport(listener solace.ReceiverStateChangeListener) solace.PersistentMessageReceiverBuilder {
+	b.stateListener = listener
+	return b
+}
+
+// WithMessageAutoAcknowledgement acknowledges messages after the handler returns
+func (b *PersistentMessageReceiverBuilder) WithMessageAutoAcknowledgement() solace.PersistentMessageReceiverBuilder {
+	b.autoAck = true
+	return b
+}
+
+// WithMessageClientAcknowledgement requires Ack or Settle calls (default)
+func (b *PersistentMessageReceiverBuilder) WithMessageClientAcknowledgement() solace.PersistentMessageReceiverBuilder {
+	b.autoAck = false
+	return b
+}
+
+// WithMessageSelector stores the selector expression; see Selector
+func (b *PersistentMessageReceiverBuilder) WithMessageSelector(selector string) solace.PersistentMessageReceiverBuilder {
+	b.selector = selector
+	return b
+}
+
+// WithMissingResourcesCreationStrategy creates the queue on start if requested
+func (b *PersistentMessageReceiverBuilder) WithMissingResourcesCreationStrategy(strategy config.MissingResourcesCreationStrategy) solace.PersistentMessageReceiverBuilder {
+	b.createQueue = strategy == config.PersistentReceiverCreateOnStartMissingResources
+	return b
+}
+
+// WithMessageReplay replays the queue's replay log when the receiver starts
+func (b *PersistentMessageReceiverBuilder) WithMessageReplay(strategy config.ReplayStrategy) solace.PersistentMessageReceiverBuilder {
+	b.replay = &strategy
+	return b
+}
+
+// WithSubscriptions adds topic subscriptions to the queue when the receiver starts
+func (b *PersistentMessageReceiverBuilder) WithSubscriptions(topics ...resource.Subscription) solace.PersistentMessageReceiverBuilder {
+	b.subscriptions = append(b.subscriptions, topics...)
+	return b
+}
+
+// WithRequiredMessageOutcomeSupport enables the FAILED and REJECTED settlement outcomes
+func (b *PersistentMessageReceiverBuilder) WithRequiredMessageOutcomeSupport(outcomes ...config.MessageSettlementOutcome) solace.PersistentMessageReceiverBuilder {
+	b.outcomes = append(b.outcomes, outcomes...)
+	return b
+}
+
+// FromConfigurationProvider stores the receiver properties
+func (b *PersistentMessageReceiverBuilder) FromConfigurationProvider(provider config.ReceiverPropertiesConfigurationProvider) solace.PersistentMessageReceiverBuilder {
+	if b.configuredProps == nil {
+		b.configuredProps = config.ReceiverPropertyMap{}
+	}
+	for k, v := range provider.GetConfiguration() {
+		b.configuredProps[k] = v
+	}
+	return b
+}
+
+type receiverState int
+
+const (
+	receiverNotStarted receiverState = iota
+	receiverRunning
+	receiverTerminating
+	receiverTerminated
+)
+
+// PersistentMessageReceiver is a fake solace.PersistentMessageReceiver
+type PersistentMessageReceiver struct {
+	service       *MessagingService
+	queueName     string
+	exclusive     bool
+	autoAck       bool
+	selector      string
+	replay        *config.ReplayStrategy
+	subscriptions []resource.Subscription
+	outcomes      map[config.MessageSettlementOutcome]bool
+	createQueue   bool
+
+	// guarded by the broker mutex
+	state               receiverState
+	paused              bool
+	queue               *queue
+	handler             solace.MessageHandler
+	dispatching         bool
+	dispatchDone        chan struct{}
+	unacked             map[*Message]*Message
+	terminationListener solace.TerminationNotificationListener
+}
+
+// Selector returns the message selector the receiver was built with
+func (r *PersistentMessageReceiver) Selector() string {
+	return r.selector
+}
+
+// Start binds the receiver to its queue
+func (r *PersistentMessageReceiver) Start() error {
+	b := r.service.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch r.state {
+	case receiverRunning:
+		return nil
+	case receiverTerminating, receiverTerminated:
+		return solace.NewError(&solace.IllegalStateError{}, "receiver has been terminated", nil)
+	}
+	if !r.service.connected {
+		return solace.NewError(&solace.IllegalStateError{}, "messaging service is not connected", nil)
+	}
+	q, ok := b.queues[r.queueName]
+	if !ok {
+		if !r.createQueue {
+			return solace.NewError(&solace.IllegalStateError{}, "unknown queue "+r.queueName, nil)
+		}
+		q = b.queueLocked(r.queueName)
+	}
+	for _, subscription := range r.subscriptions {
+		q.subscriptions = append(q.subscriptions, subscription.GetName())
+	}
+	if r.replay != nil {
+		if err := replayLocked(q, *r.replay); err != nil {
+			return err
+		}
+	}
+	r.queue = q
+	q.flows = append(q.flows, r)
+	r.state = receiverRunning
+	if r.handler != nil {
+		r.startDispatchLocked()
+	}
+	b.cond.Broadcast()
+	return nil
+}
+
+// StartAsync starts the receiver asynchronously
+func (r *PersistentMessageReceiver) StartAsync() <-chan error {
+	result := make(chan error, 1)
+	go func() { result <- r.Start() }()
+	return result
+}
+
+// StartAsyncCallback starts the receiver and calls callback with the result
+func (r *PersistentMessageReceiver) StartAsyncCallback(callback func(solace.PersistentMessageReceiver, error)) {
+	go func() { callback(r, r.Start()) }()
+}
+
+// Terminate stops delivery, waits up to gracePeriod for the running handler
+// and unbinds from the queue; unsettled messages are redelivered later.
+func (r *PersistentMessageReceiver) Terminate(gracePeriod time.Duration) error {
+	b := r.service.broker
+	b.mu.Lock()
+	if r.state != receiverRunning {
+		r.state = receiverTerminated
+		b.mu.Unlock()
+		return nil
+	}
+	r.state = receiverTerminating
+	done := r.dispatchDone
+	b.cond.Broadcast()
+	b.mu.Unlock()
+
+	var err error
+	if done != nil {
+		if gracePeriod < 0 {
+			<-done
+		} else {
+			select {
+			case <-done:
+			case <-time.After(gracePeriod):
+				err = solace.NewError(&solace.IncompleteMessageDeliveryError{},
+					"message handler did not return within the grace period", nil)
+			}
+		}
+	}
+
+	b.mu.Lock()
+	r.unbindLocked()
+	b.cond.Broadcast()
+	b.mu.Unlock()
+	r.notifyTermination(newEvent("receiver terminated", err))
+	return err
+}
+
+// TerminateAsync terminates the receiver asynchronously
+func (r *PersistentMessageReceiver) TerminateAsync(gracePeriod time.Duration) <-chan error {
+	result := make(chan error, 1)
+	go func() { result <- r.Terminate(gracePeriod) }()
+	return result
+}
+
+// TerminateAsyncCallback terminates the receiver and calls callback with the result
+func (r *PersistentMessageReceiver) TerminateAsyncCallback(gracePeriod time.Duration, callback func(error)) {
+	go func() { callback(r.Terminate(gracePeriod)) }()
+}
+
+// IsRunning reports whether the receiver is started
+func (r *PersistentMessageReceiver) IsRunning() bool {
+	r.service.broker.mu.Lock()
+	defer r.service.broker.mu.Unlock()
+	return r.state == receiverRunning
+}
+
+// IsTerminated reports whether the receiver is terminated
+func (r *PersistentMessageReceiver) IsTerminated() bool {
+	r.service.broker.mu.Lock()
+	defer r.service.broker.mu.Unlock()
+	return r.state == receiverTerminated
+}
+
+// IsTerminating reports whether the receiver is terminating
+func (r *PersistentMessageReceiver) IsTerminating() bool {
+	r.service.broker.mu.Lock()
+	defer r.service.broker.mu.Unlock()
+	return r.state == receiverTerminating
+}
+
+// SetTerminationNotificationListener registers a listener for unsolicited terminations
+func (r *PersistentMessageReceiver) SetTerminationNotificationListener(listener solace.TerminationNotificationListener) {
+	r.service.broker.mu.Lock()
+	defer r.service.broker.mu.Unlock()
+	r.terminationListener = listener
+}
+
+// ReceiveAsync delivers messages to callback on a dedicated goroutine, one at a time
+func (r *PersistentMessageReceiver) ReceiveAsync(callback solace.MessageHandler) error {
+	b := r.service.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if r.state == receiverTerminating || r.state == receiverTerminated {
+		return solace.NewError(&solace.IllegalStateError{}, "receiver has been terminated", nil)
+	}
+	r.handler = callback
+	if r.state == receiverRunning {
+		r.startDispatchLocked()
+	}
+	return nil
+}
+
+// ReceiveMessage waits up to timeout for the next message
+func (r *PersistentMessageReceiver) ReceiveMessage(timeout time.Duration) (message.InboundMessage, error) {
+	b := r.service.broker
+	deadline := time.Now().Add(timeout)
+	for {
+		b.mu.Lock()
+		if r.state == receiverTerminating || r.state == receiverTerminated {
+			b.mu.Unlock()
+			return nil, solace.NewError(&solace.IllegalStateError{}, "receiver has been terminated, no messages to receive", nil)
+		}
+		if m := r.nextLocked(); m != nil {
+			b.mu.Unlock()
+			if r.autoAck {
+				_ = r.Ack(m)
+			}
+			return m, nil
+		}
+		b.mu.Unlock()
+		if timeout >= 0 && time.Now().After(deadline) {
+			return nil, solace.NewError(&solace.TimeoutError{}, "timed out waiting for message on call to Receive", nil)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Ack settles a message as ACCEPTED
+func (r *PersistentMessageReceiver) Ack(msg message.InboundMessage) error {
+	return r.Settle(msg, config.PersistentReceiverAcceptedOutcome)
+}
+
+// Settle settles a delivered message. ACCEPTED removes it from the queue,
+// FAILED makes it available for redelivery and REJECTED removes it and
+// records it as rejected.
+func (r *PersistentMessageReceiver) Settle(msg message.InboundMessage, outcome config.MessageSettlementOutcome) error {
+	b := r.service.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !r.outcomes[outcome] {
+		return solace.NewError(&solace.IllegalArgumentError{},
+			"settlement outcome "+string(outcome)+" was not enabled on the receiver", nil)
+	}
+	delivered, ok := msg.(*Message)
+	if !ok {
+		return solace.NewError(&solace.IllegalArgumentError{}, "message was not delivered by this broker", nil)
+	}
+	spooled, ok := r.unacked[delivered]
+	if !ok {
+		return solace.NewError(&solace.IllegalStateError{}, "message is not pending settlement on this receiver", nil)
+	}
+	delete(r.unacked, delivered)
+	switch outcome {
+	case config.PersistentReceiverAcceptedOutcome:
+		r.queue.acked++
+	case config.PersistentReceiverFailedOutcome:
+		r.queue.requeue(spooled)
+	case config.PersistentReceiverRejectedOutcome:
+		r.queue.rejected = append(r.queue.rejected, spooled)
+	}
+	b.cond.Broadcast()
+	return nil
+}
+
+// Pause stops asynchronous delivery until Resume
+func (r *PersistentMessageReceiver) Pause() error {
+	r.service.broker.mu.Lock()
+	defer r.service.broker.mu.Unlock()
+	r.paused = true
+	return nil
+}
+
+// Resume continues asynchronous delivery
+func (r *PersistentMessageReceiver) Resume() error {
+	b := r.service.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r.paused = false
+	b.cond.Broadcast()
+	return nil
+}
+
+// ReceiverInfo describes the queue of the receiver
+func (r *PersistentMessageReceiver) ReceiverInfo() (solace.PersistentReceiverInfo, error) {
+	return receiverInfo{name: r.queueName}, nil
+}
+
+// AddSubscription adds a topic subscription to the queue
+func (r *PersistentMessageReceiver) AddSubscription(subscription resource.Subscription) error {
+	b := r.service.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	q := b.queueLocked(r.queueName)
+	q.subscriptions = append(q.subscriptions, subscription.GetName())
+	return nil
+}
+
+// RemoveSubscription removes a topic subscription from the queue
+func (r *PersistentMessageReceiver) RemoveSubscription(subscription resource.Subscription) error {
+	b := r.service.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	q := b.queueLocked(r.queueName)
+	for i, s := range q.subscriptions {
+		if s == subscription.GetName() {
+			q.subscriptions = append(q.subscriptions[:i], q.subscriptions[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// AddSubscriptionAsync adds a topic subscription and notifies listener
+func (r *PersistentMessageReceiver) AddSubscriptionAsync(subscription resource.Subscription, listener solace.SubscriptionChangeListener) error {
+	err := r.AddSubscription(subscription)
+	go listener(subscription, solace.SubscriptionAdded, err)
+	return nil
+}
+
+// RemoveSubscriptionAsync removes a topic subscription and notifies listener
+func (r *PersistentMessageReceiver) RemoveSubscriptionAsync(subscription resource.Subscription, listener solace.SubscriptionChangeListener) error {
+	err := r.RemoveSubscription(subscription)
+	go listener(subscription, solace.SubscriptionRemoved, err)
+	return nil
+}
+
+// startDispatchLocked starts the delivery goroutine once
+func (r *PersistentMessageReceiver) startDispatchLocked() {
+	if r.dispatching {
+		return
+	}
+	r.dispatching = true
+	r.dispatchDone = make(chan struct{})
+	go r.dispatch(r.dispatchDone)
+}
+
+// dispatch delivers messages to the handler until the receiver stops
+func (r *PersistentMessageReceiver) dispatch(done chan struct{}) {
+	defer close(done)
+	b := r.service.broker
+	for {
+		b.mu.Lock()
+		var m *Message
+		for m == nil {
+			if r.state != receiverRunning {
+				b.mu.Unlock()
+				return
+			}
+			if m = r.nextLocked(); m == nil {
+				b.cond.Wait()
+			}
+		}
+		handler := r.handler
+		b.mu.Unlock()
+
+		handler(m)
+		if r.autoAck {
+			_ = r.Ack(m)
+		}
+	}
+}
+
+// nextLocked takes the next deliverable message off the queue
+func (r *PersistentMessageReceiver) nextLocked() *Message {
+	if r.state != receiverRunning || r.paused || r.queue == nil || !r.queue.isActive(r) || len(r.queue.pending) == 0 {
+		return nil
+	}
+	spooled := r.queue.pending[0]
+	r.queue.pending = r.queue.pending[1:]
+	delivered := spooled.clone()
+	r.unacked[delivered] = spooled
+	return delivered
+}
+
+// unbindLocked detaches the receiver from its queue and requeues unsettled messages
+func (r *PersistentMessageReceiver) unbindLocked() {
+	r.state = receiverTerminated
+	if r.queue == nil {
+		return
+	}
+	for i, flow := range r.queue.flows {
+		if flow == r {
+			r.queue.flows = append(r.queue.flows[:i], r.queue.flows[i+1:]...)
+			break
+		}
+	}
+	returned := make([]*Message, 0, len(r.unacked))
+	for delivered, spooled := range r.unacked {
+		returned = append(returned, spooled)
+		delete(r.unacked, delivered)
+	}
+	// Redeliver in the original spool order
+	sort.Slice(returned, func(i, j int) bool { return returned[i].rgmid < returned[j].rgmid })
+	for i := len(returned) - 1; i >= 0; i-- {
+		r.queue.requeue(returned[i])
+	}
+}
+
+// notifyTermination calls the termination listener
+func (r *PersistentMessageReceiver) notifyTermination(event solace.TerminationEvent) {
+	r.service.broker.mu.Lock()
+	listener := r.terminationListener
+	r.service.broker.mu.Unlock()
+	if listener != nil {
+		listener(event)
+	}
+}
+
+// replayLocked replaces the queue content with messages from its replay log
+func replayLocked(q *queue, strategy config.ReplayStrategy) error {
+	var replayed []*Message
+	switch strategy.GetStrategy() {
+	case config.PersistentReplayAll:
+		replayed = q.log
+	case config.PersistentReplayTimeBased:
+		from, _ := strategy.GetData().(time.Time)
+		for _, m := range q.log {
+			if !m.timestamp.Before(from) {
+				replayed = append(replayed, m)
+			}
+		}
+	case config.PersistentReplayIDBased:
+		after, ok := strategy.GetData().(rgmid.ReplicationGroupMessageID)
+		if !ok {
+			return solace.NewError(&solace.MessageReplayError{}, "missing replication group message ID", nil)
+		}
+		id, err := ParseReplicationGroupMessageID(after.String())
+		if err != nil {
+			return solace.NewError(&solace.MessageReplayError{}, err.Error(), err)
+		}
+		for _, m := range q.log {
+			if m.rgmid > id {
+				replayed = append(replayed, m)
+			}
+		}
+	default:
+		return solace.NewError(&solace.MessageReplayError{}, "unsupported replay strategy "+strategy.GetStrategy(), nil)
+	}
+	q.pending = q.pending[:0]
+	for _, m := range replayed {
+		q.pending = append(q.pending, m.clone())
+	}
+	return nil
+}
+
+type receiverInfo struct {
+	name string
+}
+
+func (i receiverInfo) GetResourceInfo() solace.ResourceInfo { return i }
+func (i receiverInfo) GetName() string                      { return i.name }
+func (i receiverInfo) IsDurable() bool                      { return true }
@@ -0,0 +1,26 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/xextension/storage"
+)
+
+// storageClient returns a client of the storage extension id named name
+func (r *Receiver) storageClient(ctx context.Context, host component.Host, id component.ID, name string) (storage.Client, error) {
+	extension, ok := host.GetExtensions()[id]
+	if !ok {
+		return nil, fmt.Errorf("storage extension %q not found", id)
+	}
+	storageExtension, ok := extension.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("extension %q is not a storage extension", id)
+	}
+	client, err := storageExtension.GetClient(ctx, component.KindReceiver, r.settings.ID, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage client of %q: %w", id, err)
+	}
+	return client, nil
+}
@@ -0,0 +1,45 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pipeline"
+	"solace.dev/go/messaging/pkg/solace/message"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/internal/timestamps"
+)
+
+// correctTimestamps applies the timestamp policy to decoded logs, traces and
+// metrics and records the corrections
+func (r *Receiver) correctTimestamps(msg message.InboundMessage, receivedAt time.Time, data decoded) {
+	cfg := r.config.Timestamps
+	if !cfg.Enabled {
+		return
+	}
+	policy := timestamps.Policy{
+		Fill:      pcommon.NewTimestampFromTime(receivedAt),
+		Received:  pcommon.NewTimestampFromTime(receivedAt),
+		MaxFuture: cfg.MaxFuture,
+		MaxPast:   cfg.MaxPast,
+		Clamp:     cfg.OutOfRange == solaceconfig.OutOfRangeClamp,
+	}
+	if cfg.Fill != solaceconfig.FillReceive {
+		if sent, ok := msg.GetSenderTimestamp(); ok && !sent.IsZero() {
+			policy.Fill = pcommon.NewTimestampFromTime(sent)
+		}
+	}
+	switch data.signal {
+	case pipeline.SignalLogs:
+		policy.Logs(data.logs)
+	case pipeline.SignalTraces:
+		policy.Traces(data.traces)
+	case pipeline.SignalMetrics:
+		policy.Metrics(data.metrics)
+	}
+	for correction, n := range policy.Corrections {
+		r.telemetry.RecordTimestampCorrections(context.Background(), data.signal.String(), string(correction), int64(n))
+	}
+}
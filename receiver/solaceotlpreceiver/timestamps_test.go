@@ -0,0 +1,71 @@
+package solaceotlpreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	solaceconfig "github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/config"
+	"github.com/ThinkportRepo/opentelemetry-solace-otlp/receiver/solaceotlpreceiver/solacetest"
+)
+
+func TestHandleMessage_TimestampPolicy(t *testing.T) {
+	tel := componenttest.NewTelemetry()
+	t.Cleanup(func() { require.NoError(t, tel.Shutdown(context.Background())) })
+	settings := receivertest.NewNopSettings(typeStr)
+	settings.TelemetrySettings = tel.NewTelemetrySettings()
+	broker := newTestBroker()
+	cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+	cfg.Timestamps.Enabled = true
+	sink := new(consumertest.LogsSink)
+	r, err := NewReceiver(settings, cfg, sink, nil, nil, broker.NewMessagingService())
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+	sentAt := time.Now().Add(-time.Second).Truncate(time.Millisecond)
+	broker.Publish("otel/logs", []byte(`{"body": "no time"}`), solacetest.WithSenderTimestamp(sentAt))
+	require.Eventually(t, func() bool { return broker.Acked(testQueue) == 1 }, time.Second, time.Millisecond)
+	record := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, sentAt, record.Timestamp().AsTime().Local())
+	assert.NotZero(t, record.ObservedTimestamp())
+
+	got, err := tel.GetMetric("otelcol_receiver_solaceotlp_timestamp_corrections")
+	require.NoError(t, err)
+	corrections := map[string]int64{}
+	for _, dp := range got.Data.(metricdata.Sum[int64]).DataPoints {
+		correction, _ := dp.Attributes.Value("correction")
+		corrections[correction.AsString()] = dp.Value
+	}
+	assert.Equal(t, map[string]int64{"filled": 1, "filled_observed": 1}, corrections)
+}
+
+func TestConfigValidate_Timestamps(t *testing.T) {
+	for name, tc := range map[string]struct {
+		timestamps solaceconfig.TimestampsConfig
+		wantErr    bool
+	}{
+		"receive and clamp": {timestamps: solaceconfig.TimestampsConfig{Enabled: true, Fill: solaceconfig.FillReceive, OutOfRange: solaceconfig.OutOfRangeClamp}},
+		"unknown fill":      {timestamps: solaceconfig.TimestampsConfig{Fill: "now"}, wantErr: true},
+		"unknown handling":  {timestamps: solaceconfig.TimestampsConfig{OutOfRange: "drop"}, wantErr: true},
+		"negative bound":    {timestamps: solaceconfig.TimestampsConfig{MaxPast: -time.Hour}, wantErr: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := newTestConfig(solaceconfig.InitialConnectBlock)
+			cfg.Timestamps = tc.timestamps
+			err := cfg.Validate()
+			if tc.wantErr {
+				assert.ErrorContains(t, err, "timestamps: ")
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}